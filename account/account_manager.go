@@ -0,0 +1,273 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/service"
+	"github.com/gofiber/fiber/v2"
+
+	dom_account_repository "github.com/budimanlai/go-core/account/domain/repository"
+	dom_account_usecase "github.com/budimanlai/go-core/account/domain/usecase"
+	account_http "github.com/budimanlai/go-core/account/platform/http"
+	impl_account_repository "github.com/budimanlai/go-core/account/platform/repository"
+	impl_account_usecase "github.com/budimanlai/go-core/account/platform/usecase"
+	"github.com/budimanlai/go-core/security"
+
+	"github.com/budimanlai/go-core/auth"
+	dom_auth_usecase "github.com/budimanlai/go-core/auth/domain/usecase"
+	auth_http "github.com/budimanlai/go-core/auth/handler/http"
+	"github.com/budimanlai/go-core/region"
+)
+
+type AccountManagerDefaultImpl struct {
+	factory *base.Factory
+
+	// repository
+	UserRepo          dom_account_repository.UserRepository
+	AuditLogRepo      dom_account_repository.AuditLogRepository
+	StatusHistoryRepo dom_account_repository.StatusHistoryRepository
+
+	// usecase
+	UserUsecase  dom_account_usecase.UserUsecase
+	AdminUsecase dom_account_usecase.AdminUsecase
+
+	// UserSessionUsecase is the auth module usecase issuing the login tokens
+	UserSessionUsecase dom_auth_usecase.UserSessionUsecase
+
+	// PasswordPolicyUsecase is the auth module password policy applied to registrations, optional
+	PasswordPolicyUsecase dom_auth_usecase.PasswordPolicyUsecase
+
+	// handler
+	UserHandler  *account_http.UserHandler
+	AdminHandler *account_http.AdminHandler
+
+	// service
+	PasswordHasher     security.PasswordHasher
+	Mailer             service.SMTPMailService
+	VerificationConfig dom_account_usecase.VerificationConfig
+
+	// AvatarStorage stores the avatar thumbnails, the avatar route is registered only when set
+	AvatarStorage service.StorageService
+	AvatarConfig  dom_account_usecase.AvatarConfig
+
+	// DeletionConfig sets the grace and retention periods of user-initiated deletion, the purge job runs
+	// when its PurgeInterval is set
+	DeletionConfig dom_account_usecase.DeletionConfig
+
+	// SuspensionCheckInterval is how often expired suspensions are reactivated, defaults to 1 minute,
+	// a negative value disables the job
+	SuspensionCheckInterval time.Duration
+
+	// ImpersonationTTL is how long an admin can act as a user, defaults to 30 minutes
+	ImpersonationTTL time.Duration
+
+	// PersonalDataHandlers add the data of other modules to exports and deletions, the auth tables and the
+	// audit log are always covered
+	PersonalDataHandlers []dom_account_usecase.PersonalDataHandler
+
+	// RegionValidator checks the region IDs on register and profile updates, optional
+	RegionValidator dom_account_usecase.RegionValidator
+
+	// middleware
+	// PublicMiddleware is for register, login and email verification
+	PublicMiddleware fiber.Handler
+
+	// AppMiddleware identifies the calling app on login and on the user routes, so a session stays with its app
+	AppMiddleware fiber.Handler
+
+	// SelfMiddleware is for the "me" routes, it must set the auth context of the current user
+	SelfMiddleware fiber.Handler
+
+	// AdminMiddleware is for the routes managing any user
+	AdminMiddleware fiber.Handler
+
+	// DashboardMiddleware guards the dashboard routes of the app after the private middleware, it checks the
+	// dashboard access and the verification required by VerificationConfig. It is set by InitManager.
+	DashboardMiddleware fiber.Handler
+}
+
+func NewAccountManagerDefaultImpl(factory *base.Factory) *AccountManagerDefaultImpl {
+	return &AccountManagerDefaultImpl{
+		factory:                 factory,
+		PasswordHasher:          security.NewBcryptHasher(),
+		SuspensionCheckInterval: time.Minute,
+	}
+}
+
+// SetAuthManager shares the sessions, hasher, password policy and private middleware of an initialized auth manager
+func (m *AccountManagerDefaultImpl) SetAuthManager(authManager *auth.AuthManagerDefaultImpl) {
+	m.UserSessionUsecase = authManager.UserSessionUsecase
+	m.PasswordHasher = authManager.PasswordHasher
+	m.PasswordPolicyUsecase = authManager.PasswordPolicyUsecase
+	if m.AppMiddleware == nil {
+		m.AppMiddleware = authManager.AppMiddleware
+	}
+	if m.SelfMiddleware == nil {
+		m.SelfMiddleware = authManager.PrivateMiddleware
+	}
+}
+
+func (m *AccountManagerDefaultImpl) SetUserSessionUsecase(sessionUC dom_auth_usecase.UserSessionUsecase) {
+	m.UserSessionUsecase = sessionUC
+}
+
+func (m *AccountManagerDefaultImpl) SetPasswordPolicyUsecase(policy dom_auth_usecase.PasswordPolicyUsecase) {
+	m.PasswordPolicyUsecase = policy
+}
+
+func (m *AccountManagerDefaultImpl) SetPasswordHasher(hasher security.PasswordHasher) {
+	m.PasswordHasher = hasher
+}
+
+func (m *AccountManagerDefaultImpl) SetVerification(config dom_account_usecase.VerificationConfig, mailer service.SMTPMailService) {
+	m.VerificationConfig = config
+	m.Mailer = mailer
+}
+
+func (m *AccountManagerDefaultImpl) SetAvatarStorage(storage service.StorageService, config dom_account_usecase.AvatarConfig) {
+	m.AvatarStorage = storage
+	m.AvatarConfig = config
+}
+
+// SetRegionContainer validates the profile regions against the region module
+func (m *AccountManagerDefaultImpl) SetRegionContainer(container *region.RegionContainer) {
+	m.RegionValidator = impl_account_usecase.NewRegionValidatorImpl(container)
+}
+
+func (m *AccountManagerDefaultImpl) SetRegionValidator(validator dom_account_usecase.RegionValidator) {
+	m.RegionValidator = validator
+}
+
+func (m *AccountManagerDefaultImpl) SetDeletionConfig(config dom_account_usecase.DeletionConfig) {
+	m.DeletionConfig = config
+}
+
+func (m *AccountManagerDefaultImpl) AddPersonalDataHandler(handler dom_account_usecase.PersonalDataHandler) {
+	m.PersonalDataHandlers = append(m.PersonalDataHandlers, handler)
+}
+
+func (m *AccountManagerDefaultImpl) SetPublicMiddleware(middleware fiber.Handler) {
+	m.PublicMiddleware = middleware
+}
+
+func (m *AccountManagerDefaultImpl) SetSelfMiddleware(middleware fiber.Handler) {
+	m.SelfMiddleware = middleware
+}
+
+func (m *AccountManagerDefaultImpl) SetAdminMiddleware(middleware fiber.Handler) {
+	m.AdminMiddleware = middleware
+}
+
+func (m *AccountManagerDefaultImpl) InitManager() {
+	m.initContainer()
+	m.initUsecase()
+	m.initMiddleware()
+}
+
+func (m *AccountManagerDefaultImpl) initContainer() {
+	m.UserRepo = impl_account_repository.NewUserRepository(m.factory)
+	m.AuditLogRepo = impl_account_repository.NewAuditLogRepository(m.factory)
+	m.StatusHistoryRepo = impl_account_repository.NewStatusHistoryRepository(m.factory)
+}
+
+func (m *AccountManagerDefaultImpl) initUsecase() {
+	m.UserUsecase = impl_account_usecase.NewUserUsecase(m.factory.DB, m.UserRepo, m.StatusHistoryRepo, m.PasswordHasher, m.UserSessionUsecase)
+	if m.Mailer != nil {
+		m.UserUsecase.SetVerification(m.VerificationConfig, m.Mailer)
+	}
+	if m.AvatarStorage != nil {
+		m.UserUsecase.SetAvatarStorage(m.AvatarStorage, m.AvatarConfig)
+	}
+	if m.RegionValidator != nil {
+		m.UserUsecase.SetRegionValidator(m.RegionValidator)
+	}
+	if m.PasswordPolicyUsecase != nil {
+		m.UserUsecase.SetPasswordPolicy(m.PasswordPolicyUsecase)
+	}
+	// the auth module logins (password, OTP, magic link, OAuth) share the sessions, so they share the rule
+	if m.VerificationConfig.RequireVerifiedForLogin && m.UserSessionUsecase != nil {
+		m.UserSessionUsecase.SetRequireVerified(true)
+	}
+
+	m.UserUsecase.SetDeletionConfig(m.DeletionConfig)
+	m.UserUsecase.AddPersonalDataHandler(impl_account_usecase.NewAuthPersonalDataImpl(m.factory.DB))
+	m.UserUsecase.AddPersonalDataHandler(impl_account_usecase.NewAuditPersonalDataImpl(m.AuditLogRepo))
+	m.UserUsecase.AddPersonalDataHandler(impl_account_usecase.NewStatusHistoryPersonalDataImpl(m.StatusHistoryRepo))
+	for _, handler := range m.PersonalDataHandlers {
+		m.UserUsecase.AddPersonalDataHandler(handler)
+	}
+	m.UserUsecase.StartPurgeJob(context.Background())
+	m.UserUsecase.StartSuspensionJob(context.Background(), m.SuspensionCheckInterval)
+
+	m.AdminUsecase = impl_account_usecase.NewAdminUsecase(m.factory.DB, m.UserUsecase, m.UserRepo, m.AuditLogRepo,
+		m.UserSessionUsecase)
+	m.AdminUsecase.SetImpersonationTTL(m.ImpersonationTTL)
+}
+
+func (m *AccountManagerDefaultImpl) initMiddleware() {
+	next := func(c *fiber.Ctx) error { return c.Next() }
+	if m.PublicMiddleware == nil {
+		m.PublicMiddleware = next
+	}
+	if m.AppMiddleware == nil {
+		m.AppMiddleware = next
+	}
+
+	// the "me" and admin routes are never left open
+	deny := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusUnauthorized) }
+	if m.SelfMiddleware == nil {
+		m.SelfMiddleware = deny
+	}
+	if m.AdminMiddleware == nil {
+		m.AdminMiddleware = deny
+	}
+
+	m.DashboardMiddleware = account_http.NewDashboardMiddleware(m.UserUsecase)
+}
+
+func (m *AccountManagerDefaultImpl) SetRoute(app fiber.Router) {
+	m.UserHandler = account_http.NewUserHandler(m.UserUsecase)
+
+	// Self-service registration and login
+	publicAPI := app.Group("/account")
+	publicAPI.Post("/register", m.PublicMiddleware, m.UserHandler.Register)
+	publicAPI.Post("/login", m.PublicMiddleware, m.AppMiddleware, m.UserHandler.Login)
+	publicAPI.Get("/verify", m.PublicMiddleware, m.UserHandler.VerifyEmail)
+	publicAPI.Post("/verify/resend", m.PublicMiddleware, m.UserHandler.ResendVerification)
+
+	// Current user, an admin impersonating the user cannot change, export or delete the account
+	deny := auth_http.DenyImpersonation
+	selfAPI := app.Group("/account/me", m.AppMiddleware, m.SelfMiddleware)
+	selfAPI.Get("/", m.UserHandler.Me)
+	selfAPI.Patch("/", deny, m.UserHandler.UpdateMe)
+	if m.AvatarStorage != nil {
+		selfAPI.Post("/avatar", m.UserHandler.UploadAvatar)
+	}
+	selfAPI.Get("/export", deny, m.UserHandler.ExportData)
+	selfAPI.Post("/delete", deny, m.UserHandler.RequestDeletion)
+	selfAPI.Post("/delete/cancel", deny, m.UserHandler.CancelDeletion)
+
+	// User management, every change is audited
+	m.AdminHandler = account_http.NewAdminHandler(m.AdminUsecase)
+	adminAPI := app.Group("/accounts", m.AppMiddleware, m.AdminMiddleware, auth_http.DenyImpersonation)
+	adminAPI.Get("/", m.AdminHandler.List)
+	adminAPI.Get("/export", m.AdminHandler.ExportCSV)
+	adminAPI.Get("/audit-logs", m.AdminHandler.AuditLogs)
+	adminAPI.Post("/bulk/status", m.AdminHandler.BulkStatus)
+	adminAPI.Delete("/impersonations/:session_id", m.AdminHandler.StopImpersonation)
+	adminAPI.Get("/:id", m.AdminHandler.GetByID)
+	adminAPI.Put("/:id", m.AdminHandler.Update)
+	adminAPI.Delete("/:id", m.AdminHandler.Delete)
+	adminAPI.Get("/:id/audit-logs", m.AdminHandler.AuditLogs)
+	adminAPI.Get("/:id/status-history", m.AdminHandler.StatusHistory)
+	adminAPI.Post("/:id/activate", m.AdminHandler.Activate)
+	adminAPI.Post("/:id/deactivate", m.AdminHandler.Deactivate)
+	adminAPI.Post("/:id/suspend", m.AdminHandler.Suspend)
+	adminAPI.Post("/:id/dashboard/enable", m.AdminHandler.EnableDashboard)
+	adminAPI.Post("/:id/dashboard/disable", m.AdminHandler.DisableDashboard)
+	if m.UserSessionUsecase != nil {
+		adminAPI.Post("/:id/impersonate", m.AdminHandler.Impersonate)
+	}
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditActionUpdate           = "update"
+	AuditActionDelete           = "delete"
+	AuditActionActivate         = "activate"
+	AuditActionDeactivate       = "deactivate"
+	AuditActionSuspend          = "suspend"
+	AuditActionDashboardEnable  = "dashboard_enable"
+	AuditActionDashboardDisable = "dashboard_disable"
+	AuditActionExport           = "export"
+	AuditActionImpersonate      = "impersonate"
+	AuditActionImpersonateEnd   = "impersonate_end"
+)
+
+// Actor is the admin performing an action
+type Actor struct {
+	UserID uint
+	IP     string
+}
+
+// AuditLog records an admin action on a user
+type AuditLog struct {
+	ID       uint
+	ActorID  uint
+	ActorIP  string
+	Action   string
+	TargetID uint
+	Reason   string
+	// Changes is the JSON encoded request of the action
+	Changes   string
+	CreatedAt time.Time
+}
@@ -5,33 +5,39 @@ import (
 )
 
 type User struct {
-	ID                   uint
-	Username             string
-	AuthKey              string
-	PasswordHash         string
-	PasswordResetToken   *string
-	Email                string
-	Fullname             string
-	Handphone            string
-	Dob                  *time.Time
-	Gender               string
-	Status               string
-	MainRole             *string
-	LoginDashboard       string
-	Avatar               *string
-	Address              *string
-	Zipcode              string
-	DistrictID           uint
-	SubdistrictID        uint
-	CityID               uint
-	ProvinceID           uint
-	CountryID            string
-	CreatedAt            time.Time
-	CreatedBy            uint
-	UpdatedAt            time.Time
-	UpdatedBy            uint
-	VerificationToken    *string
-	DeletedAt            *time.Time
+	ID                 uint
+	Username           string
+	AuthKey            string
+	PasswordHash       string
+	PasswordResetToken *string
+	Email              string
+	Fullname           string
+	Handphone          string
+	Dob                *time.Time
+	Gender             string
+	Status             string
+	// SuspendedUntil reactivates a suspended user once passed, nil suspends indefinitely
+	SuspendedUntil     *time.Time
+	MainRole           *string
+	LoginDashboard     string
+	Avatar             *string
+	Address            *string
+	Zipcode            string
+	DistrictID         uint
+	SubdistrictID      uint
+	CityID             uint
+	ProvinceID         uint
+	CountryID          string
+	CreatedAt          time.Time
+	CreatedBy          uint
+	UpdatedAt          time.Time
+	UpdatedBy          uint
+	VerificationToken  *string
+	VerificationSentAt *time.Time
+	// DeletionScheduledAt is when a user requested deletion becomes final
+	DeletionScheduledAt *time.Time
+	AnonymizedAt        *time.Time
+	DeletedAt           *time.Time
 }
 
 // IsDeleted checks if the user has been soft deleted
@@ -41,25 +47,7 @@ func (u *User) IsDeleted() bool {
 
 // IsActive checks if the user is active
 func (u *User) IsActive() bool {
-	return u.Status == "active"
-}
-
-// Activate changes user status to active
-func (u *User) Activate() {
-	u.Status = "active"
-	u.UpdatedAt = time.Now()
-}
-
-// Deactivate changes user status to inactive
-func (u *User) Deactivate() {
-	u.Status = "inactive"
-	u.UpdatedAt = time.Now()
-}
-
-// Suspend changes user status to suspended
-func (u *User) Suspend() {
-	u.Status = "suspended"
-	u.UpdatedAt = time.Now()
+	return u.Status == StatusActive
 }
 
 // SoftDelete marks user as deleted
@@ -96,3 +84,18 @@ func (u *User) ClearVerificationToken() {
 	u.VerificationToken = nil
 	u.UpdatedAt = time.Now()
 }
+
+// IsDeletionScheduled checks if the user asked for the account to be deleted
+func (u *User) IsDeletionScheduled() bool {
+	return u.DeletionScheduledAt != nil
+}
+
+// IsDeletionDue checks if the grace period of the requested deletion has passed and the user is not anonymized yet
+func (u *User) IsDeletionDue(now time.Time) bool {
+	return u.DeletionScheduledAt != nil && !u.DeletionScheduledAt.After(now) && u.AnonymizedAt == nil
+}
+
+// IsPurgeDue checks if the user was anonymized longer than the retention period ago
+func (u *User) IsPurgeDue(now time.Time, retention time.Duration) bool {
+	return u.AnonymizedAt != nil && u.AnonymizedAt.Before(now.Add(-retention))
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// User statuses
+const (
+	StatusPendingVerification = "pending_verification"
+	StatusActive              = "active"
+	StatusSuspended           = "suspended"
+	StatusInactive            = "inactive"
+	StatusDeleted             = "deleted"
+)
+
+// SystemActorID is the actor of the status changes made by the system, such as an expired suspension
+const SystemActorID uint = 0
+
+// statusTransitions lists the statuses a user can move to from each status, deleted is final
+var statusTransitions = map[string][]string{
+	StatusPendingVerification: {StatusActive, StatusSuspended, StatusInactive, StatusDeleted},
+	StatusActive:              {StatusSuspended, StatusInactive, StatusDeleted},
+	StatusSuspended:           {StatusActive, StatusInactive, StatusDeleted},
+	StatusInactive:            {StatusActive, StatusDeleted},
+}
+
+var ErrStatusReasonRequired = errors.New("a reason is required to change the user status")
+
+// StatusHistory records a status change of a user
+type StatusHistory struct {
+	ID             uint
+	UserID         uint
+	FromStatus     string
+	ToStatus       string
+	Reason         string
+	ActorID        uint
+	SuspendedUntil *time.Time
+	CreatedAt      time.Time
+}
+
+// CanTransitionTo checks if the user can move to the status
+func (u *User) CanTransitionTo(status string) bool {
+	for _, allowed := range statusTransitions[u.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo moves the user to the status and returns the history row to store with it.
+// until only applies to suspensions and must be in the future.
+func (u *User) TransitionTo(status, reason string, actorID uint, until *time.Time, now time.Time) (*StatusHistory, error) {
+	if !u.CanTransitionTo(status) {
+		return nil, fmt.Errorf("cannot change user status from %s to %s", u.Status, status)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrStatusReasonRequired
+	}
+	if until != nil {
+		if status != StatusSuspended {
+			return nil, errors.New("only a suspension can have an end date")
+		}
+		if !until.After(now) {
+			return nil, errors.New("suspension end date must be in the future")
+		}
+	}
+
+	history := &StatusHistory{
+		UserID:         u.ID,
+		FromStatus:     u.Status,
+		ToStatus:       status,
+		Reason:         reason,
+		ActorID:        actorID,
+		SuspendedUntil: until,
+		CreatedAt:      now,
+	}
+
+	u.Status = status
+	u.SuspendedUntil = until
+	u.UpdatedAt = now
+	u.UpdatedBy = actorID
+	return history, nil
+}
+
+// IsSuspensionExpired checks if the user is suspended with an end date that has passed
+func (u *User) IsSuspensionExpired(now time.Time) bool {
+	return u.Status == StatusSuspended && u.SuspendedUntil != nil && !u.SuspendedUntil.After(now)
+}
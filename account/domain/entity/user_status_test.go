@@ -0,0 +1,85 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_TransitionTo(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Allowed transition", func(t *testing.T) {
+		user := entity.User{ID: 7, Status: entity.StatusActive}
+		until := now.Add(time.Hour)
+
+		history, err := user.TransitionTo(entity.StatusSuspended, " spam ", 1, &until, now)
+		require.NoError(t, err)
+		assert.Equal(t, entity.StatusSuspended, user.Status)
+		assert.Equal(t, &until, user.SuspendedUntil)
+		assert.Equal(t, uint(1), user.UpdatedBy)
+		assert.Equal(t, entity.StatusHistory{
+			UserID:         7,
+			FromStatus:     entity.StatusActive,
+			ToStatus:       entity.StatusSuspended,
+			Reason:         "spam",
+			ActorID:        1,
+			SuspendedUntil: &until,
+			CreatedAt:      now,
+		}, *history)
+
+		_, err = user.TransitionTo(entity.StatusActive, "appeal accepted", 1, nil, now)
+		require.NoError(t, err)
+		assert.Nil(t, user.SuspendedUntil)
+	})
+
+	t.Run("Rejected transitions", func(t *testing.T) {
+		cases := map[string]string{
+			entity.StatusInactive: entity.StatusSuspended,
+			entity.StatusActive:   entity.StatusPendingVerification,
+			entity.StatusDeleted:  entity.StatusActive,
+		}
+		for from, to := range cases {
+			user := entity.User{Status: from}
+			_, err := user.TransitionTo(to, "reason", 1, nil, now)
+			assert.Error(t, err, "%s to %s", from, to)
+			assert.Equal(t, from, user.Status)
+		}
+
+		user := entity.User{Status: entity.StatusActive}
+		_, err := user.TransitionTo(entity.StatusActive, "reason", 1, nil, now)
+		assert.Error(t, err, "same status")
+	})
+
+	t.Run("Reason is required", func(t *testing.T) {
+		user := entity.User{Status: entity.StatusActive}
+		_, err := user.TransitionTo(entity.StatusInactive, "  ", 1, nil, now)
+		assert.ErrorIs(t, err, entity.ErrStatusReasonRequired)
+	})
+
+	t.Run("End date", func(t *testing.T) {
+		past := now.Add(-time.Minute)
+		future := now.Add(time.Minute)
+
+		user := entity.User{Status: entity.StatusActive}
+		_, err := user.TransitionTo(entity.StatusSuspended, "reason", 1, &past, now)
+		assert.Error(t, err, "past end date")
+
+		_, err = user.TransitionTo(entity.StatusInactive, "reason", 1, &future, now)
+		assert.Error(t, err, "end date on a deactivation")
+	})
+}
+
+func TestUser_IsSuspensionExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	assert.True(t, (&entity.User{Status: entity.StatusSuspended, SuspendedUntil: &past}).IsSuspensionExpired(now))
+	assert.False(t, (&entity.User{Status: entity.StatusSuspended, SuspendedUntil: &future}).IsSuspensionExpired(now))
+	assert.False(t, (&entity.User{Status: entity.StatusSuspended}).IsSuspensionExpired(now))
+	assert.False(t, (&entity.User{Status: entity.StatusActive, SuspendedUntil: &past}).IsSuspensionExpired(now))
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/models"
+)
+
+type StatusHistoryRepository interface {
+	base.BaseRepository[entity.StatusHistory, models.UserStatusHistory]
+}
@@ -1,17 +1,12 @@
 package repository
 
 import (
+	"github.com/budimanlai/go-core/base"
+
 	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/models"
 )
 
 type UserRepository interface {
-	FindByEmail(email string) (*entity.User, error)
-	FindByUsername(username string) (*entity.User, error)
-	FindByHandphone(handphone string) (*entity.User, error)
-	FindByID(id uint) (*entity.User, error)
-	FindByVerificationToken(token string) (*entity.User, error)
-	Create(user *entity.User) error
-	Update(user *entity.User) error
-	Delete(id uint) error
-	List(limit, offset int) ([]*entity.User, int64, error)
+	base.BaseRepository[entity.User, models.User]
 }
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/dto"
+)
+
+// AdminUsecase manages any user on behalf of an admin, every change is written to the audit log
+type AdminUsecase interface {
+	// Search lists the users matching the filters
+	Search(ctx context.Context, req *dto.UserSearchRequest) (*dto.ListUserResponse, error)
+
+	// ExportCSV writes all the users matching the filters as CSV
+	ExportCSV(ctx context.Context, actor entity.Actor, req *dto.UserSearchRequest, w io.Writer) error
+
+	GetUser(ctx context.Context, id uint) (*dto.UserResponse, error)
+	UpdateUser(ctx context.Context, actor entity.Actor, id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
+	DeleteUser(ctx context.Context, actor entity.Actor, id uint, reason string) error
+
+	// SetStatus applies the action (activate, deactivate or suspend) to the user
+	SetStatus(ctx context.Context, actor entity.Actor, id uint, action string, req *dto.AdminActionRequest) error
+
+	// BulkSetStatus applies the action to each user, a failure does not stop the others
+	BulkSetStatus(ctx context.Context, actor entity.Actor, req *dto.BulkStatusRequest) (*dto.BulkActionResponse, error)
+
+	SetDashboardAccess(ctx context.Context, actor entity.Actor, id uint, enabled bool, reason string) error
+
+	// Impersonate issues a session of the user to the admin, it expires after the impersonation TTL
+	Impersonate(ctx context.Context, actor entity.Actor, id uint, req *dto.ImpersonateRequest) (*dto.ImpersonateResponse, error)
+
+	// StopImpersonation revokes an impersonation session before it expires
+	StopImpersonation(ctx context.Context, actor entity.Actor, sessionID int, reason string) error
+
+	// SetImpersonationTTL sets how long an impersonation session lasts, defaults to 30 minutes
+	SetImpersonationTTL(ttl time.Duration)
+
+	StatusHistory(ctx context.Context, id uint, page, pageSize int) (*dto.ListStatusHistoryResponse, error)
+
+	// ListAuditLogs lists the audit log, of one user when targetID is not zero, newest first
+	ListAuditLogs(ctx context.Context, targetID uint, page, pageSize int) (*dto.ListAuditLogResponse, error)
+}
@@ -1,22 +1,163 @@
 package usecase
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/budimanlai/go-core/account/domain/entity"
 	"github.com/budimanlai/go-core/account/dto"
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/service"
+
+	auth_usecase "github.com/budimanlai/go-core/auth/domain/usecase"
 )
 
+// VerificationConfig controls the verification emails and what unverified users may do.
+type VerificationConfig struct {
+	// Secret signs the verification tokens
+	Secret string
+
+	// URL is the page receiving the verification link, the token is added as the "token" query parameter
+	URL string
+
+	// Template is the email template name, defaults to "email_verification"
+	Template string
+
+	// TTL is how long a verification link stays valid, defaults to 24 hours
+	TTL time.Duration
+
+	// ResendInterval is the minimum time between two verification emails, defaults to 1 minute
+	ResendInterval time.Duration
+
+	// RequireVerifiedForLogin rejects logins from users who have not verified their email
+	RequireVerifiedForLogin bool
+
+	// RequireVerifiedForDashboard denies dashboard access to users who have not verified their email
+	RequireVerifiedForDashboard bool
+}
+
+// AvatarConfig controls the avatar uploads.
+type AvatarConfig struct {
+	// Sizes are the thumbnail sizes in pixels, User.Avatar points to the first one, defaults to 256 and 64
+	Sizes []int
+
+	// MaxFileSize is the maximum upload size in bytes, defaults to 5 MB
+	MaxFileSize int64
+
+	// Quality is the JPEG quality of the thumbnails, defaults to 85
+	Quality int
+
+	// Prefix is prepended to the storage keys, defaults to "avatars"
+	Prefix string
+}
+
+// RegionValidator checks that the region IDs of a profile exist and belong to each other, zero IDs are skipped.
+type RegionValidator interface {
+	ValidateRegion(ctx context.Context, countryID string, provinceID, cityID, districtID, subdistrictID uint) error
+}
+
+// DeletionConfig controls the user-initiated account deletion.
+type DeletionConfig struct {
+	// GracePeriod is how long the user can cancel the deletion, defaults to 30 days
+	GracePeriod time.Duration
+
+	// RetentionPeriod is how long the anonymized user row is kept before it is hard deleted, defaults to 0
+	// which hard deletes it on the next purge run
+	RetentionPeriod time.Duration
+
+	// PurgeInterval runs the purge job periodically when set
+	PurgeInterval time.Duration
+
+	// Template is the email template confirming the request, no email is sent when empty
+	Template string
+}
+
+// PersonalDataHandler exports and erases the personal data another module stores about a user,
+// so deletions and exports cover every table.
+type PersonalDataHandler interface {
+	// Name is the section name in the export
+	Name() string
+
+	// Export returns the user data, it is serialized to JSON
+	Export(ctx context.Context, user *entity.User) (interface{}, error)
+
+	// Anonymize erases the personal data when the grace period ends, rows may be kept without it
+	Anonymize(ctx context.Context, user *entity.User) error
+
+	// Delete removes the remaining rows when the user row is hard deleted
+	Delete(ctx context.Context, userID uint) error
+}
+
 type UserUsecase interface {
-	Register(req *dto.RegisterRequest) (interface{}, error)
-	Login(req *dto.LoginRequest) (*dto.LoginResponse, error)
-	GetByID(id uint) (*dto.UserResponse, error)
-	Update(id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
-	Delete(id uint) error
-	List(page, pageSize int) (*dto.ListUserResponse, error)
-	Activate(id uint) error
-	Deactivate(id uint) error
-	Suspend(id uint) error
-	VerifyEmail(token string) error
-	EnableDashboard(id uint) error
-	DisableDashboard(id uint) error
+	base.BaseUsecase[entity.User]
+
+	Register(ctx context.Context, req *dto.RegisterRequest) (interface{}, error)
+
+	// Login checks the password and issues the token through the auth module sessions
+	Login(ctx context.Context, req *dto.LoginRequest, appID int, fromIP, userAgent string) (*dto.LoginResponse, error)
+
+	GetByID(ctx context.Context, id uint) (*dto.UserResponse, error)
+	UpdateProfile(ctx context.Context, id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
+
+	// DeleteUser moves the user to the deleted status and soft deletes it
+	DeleteUser(ctx context.Context, actorID, id uint, reason string) error
+
+	List(ctx context.Context, page, pageSize int) (*dto.ListUserResponse, error)
+
+	// ChangeStatus moves the user to the status if the transition is allowed and records it in the status
+	// history, leaving the active status revokes the sessions. until only applies to suspensions.
+	ChangeStatus(ctx context.Context, actorID, id uint, status, reason string, until *time.Time) error
+
+	// StatusHistory lists the status changes of the user, newest first
+	StatusHistory(ctx context.Context, id uint, page, pageSize int) (*dto.ListStatusHistoryResponse, error)
+
+	// ReactivateExpiredSuspensions reactivates the users whose suspension has ended
+	ReactivateExpiredSuspensions(ctx context.Context) (int, error)
+
+	// StartSuspensionJob runs ReactivateExpiredSuspensions every interval until the context is done
+	StartSuspensionJob(ctx context.Context, interval time.Duration)
+
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, req *dto.ResendVerificationRequest) error
+	EnableDashboard(ctx context.Context, id uint) error
+	DisableDashboard(ctx context.Context, id uint) error
+	CheckDashboardAccess(ctx context.Context, id uint) error
 	SetCustomResponse(customToResponse func(*entity.User) interface{})
+
+	// UploadAvatar stores the thumbnails of the uploaded image and points User.Avatar to the largest one
+	UploadAvatar(ctx context.Context, id uint, file io.Reader) (*dto.UserResponse, error)
+
+	// SetAvatarStorage enables avatar uploads to the given storage
+	SetAvatarStorage(storage service.StorageService, config AvatarConfig)
+
+	// RequestDeletion checks the password and schedules the deletion after the grace period
+	RequestDeletion(ctx context.Context, id uint, req *dto.DeleteAccountRequest) (*dto.DeleteAccountResponse, error)
+
+	// CancelDeletion cancels a scheduled deletion during the grace period
+	CancelDeletion(ctx context.Context, id uint) error
+
+	// PurgeAccounts anonymizes the users past their grace period and hard deletes the ones past the retention
+	PurgeAccounts(ctx context.Context) (int, error)
+
+	// StartPurgeJob runs PurgeAccounts every PurgeInterval until the context is done
+	StartPurgeJob(ctx context.Context)
+
+	// ExportData returns everything stored about the user, by section
+	ExportData(ctx context.Context, id uint) (map[string]interface{}, error)
+
+	// SetDeletionConfig sets the grace and retention periods of account deletion
+	SetDeletionConfig(config DeletionConfig)
+
+	// AddPersonalDataHandler adds the data of another module to exports and deletions
+	AddPersonalDataHandler(handler PersonalDataHandler)
+
+	// SetRegionValidator enables region checks on register and profile updates
+	SetRegionValidator(validator RegionValidator)
+
+	// SetVerification enables signed, expiring verification links sent through the mailer
+	SetVerification(config VerificationConfig, mailer service.SMTPMailService)
+
+	// SetPasswordPolicy applies the password policy of the auth module to registrations
+	SetPasswordPolicy(policy auth_usecase.PasswordPolicyUsecase)
 }
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/budimanlai/go-pkg/types"
+)
+
+type UserSearchRequest struct {
+	// Query matches a substring of the name, username, email or handphone
+	Query       string `query:"q" validate:"omitempty,max=100"`
+	Status      string `query:"status" validate:"omitempty,oneof=pending_verification active inactive suspended"`
+	Role        string `query:"role"`
+	ProvinceID  uint   `query:"province_id"`
+	CityID      uint   `query:"city_id"`
+	CreatedFrom string `query:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo   string `query:"created_to" validate:"omitempty,datetime=2006-01-02"`
+	// Sort is a column name, prefixed with "-" for descending order
+	Sort     string `query:"sort" validate:"omitempty,oneof=id -id username -username fullname -fullname email -email created_at -created_at"`
+	Page     int    `query:"page"`
+	PageSize int    `query:"page_size"`
+}
+
+// AdminActionRequest is the body of a single user action, the reason is required by status changes
+type AdminActionRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+	// SuspendedUntil ends a suspension automatically, it is ignored by the other actions
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
+
+type BulkStatusRequest struct {
+	IDs            []uint     `json:"ids" validate:"required,min=1,max=100"`
+	Action         string     `json:"action" validate:"required,oneof=activate deactivate suspend"`
+	Reason         string     `json:"reason" validate:"required,max=255"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
+
+type BulkActionError struct {
+	ID    uint   `json:"id"`
+	Error string `json:"error"`
+}
+
+type BulkActionResponse struct {
+	Succeeded []uint            `json:"succeeded"`
+	Failed    []BulkActionError `json:"failed"`
+}
+
+type ImpersonateRequest struct {
+	// AppID is the app the session is issued for, defaults to the app of the admin request
+	AppID  int    `json:"app_id"`
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type ImpersonateResponse struct {
+	SessionID int       `json:"session_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AuditLogResponse struct {
+	ID        uint          `json:"id"`
+	ActorID   uint          `json:"actor_id"`
+	ActorIP   string        `json:"actor_ip"`
+	Action    string        `json:"action"`
+	TargetID  uint          `json:"target_id"`
+	Reason    string        `json:"reason"`
+	Changes   string        `json:"changes,omitempty"`
+	CreatedAt types.UTCTime `json:"created_at"`
+}
+
+type ListAuditLogResponse struct {
+	Logs       []*AuditLogResponse `json:"logs"`
+	TotalCount int64               `json:"total_count"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+}
+
+type StatusHistoryResponse struct {
+	ID             uint          `json:"id"`
+	FromStatus     string        `json:"from_status"`
+	ToStatus       string        `json:"to_status"`
+	Reason         string        `json:"reason"`
+	ActorID        uint          `json:"actor_id"`
+	SuspendedUntil *time.Time    `json:"suspended_until,omitempty"`
+	CreatedAt      types.UTCTime `json:"created_at"`
+}
+
+type ListStatusHistoryResponse struct {
+	History    []*StatusHistoryResponse `json:"history"`
+	TotalCount int64                    `json:"total_count"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"page_size"`
+}
@@ -33,9 +33,10 @@ type LoginResponse struct {
 	User  *UserResponse `json:"user"`
 }
 
+// UpdateUserRequest changes the profile. The handphone is an identifier, it changes through the OTP
+// confirmed change-identifier flow of auth.
 type UpdateUserRequest struct {
 	Fullname      *string    `json:"fullname"`
-	Handphone     *string    `json:"handphone"`
 	Dob           *time.Time `json:"dob"`
 	Gender        *string    `json:"gender" validate:"omitempty,oneof=M F"`
 	Address       *string    `json:"address"`
@@ -45,7 +46,6 @@ type UpdateUserRequest struct {
 	CityID        *uint      `json:"city_id"`
 	ProvinceID    *uint      `json:"province_id"`
 	CountryID     *string    `json:"country_id" validate:"omitempty,len=2"`
-	Avatar        *string    `json:"avatar"`
 }
 
 type UserResponse struct {
@@ -57,6 +57,7 @@ type UserResponse struct {
 	Dob            *time.Time    `json:"dob,omitempty"`
 	Gender         string        `json:"gender"`
 	Status         string        `json:"status"`
+	SuspendedUntil *time.Time    `json:"suspended_until,omitempty"`
 	MainRole       *string       `json:"main_role,omitempty"`
 	LoginDashboard string        `json:"login_dashboard"`
 	Avatar         *string       `json:"avatar,omitempty"`
@@ -77,3 +78,15 @@ type ListUserResponse struct {
 	Page       int             `json:"page"`
 	PageSize   int             `json:"page_size"`
 }
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type DeleteAccountResponse struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
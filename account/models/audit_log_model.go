@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+type AuditLog struct {
+	ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	ActorID   uint      `gorm:"column:actor_id;not null;index"`
+	ActorIP   string    `gorm:"column:actor_ip;type:varchar(45);not null;default:''"`
+	Action    string    `gorm:"column:action;type:varchar(50);not null"`
+	TargetID  uint      `gorm:"column:target_id;not null;index"`
+	Reason    string    `gorm:"column:reason;type:varchar(255);not null;default:''"`
+	Changes   string    `gorm:"column:changes;type:text"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:milli;index"`
+}
+
+func (AuditLog) TableName() string {
+	return "account_audit_logs"
+}
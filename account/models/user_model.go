@@ -2,37 +2,45 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// User shares the "users" table with the auth module, auth only maps the columns it needs
 type User struct {
-	ID                 uint       `gorm:"primaryKey;autoIncrement"`
-	Username           string     `gorm:"type:varchar(255);uniqueIndex;not null"`
-	AuthKey            string     `gorm:"type:varchar(32);not null"`
-	PasswordHash       string     `gorm:"type:varchar(255);not null"`
-	PasswordResetToken *string    `gorm:"type:varchar(255);uniqueIndex"`
-	Email              string     `gorm:"type:varchar(255);uniqueIndex;not null"`
-	Fullname           string     `gorm:"type:varchar(225);not null"`
-	Handphone          string     `gorm:"type:varchar(15);not null"`
-	Dob                *time.Time `gorm:"type:date"`
-	Gender             string     `gorm:"type:enum('M','F');default:'M'"`
-	Status             string     `gorm:"type:enum('active','inactive','suspended');default:'active'"`
-	MainRole           *string    `gorm:"type:varchar(225)"`
-	LoginDashboard     string     `gorm:"type:enum('Y','N');default:'N'"`
-	Avatar             *string    `gorm:"type:varchar(200)"`
-	Address            *string    `gorm:"type:text"`
-	Zipcode            string     `gorm:"type:varchar(10);not null"`
-	DistrictID         uint       `gorm:"not null"`
-	SubdistrictID      uint       `gorm:"not null"`
-	CityID             uint       `gorm:"not null"`
-	ProvinceID         uint       `gorm:"not null"`
-	CountryID          string     `gorm:"type:char(2);not null"`
-	CreatedAt          time.Time  `gorm:"autoCreateTime;not null"`
-	CreatedBy          uint       `gorm:"not null"`
-	UpdatedAt          time.Time  `gorm:"autoUpdateTime;not null"`
-	UpdatedBy          uint       `gorm:"not null"`
-	VerificationToken  *string    `gorm:"type:varchar(255);uniqueIndex"`
+	ID                  uint           `gorm:"column:id;primaryKey;autoIncrement"`
+	Username            string         `gorm:"column:username;type:varchar(255);uniqueIndex;not null"`
+	Fullname            string         `gorm:"column:fullname;type:varchar(255);not null"`
+	AuthKey             string         `gorm:"column:auth_key;type:varchar(255);not null"`
+	PasswordHash        string         `gorm:"column:password_hash;type:varchar(255);not null"`
+	PasswordResetToken  *string        `gorm:"column:password_reset_token;type:varchar(255);uniqueIndex"`
+	Email               string         `gorm:"column:email;type:varchar(255);not null;uniqueIndex"`
+	Handphone           string         `gorm:"column:handphone;type:varchar(20);not null;default:'';uniqueIndex"`
+	Dob                 *time.Time     `gorm:"column:dob;type:date"`
+	Gender              string         `gorm:"column:gender;type:varchar(1);not null;default:'M'"`
+	Status              string         `gorm:"column:status;type:varchar(25);not null;default:'active'"`
+	SuspendedUntil      *time.Time     `gorm:"column:suspended_until;index"`
+	MainRole            *string        `gorm:"column:main_role;type:varchar(225)"`
+	LoginDashboard      string         `gorm:"column:login_dashboard;type:varchar(1);not null;default:'N'"`
+	Avatar              *string        `gorm:"column:avatar;type:varchar(200)"`
+	Address             *string        `gorm:"column:address;type:text"`
+	Zipcode             string         `gorm:"column:zipcode;type:varchar(10);not null;default:''"`
+	DistrictID          uint           `gorm:"column:district_id;not null;default:0"`
+	SubdistrictID       uint           `gorm:"column:subdistrict_id;not null;default:0"`
+	CityID              uint           `gorm:"column:city_id;not null;default:0"`
+	ProvinceID          uint           `gorm:"column:province_id;not null;default:0"`
+	CountryID           string         `gorm:"column:country_id;type:char(2);not null;default:''"`
+	VerificationToken   *string        `gorm:"column:verification_token;type:varchar(255);uniqueIndex"`
+	VerificationSentAt  *time.Time     `gorm:"column:verification_sent_at"`
+	DeletionScheduledAt *time.Time     `gorm:"column:deletion_scheduled_at;index"`
+	AnonymizedAt        *time.Time     `gorm:"column:anonymized_at;index"`
+	CreatedBy           uint           `gorm:"column:created_by;type:int;not null"`
+	CreatedAt           time.Time      `gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedBy           uint           `gorm:"column:updated_by;type:int;not null"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at;autoUpdateTime:milli"`
+	DeletedAt           gorm.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 func (User) TableName() string {
-	return "user"
+	return "users"
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+type UserStatusHistory struct {
+	ID             uint       `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID         uint       `gorm:"column:user_id;not null;index"`
+	FromStatus     string     `gorm:"column:from_status;type:varchar(25);not null"`
+	ToStatus       string     `gorm:"column:to_status;type:varchar(25);not null"`
+	Reason         string     `gorm:"column:reason;type:varchar(255);not null"`
+	ActorID        uint       `gorm:"column:actor_id;not null"`
+	SuspendedUntil *time.Time `gorm:"column:suspended_until"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (UserStatusHistory) TableName() string {
+	return "user_status_history"
+}
@@ -0,0 +1,294 @@
+package http
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/dto"
+	auth_http "github.com/budimanlai/go-core/auth/handler/http"
+
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/budimanlai/go-pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminHandler struct {
+	usecase usecase.AdminUsecase
+}
+
+func NewAdminHandler(usecase usecase.AdminUsecase) *AdminHandler {
+	return &AdminHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *AdminHandler) List(c *fiber.Ctx) error {
+	var req dto.UserSearchRequest
+	if err := c.QueryParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+
+	listResp, err := h.usecase.Search(c.Context(), &req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", listResp)
+}
+
+func (h *AdminHandler) ExportCSV(c *fiber.Ctx) error {
+	actor, ok := h.actor(c)
+	if !ok {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
+
+	var req dto.UserSearchRequest
+	if err := c.QueryParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+
+	var buf bytes.Buffer
+	if err := h.usecase.ExportCSV(c.Context(), actor, &req, &buf); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	c.Attachment("users.csv")
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	return c.Send(buf.Bytes())
+}
+
+func (h *AdminHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+
+	user, err := h.usecase.GetUser(c.Context(), uint(id))
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", user)
+}
+
+func (h *AdminHandler) Update(c *fiber.Ctx) error {
+	actor, ok := h.actor(c)
+	if !ok {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+
+	var req dto.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+
+	user, err := h.usecase.UpdateUser(c.Context(), actor, uint(id), &req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", user)
+}
+
+func (h *AdminHandler) Delete(c *fiber.Ctx) error {
+	return h.action(c, func(actor entity.Actor, id uint, req *dto.AdminActionRequest) error {
+		return h.usecase.DeleteUser(c.Context(), actor, id, req.Reason)
+	})
+}
+
+func (h *AdminHandler) Activate(c *fiber.Ctx) error {
+	return h.action(c, func(actor entity.Actor, id uint, req *dto.AdminActionRequest) error {
+		return h.usecase.SetStatus(c.Context(), actor, id, entity.AuditActionActivate, req)
+	})
+}
+
+func (h *AdminHandler) Deactivate(c *fiber.Ctx) error {
+	return h.action(c, func(actor entity.Actor, id uint, req *dto.AdminActionRequest) error {
+		return h.usecase.SetStatus(c.Context(), actor, id, entity.AuditActionDeactivate, req)
+	})
+}
+
+func (h *AdminHandler) Suspend(c *fiber.Ctx) error {
+	return h.action(c, func(actor entity.Actor, id uint, req *dto.AdminActionRequest) error {
+		return h.usecase.SetStatus(c.Context(), actor, id, entity.AuditActionSuspend, req)
+	})
+}
+
+func (h *AdminHandler) EnableDashboard(c *fiber.Ctx) error {
+	return h.action(c, func(actor entity.Actor, id uint, req *dto.AdminActionRequest) error {
+		return h.usecase.SetDashboardAccess(c.Context(), actor, id, true, req.Reason)
+	})
+}
+
+func (h *AdminHandler) DisableDashboard(c *fiber.Ctx) error {
+	return h.action(c, func(actor entity.Actor, id uint, req *dto.AdminActionRequest) error {
+		return h.usecase.SetDashboardAccess(c.Context(), actor, id, false, req.Reason)
+	})
+}
+
+func (h *AdminHandler) Impersonate(c *fiber.Ctx) error {
+	actor, ok := h.actor(c)
+	if !ok {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+
+	var req dto.ImpersonateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+	if req.AppID == 0 {
+		req.AppID = auth_http.AppIDFromCtx(c)
+	}
+
+	result, err := h.usecase.Impersonate(c.Context(), actor, uint(id), &req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", result)
+}
+
+func (h *AdminHandler) StopImpersonation(c *fiber.Ctx) error {
+	actor, ok := h.actor(c)
+	if !ok {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
+
+	sessionID, err := strconv.Atoi(c.Params("session_id"))
+	if err != nil {
+		return response.BadRequest(c, "Invalid session ID")
+	}
+
+	var req dto.AdminActionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+		}
+		if err := validator.ValidateStruct(req); err != nil {
+			return response.ValidationErrorI18n(c, err)
+		}
+	}
+
+	if err := h.usecase.StopImpersonation(c.Context(), actor, sessionID, req.Reason); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", nil)
+}
+
+func (h *AdminHandler) BulkStatus(c *fiber.Ctx) error {
+	actor, ok := h.actor(c)
+	if !ok {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
+
+	var req dto.BulkStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+
+	result, err := h.usecase.BulkSetStatus(c.Context(), actor, &req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", result)
+}
+
+func (h *AdminHandler) StatusHistory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+
+	history, err := h.usecase.StatusHistory(c.Context(), uint(id), page, pageSize)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", history)
+}
+
+func (h *AdminHandler) AuditLogs(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+	targetID, _ := strconv.ParseUint(c.Params("id", "0"), 10, 32)
+
+	logs, err := h.usecase.ListAuditLogs(c.Context(), uint(targetID), page, pageSize)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", logs)
+}
+
+// action parses the ID and the optional body of a single user action and runs it as the current admin
+func (h *AdminHandler) action(c *fiber.Ctx, run func(actor entity.Actor, id uint, req *dto.AdminActionRequest) error) error {
+	actor, ok := h.actor(c)
+	if !ok {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+
+	var req dto.AdminActionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+		}
+		if err := validator.ValidateStruct(req); err != nil {
+			return response.ValidationErrorI18n(c, err)
+		}
+	}
+
+	if err := run(actor, uint(id), &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", nil)
+}
+
+func (h *AdminHandler) actor(c *fiber.Ctx) (entity.Actor, bool) {
+	auth := auth_http.GetAuthContext(c)
+	if auth == nil {
+		return entity.Actor{}, false
+	}
+	return entity.Actor{UserID: auth.UserID, IP: c.IP()}, true
+}
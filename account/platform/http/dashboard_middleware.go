@@ -0,0 +1,26 @@
+package http
+
+import (
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	auth_http "github.com/budimanlai/go-core/auth/handler/http"
+
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewDashboardMiddleware lets through the users allowed on the dashboard, verified when the verification
+// config requires it. It runs after the private middleware and checks the user on every request, so a
+// revoked access applies at once.
+func NewDashboardMiddleware(userUC usecase.UserUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := auth_http.GetAuthContext(c)
+		if auth == nil {
+			return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+		}
+
+		if err := userUC.CheckDashboardAccess(c.Context(), auth.UserID); err != nil {
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	account_http "github.com/budimanlai/go-core/account/platform/http"
+	auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserUsecase allows the dashboard to the users of its set
+type fakeUserUsecase struct {
+	usecase.UserUsecase
+	allowed map[uint]bool
+}
+
+func (u *fakeUserUsecase) CheckDashboardAccess(ctx context.Context, id uint) error {
+	if !u.allowed[id] {
+		return errors.New("email is not verified")
+	}
+	return nil
+}
+
+func TestDashboardMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if userID := c.Get("X-User-ID"); userID != "" {
+			c.Locals(auth_entity.AuthContextKey{}, &auth_entity.AuthContext{UserID: map[string]uint{"1": 1, "2": 2}[userID]})
+		}
+		return c.Next()
+	})
+	app.Use(account_http.NewDashboardMiddleware(&fakeUserUsecase{allowed: map[uint]bool{1: true}}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+	request := func(userID string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		if userID != "" {
+			req.Header.Set("X-User-ID", userID)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusUnauthorized, request(""))
+	assert.Equal(t, fiber.StatusForbidden, request("2"))
+	assert.Equal(t, fiber.StatusNoContent, request("1"))
+}
@@ -1,10 +1,14 @@
 package http
 
 import (
-	"strconv"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"sort"
 
 	"github.com/budimanlai/go-core/account/domain/usecase"
 	"github.com/budimanlai/go-core/account/dto"
+	auth_http "github.com/budimanlai/go-core/auth/handler/http"
 
 	"github.com/budimanlai/go-pkg/response"
 	"github.com/budimanlai/go-pkg/validator"
@@ -31,7 +35,7 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 		return response.ValidationErrorI18n(c, err)
 	}
 
-	user, err := h.usecase.Register(&req)
+	user, err := h.usecase.Register(c.Context(), &req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -45,7 +49,7 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
 	}
 
-	loginResp, err := h.usecase.Login(&req)
+	loginResp, err := h.usecase.Login(c.Context(), &req, auth_http.AppIDFromCtx(c), c.IP(), c.Get("User-Agent"))
 	if err != nil {
 		return response.Error(c, fiber.StatusUnauthorized, err.Error())
 	}
@@ -53,153 +57,175 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 	return response.SuccessI18n(c, "app.success", loginResp)
 }
 
-func (h *UserHandler) GetByID(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+func (h *UserHandler) VerifyEmail(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return response.BadRequest(c, "Verification token is required")
 	}
 
-	user, err := h.usecase.GetByID(uint(id))
-	if err != nil {
-		return response.Error(c, fiber.StatusNotFound, err.Error())
+	if err := h.usecase.VerifyEmail(c.Context(), token); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return response.SuccessI18n(c, "app.success", user)
+	return response.SuccessI18n(c, "app.success", nil)
 }
 
-func (h *UserHandler) Update(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
+func (h *UserHandler) ResendVerification(c *fiber.Ctx) error {
+	var req dto.ResendVerificationRequest
+	if err := c.BodyParser(&req); err != nil {
 		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
 	}
 
-	var req dto.UpdateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
 	}
 
-	user, err := h.usecase.Update(uint(id), &req)
-	if err != nil {
+	if err := h.usecase.ResendVerification(c.Context(), &req); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return response.SuccessI18n(c, "app.success", user)
+	return response.SuccessI18n(c, "app.success", nil)
 }
 
-func (h *UserHandler) Delete(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+func (h *UserHandler) Me(c *fiber.Ctx) error {
+	auth := auth_http.GetAuthContext(c)
+	if auth == nil {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
 	}
 
-	if err := h.usecase.Delete(uint(id)); err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	user, err := h.usecase.GetByID(c.Context(), auth.UserID)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
 
-	return response.SuccessI18n(c, "app.success", nil)
+	return response.SuccessI18n(c, "app.success", user)
 }
 
-func (h *UserHandler) List(c *fiber.Ctx) error {
-	page, _ := strconv.Atoi(c.Query("page", "1"))
-	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+func (h *UserHandler) UpdateMe(c *fiber.Ctx) error {
+	auth := auth_http.GetAuthContext(c)
+	if auth == nil {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
 
-	if page < 1 {
-		page = 1
+	var req dto.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
 	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 10
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
 	}
 
-	listResp, err := h.usecase.List(page, pageSize)
+	user, err := h.usecase.UpdateProfile(c.Context(), auth.UserID, &req)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return response.SuccessI18n(c, "app.success", listResp)
+	return response.SuccessI18n(c, "app.success", user)
 }
 
-func (h *UserHandler) Activate(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		return response.BadRequest(c, "Invalid ID")
+func (h *UserHandler) UploadAvatar(c *fiber.Ctx) error {
+	auth := auth_http.GetAuthContext(c)
+	if auth == nil {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
 	}
 
-	if err := h.usecase.Activate(uint(id)); err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		return response.BadRequest(c, "Avatar file is required")
 	}
 
-	return response.SuccessI18n(c, "app.success", nil)
-}
-
-func (h *UserHandler) Deactivate(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	file, err := header.Open()
 	if err != nil {
-		return response.BadRequest(c, "Invalid ID")
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
 	}
+	defer file.Close()
 
-	if err := h.usecase.Deactivate(uint(id)); err != nil {
+	user, err := h.usecase.UploadAvatar(c.Context(), auth.UserID, file)
+	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return response.SuccessI18n(c, "app.success", nil)
+	return response.SuccessI18n(c, "app.success", user)
 }
 
-func (h *UserHandler) Suspend(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		return response.BadRequest(c, "Invalid ID")
+func (h *UserHandler) RequestDeletion(c *fiber.Ctx) error {
+	auth := auth_http.GetAuthContext(c)
+	if auth == nil {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
 	}
 
-	if err := h.usecase.Suspend(uint(id)); err != nil {
+	resp, err := h.usecase.RequestDeletion(c.Context(), auth.UserID, &req)
+	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return response.SuccessI18n(c, "app.success", nil)
+	return response.SuccessI18n(c, "app.success", resp)
 }
 
-func (h *UserHandler) VerifyEmail(c *fiber.Ctx) error {
-	token := c.Query("token")
-	if token == "" {
-		return response.BadRequest(c, "Verification token is required")
+func (h *UserHandler) CancelDeletion(c *fiber.Ctx) error {
+	auth := auth_http.GetAuthContext(c)
+	if auth == nil {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
 	}
 
-	if err := h.usecase.VerifyEmail(token); err != nil {
+	if err := h.usecase.CancelDeletion(c.Context(), auth.UserID); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
 	return response.SuccessI18n(c, "app.success", nil)
 }
 
-func (h *UserHandler) EnableDashboard(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		return response.BadRequest(c, "Invalid ID")
+// ExportData sends the user data as JSON, or as a ZIP with one JSON file per section with ?format=zip
+func (h *UserHandler) ExportData(c *fiber.Ctx) error {
+	auth := auth_http.GetAuthContext(c)
+	if auth == nil {
+		return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
 	}
 
-	if err := h.usecase.EnableDashboard(uint(id)); err != nil {
+	data, err := h.usecase.ExportData(c.Context(), auth.UserID)
+	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return response.SuccessI18n(c, "app.success", nil)
-}
+	if c.Query("format") != "zip" {
+		c.Attachment("my-data.json")
+		return c.JSON(data)
+	}
 
-func (h *UserHandler) DisableDashboard(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		return response.BadRequest(c, "Invalid ID")
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	if err := h.usecase.DisableDashboard(uint(id)); err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	for _, name := range names {
+		content, err := json.MarshalIndent(data[name], "", "  ")
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		}
+		file, err := archive.Create(name + ".json")
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		}
+		file.Write(content)
+	}
+	if err := archive.Close(); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	return response.SuccessI18n(c, "app.success", nil)
+	c.Attachment("my-data.zip")
+	c.Set(fiber.HeaderContentType, "application/zip")
+	return c.Send(buf.Bytes())
 }
@@ -0,0 +1,112 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+
+	// decoders for image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// AllowedContentTypes are the sniffed content types accepted as source images
+var AllowedContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// MaxPixels limits the decoded image size, a small file can declare huge dimensions
+const MaxPixels = 40_000_000
+
+// Decode sniffs the content type of data and decodes it, content not matching an allowed image type is rejected
+func Decode(data []byte) (image.Image, error) {
+	contentType := http.DetectContentType(data)
+	if !AllowedContentTypes[contentType] {
+		return nil, errors.New("unsupported image type " + contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, errors.New("invalid image")
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, errors.New("image is too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+	return img, nil
+}
+
+// Thumbnail crops the center square of src and scales it to size x size, averaging the source pixels
+// covered by each thumbnail pixel. Transparent areas are flattened on white.
+func Thumbnail(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	// flatten once so the sampling below works on straight RGBA values
+	flat := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, image.Point{X: x0, Y: y0}, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for dy := 0; dy < size; dy++ {
+		sy0, sy1 := span(dy, size, side)
+		for dx := 0; dx < size; dx++ {
+			sx0, sx1 := span(dx, size, side)
+
+			var r, g, b, n int
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					i := flat.PixOffset(sx, sy)
+					r += int(flat.Pix[i])
+					g += int(flat.Pix[i+1])
+					b += int(flat.Pix[i+2])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(dx, dy)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG encodes img as JPEG with the given quality
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// span returns the source pixels [from, to) covered by the destination pixel d, at least one pixel
+func span(d, size, side int) (int, int) {
+	from := d * side / size
+	to := (d + 1) * side / size
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
@@ -0,0 +1,55 @@
+package imaging_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/budimanlai/go-core/account/platform/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnail(t *testing.T) {
+	// left half red, right half blue, 300x200 so the crop keeps x 50..250
+	src := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 150 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	thumb := imaging.Thumbnail(src, 64)
+	assert.Equal(t, image.Rect(0, 0, 64, 64), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, thumb.RGBAAt(0, 32))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, thumb.RGBAAt(63, 32))
+
+	t.Run("Transparent On White", func(t *testing.T) {
+		thumb := imaging.Thumbnail(image.NewRGBA(image.Rect(0, 0, 10, 10)), 20)
+		assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, thumb.RGBAAt(5, 5))
+	})
+}
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+
+	img, err := imaging.Decode(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, img.Bounds().Dx())
+
+	// an empty image has no pixel to sample
+	buf.Reset()
+	assert.NoError(t, gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 0, 5), color.Palette{color.White}), nil))
+	_, err = imaging.Decode(buf.Bytes())
+	assert.Error(t, err)
+
+	_, err = imaging.Decode([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/account/domain/entity"
+	model "github.com/budimanlai/go-core/account/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.AuditLog, model.AuditLog](auditLogMapper{})
+}
+
+// auditLogMapper maps entity.AuditLog and model.AuditLog without reflection
+type auditLogMapper struct{}
+
+func (auditLogMapper) ToModel(e *entity.AuditLog, m *model.AuditLog) error {
+	m.ID = e.ID
+	m.ActorID = e.ActorID
+	m.ActorIP = e.ActorIP
+	m.Action = e.Action
+	m.TargetID = e.TargetID
+	m.Reason = e.Reason
+	m.Changes = e.Changes
+	m.CreatedAt = e.CreatedAt
+	return nil
+}
+
+func (auditLogMapper) ToEntity(m *model.AuditLog, e *entity.AuditLog) error {
+	e.ID = m.ID
+	e.ActorID = m.ActorID
+	e.ActorIP = m.ActorIP
+	e.Action = m.Action
+	e.TargetID = m.TargetID
+	e.Reason = m.Reason
+	e.Changes = m.Changes
+	e.CreatedAt = m.CreatedAt
+	return nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/repository"
+	"github.com/budimanlai/go-core/account/models"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/account/domain/entity.AuditLog -model github.com/budimanlai/go-core/account/models.AuditLog
+
+type auditLogRepositoryImpl struct {
+	base.BaseRepository[entity.AuditLog, models.AuditLog]
+}
+
+func NewAuditLogRepository(f *base.Factory) repository.AuditLogRepository {
+	return &auditLogRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.AuditLog, models.AuditLog](f),
+	}
+}
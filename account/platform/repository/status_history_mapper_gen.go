@@ -0,0 +1,41 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/account/domain/entity"
+	model "github.com/budimanlai/go-core/account/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.StatusHistory, model.UserStatusHistory](statusHistoryMapper{})
+}
+
+// statusHistoryMapper maps entity.StatusHistory and model.UserStatusHistory without reflection
+type statusHistoryMapper struct{}
+
+func (statusHistoryMapper) ToModel(e *entity.StatusHistory, m *model.UserStatusHistory) error {
+	m.ID = e.ID
+	m.UserID = e.UserID
+	m.FromStatus = e.FromStatus
+	m.ToStatus = e.ToStatus
+	m.Reason = e.Reason
+	m.ActorID = e.ActorID
+	m.SuspendedUntil = e.SuspendedUntil
+	m.CreatedAt = e.CreatedAt
+	return nil
+}
+
+func (statusHistoryMapper) ToEntity(m *model.UserStatusHistory, e *entity.StatusHistory) error {
+	e.ID = m.ID
+	e.UserID = m.UserID
+	e.FromStatus = m.FromStatus
+	e.ToStatus = m.ToStatus
+	e.Reason = m.Reason
+	e.ActorID = m.ActorID
+	e.SuspendedUntil = m.SuspendedUntil
+	e.CreatedAt = m.CreatedAt
+	return nil
+}
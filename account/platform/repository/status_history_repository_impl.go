@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/repository"
+	"github.com/budimanlai/go-core/account/models"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/account/domain/entity.StatusHistory -model github.com/budimanlai/go-core/account/models.UserStatusHistory
+
+type statusHistoryRepositoryImpl struct {
+	base.BaseRepository[entity.StatusHistory, models.UserStatusHistory]
+}
+
+func NewStatusHistoryRepository(f *base.Factory) repository.StatusHistoryRepository {
+	return &statusHistoryRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.StatusHistory, models.UserStatusHistory](f),
+	}
+}
@@ -0,0 +1,95 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/account/domain/entity"
+	model "github.com/budimanlai/go-core/account/models"
+	"gorm.io/gorm"
+)
+
+func init() {
+	base.RegisterMapper[entity.User, model.User](userMapper{})
+}
+
+// userMapper maps entity.User and model.User without reflection
+type userMapper struct{}
+
+func (userMapper) ToModel(e *entity.User, m *model.User) error {
+	m.ID = e.ID
+	m.Username = e.Username
+	m.AuthKey = e.AuthKey
+	m.PasswordHash = e.PasswordHash
+	m.PasswordResetToken = e.PasswordResetToken
+	m.Email = e.Email
+	m.Fullname = e.Fullname
+	m.Handphone = e.Handphone
+	m.Dob = e.Dob
+	m.Gender = e.Gender
+	m.Status = e.Status
+	m.SuspendedUntil = e.SuspendedUntil
+	m.MainRole = e.MainRole
+	m.LoginDashboard = e.LoginDashboard
+	m.Avatar = e.Avatar
+	m.Address = e.Address
+	m.Zipcode = e.Zipcode
+	m.DistrictID = e.DistrictID
+	m.SubdistrictID = e.SubdistrictID
+	m.CityID = e.CityID
+	m.ProvinceID = e.ProvinceID
+	m.CountryID = e.CountryID
+	m.CreatedAt = e.CreatedAt
+	m.CreatedBy = e.CreatedBy
+	m.UpdatedAt = e.UpdatedAt
+	m.UpdatedBy = e.UpdatedBy
+	m.VerificationToken = e.VerificationToken
+	m.VerificationSentAt = e.VerificationSentAt
+	m.DeletionScheduledAt = e.DeletionScheduledAt
+	m.AnonymizedAt = e.AnonymizedAt
+	m.DeletedAt = gorm.DeletedAt{}
+	if e.DeletedAt != nil {
+		m.DeletedAt = gorm.DeletedAt{Time: *e.DeletedAt, Valid: true}
+	}
+	return nil
+}
+
+func (userMapper) ToEntity(m *model.User, e *entity.User) error {
+	e.ID = m.ID
+	e.Username = m.Username
+	e.Fullname = m.Fullname
+	e.AuthKey = m.AuthKey
+	e.PasswordHash = m.PasswordHash
+	e.PasswordResetToken = m.PasswordResetToken
+	e.Email = m.Email
+	e.Handphone = m.Handphone
+	e.Dob = m.Dob
+	e.Gender = m.Gender
+	e.Status = m.Status
+	e.SuspendedUntil = m.SuspendedUntil
+	e.MainRole = m.MainRole
+	e.LoginDashboard = m.LoginDashboard
+	e.Avatar = m.Avatar
+	e.Address = m.Address
+	e.Zipcode = m.Zipcode
+	e.DistrictID = m.DistrictID
+	e.SubdistrictID = m.SubdistrictID
+	e.CityID = m.CityID
+	e.ProvinceID = m.ProvinceID
+	e.CountryID = m.CountryID
+	e.VerificationToken = m.VerificationToken
+	e.VerificationSentAt = m.VerificationSentAt
+	e.DeletionScheduledAt = m.DeletionScheduledAt
+	e.AnonymizedAt = m.AnonymizedAt
+	e.CreatedBy = m.CreatedBy
+	e.CreatedAt = m.CreatedAt
+	e.UpdatedBy = m.UpdatedBy
+	e.UpdatedAt = m.UpdatedAt
+	e.DeletedAt = nil
+	if m.DeletedAt.Valid {
+		v := m.DeletedAt.Time
+		e.DeletedAt = &v
+	}
+	return nil
+}
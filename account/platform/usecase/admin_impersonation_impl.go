@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/dto"
+
+	auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
+)
+
+var errImpersonationUnavailable = errors.New("impersonation is not available")
+
+func (u *adminUsecaseImpl) SetImpersonationTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	u.impersonationTTL = ttl
+}
+
+func (u *adminUsecaseImpl) Impersonate(ctx context.Context, actor entity.Actor, id uint, req *dto.ImpersonateRequest) (*dto.ImpersonateResponse, error) {
+	if u.sessionUC == nil {
+		return nil, errImpersonationUnavailable
+	}
+	if actor.UserID == id {
+		return nil, errors.New("cannot impersonate yourself")
+	}
+
+	user, err := u.userUC.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != entity.StatusActive {
+		return nil, errors.New("only an active user can be impersonated")
+	}
+
+	var out *dto.ImpersonateResponse
+	err = u.WithTransaction(ctx, func(ctx context.Context) error {
+		expiresAt := time.Now().Add(u.impersonationTTL)
+		session, err := u.sessionUC.GenerateSession(ctx, id, req.AppID, actor.IP, "impersonation",
+			auth_entity.WithImpersonator(actor.UserID, expiresAt))
+		if err != nil {
+			return err
+		}
+
+		token, err := u.sessionUC.SignToken(ctx, session)
+		if err != nil {
+			return err
+		}
+		out = &dto.ImpersonateResponse{SessionID: session.ID, Token: token, ExpiresAt: expiresAt}
+
+		return u.record(ctx, actor, entity.AuditActionImpersonate, id, req.Reason, map[string]interface{}{
+			"app_id":     req.AppID,
+			"session_id": session.ID,
+			"expires_at": expiresAt,
+		})
+	})
+	return out, err
+}
+
+func (u *adminUsecaseImpl) StopImpersonation(ctx context.Context, actor entity.Actor, sessionID int, reason string) error {
+	if u.sessionUC == nil {
+		return errImpersonationUnavailable
+	}
+
+	session, err := u.sessionUC.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || !session.IsImpersonated() {
+		return errors.New("impersonation session not found")
+	}
+	if session.RemoveOn != nil || session.IsExpired(time.Now()) {
+		return errors.New("impersonation session has already ended")
+	}
+
+	return u.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.sessionUC.RevokeSession(ctx, sessionID); err != nil {
+			return err
+		}
+		return u.record(ctx, actor, entity.AuditActionImpersonateEnd, session.UserID, reason, map[string]interface{}{
+			"session_id":      sessionID,
+			"impersonator_id": session.ImpersonatorID,
+		})
+	})
+}
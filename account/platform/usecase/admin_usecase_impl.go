@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/repository"
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/budimanlai/go-core/base"
+
+	auth_usecase "github.com/budimanlai/go-core/auth/domain/usecase"
+
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+type adminUsecaseImpl struct {
+	base.BaseUsecase[entity.AuditLog]
+
+	userUC           usecase.UserUsecase
+	userRepo         repository.UserRepository
+	auditRepo        repository.AuditLogRepository
+	sessionUC        auth_usecase.UserSessionUsecase
+	impersonationTTL time.Duration
+}
+
+// NewAdminUsecase builds the admin actions on top of the account usecase, sessionUC issues the impersonation
+// sessions and may be nil
+func NewAdminUsecase(db *gorm.DB, userUC usecase.UserUsecase, userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository, sessionUC auth_usecase.UserSessionUsecase) usecase.AdminUsecase {
+	return &adminUsecaseImpl{
+		BaseUsecase:      base.NewBaseUsecase(auditRepo, db),
+		userUC:           userUC,
+		userRepo:         userRepo,
+		auditRepo:        auditRepo,
+		sessionUC:        sessionUC,
+		impersonationTTL: 30 * time.Minute,
+	}
+}
+
+func (u *adminUsecaseImpl) Search(ctx context.Context, req *dto.UserSearchRequest) (*dto.ListUserResponse, error) {
+	scope, err := searchScope(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.userRepo.FindAll(ctx, req.Page, req.PageSize, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	users := make([]*dto.UserResponse, len(result.Data))
+	if err := copier.Copy(&users, &result.Data); err != nil {
+		return nil, fmt.Errorf("failed to copy users to response: %w", err)
+	}
+
+	return &dto.ListUserResponse{
+		Users:      users,
+		TotalCount: result.Total,
+		Page:       result.Page,
+		PageSize:   result.Limit,
+	}, nil
+}
+
+// ExportCSV reads the users page by page so large exports do not load everything at once.
+func (u *adminUsecaseImpl) ExportCSV(ctx context.Context, actor entity.Actor, req *dto.UserSearchRequest, w io.Writer) error {
+	scope, err := searchScope(req)
+	if err != nil {
+		return err
+	}
+
+	if err := u.record(ctx, actor, entity.AuditActionExport, 0, "", req); err != nil {
+		return err
+	}
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"id", "username", "email", "fullname", "handphone", "status", "main_role",
+		"province_id", "city_id", "created_at"})
+
+	for page := 1; ; page++ {
+		result, err := u.userRepo.FindAll(ctx, page, 100, scope)
+		if err != nil {
+			return fmt.Errorf("failed to export users: %w", err)
+		}
+
+		for _, user := range result.Data {
+			mainRole := ""
+			if user.MainRole != nil {
+				mainRole = *user.MainRole
+			}
+			out.Write([]string{
+				strconv.FormatUint(uint64(user.ID), 10),
+				csvSafe(user.Username),
+				csvSafe(user.Email),
+				csvSafe(user.Fullname),
+				csvSafe(user.Handphone),
+				user.Status,
+				csvSafe(mainRole),
+				strconv.FormatUint(uint64(user.ProvinceID), 10),
+				strconv.FormatUint(uint64(user.CityID), 10),
+				user.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+
+		if page >= result.TotalPage {
+			break
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func (u *adminUsecaseImpl) GetUser(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	return u.userUC.GetByID(ctx, id)
+}
+
+func (u *adminUsecaseImpl) UpdateUser(ctx context.Context, actor entity.Actor, id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	var out *dto.UserResponse
+	err := u.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err := u.userUC.UpdateProfile(ctx, id, req)
+		if err != nil {
+			return err
+		}
+		out = user
+		return u.record(ctx, actor, entity.AuditActionUpdate, id, "", req)
+	})
+	return out, err
+}
+
+func (u *adminUsecaseImpl) DeleteUser(ctx context.Context, actor entity.Actor, id uint, reason string) error {
+	return u.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.userUC.DeleteUser(ctx, actor.UserID, id, reason); err != nil {
+			return err
+		}
+		return u.record(ctx, actor, entity.AuditActionDelete, id, reason, nil)
+	})
+}
+
+func (u *adminUsecaseImpl) SetStatus(ctx context.Context, actor entity.Actor, id uint, action string, req *dto.AdminActionRequest) error {
+	var status string
+	var until *time.Time
+	switch action {
+	case entity.AuditActionActivate:
+		status = entity.StatusActive
+	case entity.AuditActionDeactivate:
+		status = entity.StatusInactive
+	case entity.AuditActionSuspend:
+		status, until = entity.StatusSuspended, req.SuspendedUntil
+	default:
+		return fmt.Errorf("unknown status action %q", action)
+	}
+
+	return u.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.userUC.ChangeStatus(ctx, actor.UserID, id, status, req.Reason, until); err != nil {
+			return err
+		}
+		return u.record(ctx, actor, action, id, req.Reason, req)
+	})
+}
+
+func (u *adminUsecaseImpl) BulkSetStatus(ctx context.Context, actor entity.Actor, req *dto.BulkStatusRequest) (*dto.BulkActionResponse, error) {
+	out := &dto.BulkActionResponse{Succeeded: []uint{}, Failed: []dto.BulkActionError{}}
+
+	action := &dto.AdminActionRequest{Reason: req.Reason, SuspendedUntil: req.SuspendedUntil}
+	for _, id := range req.IDs {
+		if err := u.SetStatus(ctx, actor, id, req.Action, action); err != nil {
+			out.Failed = append(out.Failed, dto.BulkActionError{ID: id, Error: err.Error()})
+			continue
+		}
+		out.Succeeded = append(out.Succeeded, id)
+	}
+
+	return out, nil
+}
+
+func (u *adminUsecaseImpl) SetDashboardAccess(ctx context.Context, actor entity.Actor, id uint, enabled bool, reason string) error {
+	return u.WithTransaction(ctx, func(ctx context.Context) error {
+		if enabled {
+			if err := u.userUC.EnableDashboard(ctx, id); err != nil {
+				return err
+			}
+			return u.record(ctx, actor, entity.AuditActionDashboardEnable, id, reason, nil)
+		}
+
+		if err := u.userUC.DisableDashboard(ctx, id); err != nil {
+			return err
+		}
+		return u.record(ctx, actor, entity.AuditActionDashboardDisable, id, reason, nil)
+	})
+}
+
+func (u *adminUsecaseImpl) StatusHistory(ctx context.Context, id uint, page, pageSize int) (*dto.ListStatusHistoryResponse, error) {
+	return u.userUC.StatusHistory(ctx, id, page, pageSize)
+}
+
+func (u *adminUsecaseImpl) ListAuditLogs(ctx context.Context, targetID uint, page, pageSize int) (*dto.ListAuditLogResponse, error) {
+	result, err := u.auditRepo.FindAll(ctx, page, pageSize, func(db *gorm.DB) *gorm.DB {
+		if targetID != 0 {
+			db = db.Where("target_id = ?", targetID)
+		}
+		return db.Order("id DESC")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	logs := make([]*dto.AuditLogResponse, len(result.Data))
+	if err := copier.Copy(&logs, &result.Data); err != nil {
+		return nil, fmt.Errorf("failed to copy audit logs to response: %w", err)
+	}
+
+	return &dto.ListAuditLogResponse{
+		Logs:       logs,
+		TotalCount: result.Total,
+		Page:       result.Page,
+		PageSize:   result.Limit,
+	}, nil
+}
+
+// record writes the audit log, in the transaction of the action when there is one
+func (u *adminUsecaseImpl) record(ctx context.Context, actor entity.Actor, action string, targetID uint, reason string, changes interface{}) error {
+	log := entity.AuditLog{
+		ActorID:   actor.UserID,
+		ActorIP:   actor.IP,
+		Action:    action,
+		TargetID:  targetID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if changes != nil {
+		data, err := json.Marshal(changes)
+		if err != nil {
+			return err
+		}
+		log.Changes = string(data)
+	}
+
+	if err := u.auditRepo.Create(ctx, &log); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// searchScope builds the filters and the sort of a user search
+func searchScope(req *dto.UserSearchRequest) (func(*gorm.DB) *gorm.DB, error) {
+	var from, to time.Time
+	var err error
+	if req.CreatedFrom != "" {
+		if from, err = time.Parse(time.DateOnly, req.CreatedFrom); err != nil {
+			return nil, errors.New("invalid created_from date")
+		}
+	}
+	if req.CreatedTo != "" {
+		if to, err = time.Parse(time.DateOnly, req.CreatedTo); err != nil {
+			return nil, errors.New("invalid created_to date")
+		}
+	}
+
+	order := "id"
+	if req.Sort != "" {
+		column, desc := strings.CutPrefix(req.Sort, "-")
+		switch column {
+		case "id", "username", "fullname", "email", "created_at":
+		default:
+			return nil, fmt.Errorf("invalid sort %q", req.Sort)
+		}
+		order = column
+		if desc {
+			order += " DESC"
+		}
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		if req.Query != "" {
+			like := "%" + escapeLike(strings.ToLower(req.Query)) + "%"
+			db = db.Where("LOWER(fullname) LIKE ? OR LOWER(username) LIKE ? OR LOWER(email) LIKE ? OR handphone LIKE ?",
+				like, like, like, like)
+		}
+		if req.Status != "" {
+			db = db.Where("status = ?", req.Status)
+		}
+		if req.Role != "" {
+			db = db.Where("main_role = ?", req.Role)
+		}
+		if req.ProvinceID != 0 {
+			db = db.Where("province_id = ?", req.ProvinceID)
+		}
+		if req.CityID != 0 {
+			db = db.Where("city_id = ?", req.CityID)
+		}
+		if !from.IsZero() {
+			db = db.Where("created_at >= ?", from)
+		}
+		if !to.IsZero() {
+			// the end date is inclusive
+			db = db.Where("created_at < ?", to.AddDate(0, 0, 1))
+		}
+		return db.Order(order)
+	}, nil
+}
+
+// escapeLike escapes the LIKE wildcards of a user search
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// csvSafe prevents spreadsheet formula injection from user controlled values
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,66 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/repository"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/budimanlai/go-core/account/platform/usecase"
+	"github.com/budimanlai/go-core/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuditLogRepo struct {
+	repository.AuditLogRepository
+	logs []entity.AuditLog
+}
+
+func (r *fakeAuditLogRepo) Create(ctx context.Context, log *entity.AuditLog) error {
+	r.logs = append(r.logs, *log)
+	return nil
+}
+
+func TestAdminUsecase_BulkSetStatus(t *testing.T) {
+	ctx := context.Background()
+	db := openTxDB(t)
+	users := &fakeUserRepo{users: map[uint]*entity.User{
+		1: {ID: 1, Email: "john@example.com", Status: entity.StatusActive},
+		2: {ID: 2, Email: "jane@example.com", Status: entity.StatusActive},
+	}}
+	history := &fakeStatusHistoryRepo{}
+	audit := &fakeAuditLogRepo{}
+	hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	userUC := usecase.NewUserUsecase(db, users, history, hasher, nil)
+	uc := usecase.NewAdminUsecase(db, userUC, users, audit, nil)
+
+	actor := entity.Actor{UserID: 99, IP: "10.0.0.1"}
+	until := time.Now().Add(24 * time.Hour)
+	out, err := uc.BulkSetStatus(ctx, actor, &dto.BulkStatusRequest{
+		IDs:            []uint{1, 3, 2},
+		Action:         entity.AuditActionSuspend,
+		Reason:         "spam",
+		SuspendedUntil: &until,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{1, 2}, out.Succeeded)
+	require.Len(t, out.Failed, 1)
+	assert.Equal(t, uint(3), out.Failed[0].ID)
+
+	// one audit row per changed user, none for the failed one
+	require.Len(t, audit.logs, 2)
+	for i, id := range []uint{1, 2} {
+		log := audit.logs[i]
+		assert.Equal(t, id, log.TargetID)
+		assert.Equal(t, entity.AuditActionSuspend, log.Action)
+		assert.Equal(t, actor.UserID, log.ActorID)
+		assert.Equal(t, actor.IP, log.ActorIP)
+		assert.Equal(t, "spam", log.Reason)
+		assert.Equal(t, entity.StatusSuspended, users.users[id].Status)
+	}
+	assert.Len(t, history.history, 2)
+}
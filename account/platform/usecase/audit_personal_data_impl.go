@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/repository"
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/models"
+	"gorm.io/gorm"
+)
+
+// auditPersonalDataImpl covers the admin audit log, the rows are kept as the trail of what happened
+type auditPersonalDataImpl struct {
+	repo repository.AuditLogRepository
+}
+
+func NewAuditPersonalDataImpl(repo repository.AuditLogRepository) usecase.PersonalDataHandler {
+	return &auditPersonalDataImpl{repo: repo}
+}
+
+func (h *auditPersonalDataImpl) Name() string {
+	return "audit"
+}
+
+func (h *auditPersonalDataImpl) Export(ctx context.Context, user *entity.User) (interface{}, error) {
+	type action struct {
+		Action    string    `json:"action"`
+		Reason    string    `json:"reason"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	var logs []models.AuditLog
+	err := h.repo.GetDB(ctx).Where("target_id = ?", user.ID).Order("id").Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]action, 0, len(logs))
+	for _, log := range logs {
+		actions = append(actions, action{log.Action, log.Reason, log.CreatedAt})
+	}
+	return actions, nil
+}
+
+// Anonymize drops the free text and the changes about the user, and the IP of the actions made by the user.
+func (h *auditPersonalDataImpl) Anonymize(ctx context.Context, user *entity.User) error {
+	db := h.repo.GetDB(ctx).Model(&models.AuditLog{})
+
+	err := db.Session(&gorm.Session{}).Where("target_id = ?", user.ID).
+		Updates(map[string]interface{}{"reason": "", "changes": ""}).Error
+	if err != nil {
+		return err
+	}
+	return db.Session(&gorm.Session{}).Where("actor_id = ?", user.ID).Update("actor_ip", "").Error
+}
+
+func (h *auditPersonalDataImpl) Delete(ctx context.Context, userID uint) error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/base"
+	"gorm.io/gorm"
+
+	auth_models "github.com/budimanlai/go-core/auth/models"
+)
+
+// authPersonalDataImpl covers the auth module tables: sessions, OTPs, linked identities and password history
+type authPersonalDataImpl struct {
+	db *gorm.DB
+}
+
+func NewAuthPersonalDataImpl(db *gorm.DB) usecase.PersonalDataHandler {
+	return &authPersonalDataImpl{db: db}
+}
+
+func (h *authPersonalDataImpl) Name() string {
+	return "auth"
+}
+
+func (h *authPersonalDataImpl) Export(ctx context.Context, user *entity.User) (interface{}, error) {
+	type session struct {
+		AppID        int        `json:"app_id"`
+		CreatedAt    time.Time  `json:"created_at"`
+		LastAccessAt *time.Time `json:"last_access_at"`
+		RevokedAt    *time.Time `json:"revoked_at"`
+		FromIP       string     `json:"from_ip"`
+		UserAgent    string     `json:"user_agent"`
+	}
+	type otp struct {
+		Identifier string    `json:"identifier"`
+		Status     string    `json:"status"`
+		CreatedAt  time.Time `json:"created_at"`
+	}
+	type identity struct {
+		Provider  string    `json:"provider"`
+		Email     string    `json:"email"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	db := h.tx(ctx)
+	out := map[string]interface{}{}
+
+	var sessions []auth_models.UserSession
+	if err := db.Where("user_id = ?", user.ID).Order("id").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	sessionList := make([]session, 0, len(sessions))
+	for _, s := range sessions {
+		sessionList = append(sessionList, session{s.AppID, s.CreateOn, s.LastAccessOn, s.RemoveOn, s.FromIP, s.UserAgent})
+	}
+	out["sessions"] = sessionList
+
+	var otps []auth_models.Otp
+	if err := db.Where("handphone IN ?", h.identifiers(user)).Order("id").Find(&otps).Error; err != nil {
+		return nil, err
+	}
+	otpList := make([]otp, 0, len(otps))
+	for _, o := range otps {
+		otpList = append(otpList, otp{o.Handphone, o.Status, o.CreatedAt})
+	}
+	out["otps"] = otpList
+
+	var identities []auth_models.UserIdentity
+	if err := db.Where("user_id = ?", user.ID).Order("id").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	identityList := make([]identity, 0, len(identities))
+	for _, i := range identities {
+		identityList = append(identityList, identity{i.Provider, i.Email, i.CreatedAt})
+	}
+	out["identities"] = identityList
+
+	var passwordChanges []time.Time
+	if err := db.Model(&auth_models.PasswordHistory{}).Where("user_id = ?", user.ID).Order("id").
+		Pluck("created_at", &passwordChanges).Error; err != nil {
+		return nil, err
+	}
+	out["password_changes"] = passwordChanges
+
+	return out, nil
+}
+
+// Anonymize keeps the sessions as revoked rows without IP and user agent, the rest is deleted.
+func (h *authPersonalDataImpl) Anonymize(ctx context.Context, user *entity.User) error {
+	db := h.tx(ctx)
+
+	err := db.Model(&auth_models.UserSession{}).Where("user_id = ?", user.ID).
+		Updates(map[string]interface{}{"from_ip": "", "user_agent": ""}).Error
+	if err != nil {
+		return err
+	}
+	err = db.Model(&auth_models.UserSession{}).Where("user_id = ? AND remove_on IS NULL", user.ID).
+		Update("remove_on", time.Now()).Error
+	if err != nil {
+		return err
+	}
+
+	if err := db.Where("handphone IN ?", h.identifiers(user)).Delete(&auth_models.Otp{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("user_id = ?", user.ID).Delete(&auth_models.UserIdentity{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", user.ID).Delete(&auth_models.PasswordHistory{}).Error
+}
+
+func (h *authPersonalDataImpl) Delete(ctx context.Context, userID uint) error {
+	return h.tx(ctx).Where("user_id = ?", userID).Delete(&auth_models.UserSession{}).Error
+}
+
+// identifiers are the values the OTP rows are stored under
+func (h *authPersonalDataImpl) identifiers(user *entity.User) []string {
+	identifiers := []string{user.Email}
+	if user.Handphone != "" {
+		identifiers = append(identifiers, user.Handphone)
+	}
+	return identifiers
+}
+
+func (h *authPersonalDataImpl) tx(ctx context.Context) *gorm.DB {
+	if tx := base.ExtractTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return h.db.WithContext(ctx)
+}
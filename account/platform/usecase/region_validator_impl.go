@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/region"
+	"gorm.io/gorm"
+)
+
+type regionValidatorImpl struct {
+	region *region.RegionContainer
+}
+
+// NewRegionValidatorImpl validates the profile regions against the region module tables
+func NewRegionValidatorImpl(container *region.RegionContainer) usecase.RegionValidator {
+	return &regionValidatorImpl{region: container}
+}
+
+// ValidateRegion walks down from the province, each level must belong to the one above it when both are set.
+func (v *regionValidatorImpl) ValidateRegion(ctx context.Context, countryID string, provinceID, cityID, districtID, subdistrictID uint) error {
+	if countryID != "" {
+		country, err := v.region.CountryinfoService.FindOne(ctx, func(db *gorm.DB) *gorm.DB {
+			return db.Where("iso_alpha2 = ?", countryID)
+		})
+		if err != nil {
+			return err
+		}
+		if country == nil {
+			return errors.New("invalid country")
+		}
+	}
+
+	if provinceID != 0 {
+		province, err := v.region.ProvinceService.FindByID(ctx, provinceID)
+		if err != nil {
+			return err
+		}
+		if province == nil {
+			return errors.New("invalid province")
+		}
+	}
+
+	if cityID != 0 {
+		city, err := v.region.CityService.FindByID(ctx, cityID)
+		if err != nil {
+			return err
+		}
+		if city == nil || (provinceID != 0 && uint(city.ProvId) != provinceID) {
+			return errors.New("invalid city")
+		}
+	}
+
+	if districtID != 0 {
+		district, err := v.region.DistrictService.FindByID(ctx, districtID)
+		if err != nil {
+			return err
+		}
+		if district == nil || (cityID != 0 && uint(district.CityId) != cityID) {
+			return errors.New("invalid district")
+		}
+	}
+
+	if subdistrictID != 0 {
+		subdistrict, err := v.region.SubdistrictService.FindByID(ctx, subdistrictID)
+		if err != nil {
+			return err
+		}
+		if subdistrict == nil || (districtID != 0 && uint(subdistrict.DisId) != districtID) {
+			return errors.New("invalid subdistrict")
+		}
+	}
+
+	return nil
+}
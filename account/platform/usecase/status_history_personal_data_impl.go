@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/repository"
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/models"
+)
+
+// statusHistoryPersonalDataImpl covers the status history, the transitions stay until the user row is deleted
+type statusHistoryPersonalDataImpl struct {
+	repo repository.StatusHistoryRepository
+}
+
+func NewStatusHistoryPersonalDataImpl(repo repository.StatusHistoryRepository) usecase.PersonalDataHandler {
+	return &statusHistoryPersonalDataImpl{repo: repo}
+}
+
+func (h *statusHistoryPersonalDataImpl) Name() string {
+	return "status_history"
+}
+
+func (h *statusHistoryPersonalDataImpl) Export(ctx context.Context, user *entity.User) (interface{}, error) {
+	type change struct {
+		FromStatus     string     `json:"from_status"`
+		ToStatus       string     `json:"to_status"`
+		Reason         string     `json:"reason"`
+		SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+		CreatedAt      time.Time  `json:"created_at"`
+	}
+
+	var rows []models.UserStatusHistory
+	err := h.repo.GetDB(ctx).Where("user_id = ?", user.ID).Order("id").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]change, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, change{row.FromStatus, row.ToStatus, row.Reason, row.SuspendedUntil, row.CreatedAt})
+	}
+	return changes, nil
+}
+
+// Anonymize drops the reasons, they are free text written about the user.
+func (h *statusHistoryPersonalDataImpl) Anonymize(ctx context.Context, user *entity.User) error {
+	return h.repo.GetDB(ctx).Model(&models.UserStatusHistory{}).Where("user_id = ?", user.ID).
+		Update("reason", "").Error
+}
+
+func (h *statusHistoryPersonalDataImpl) Delete(ctx context.Context, userID uint) error {
+	return h.repo.GetDB(ctx).Where("user_id = ?", userID).Delete(&models.UserStatusHistory{}).Error
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/budimanlai/go-core/account/platform/imaging"
+	"github.com/budimanlai/go-core/service"
+)
+
+func (u *userUsecaseImpl) SetAvatarStorage(storage service.StorageService, config usecase.AvatarConfig) {
+	if len(config.Sizes) == 0 {
+		config.Sizes = []int{256, 64}
+	}
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = 5 << 20
+	}
+	if config.Quality <= 0 || config.Quality > 100 {
+		config.Quality = 85
+	}
+	if config.Prefix == "" {
+		config.Prefix = "avatars"
+	}
+	u.avatarStorage = storage
+	u.avatar = config
+}
+
+// UploadAvatar checks the sniffed content type rather than the declared one, the thumbnails are re-encoded
+// as JPEG so nothing of the uploaded file is served as is.
+func (u *userUsecaseImpl) UploadAvatar(ctx context.Context, id uint, file io.Reader) (*dto.UserResponse, error) {
+	if u.avatarStorage == nil {
+		return nil, errors.New("avatar upload is not available")
+	}
+
+	user, err := u.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, u.avatar.MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if int64(len(data)) > u.avatar.MaxFileSize {
+		return nil, errors.New("avatar file is too large")
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// keys are stable per user and size so a new upload replaces the old files
+	var avatarURL string
+	for i, size := range u.avatar.Sizes {
+		thumbnail, err := imaging.EncodeJPEG(imaging.Thumbnail(img, size), u.avatar.Quality)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode avatar: %w", err)
+		}
+
+		url, err := u.avatarStorage.Put(ctx, u.avatarKey(user.ID, size), "image/jpeg", thumbnail)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store avatar: %w", err)
+		}
+		if i == 0 {
+			avatarURL = url
+		}
+	}
+
+	// the version busts the caches holding the previous avatar
+	avatar := fmt.Sprintf("%s?v=%d", avatarURL, time.Now().Unix())
+	if err := u.repo.UpdateFields(ctx, user.ID, map[string]interface{}{"avatar": avatar}); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	user.Avatar = &avatar
+	return u.toResponse(user).(*dto.UserResponse), nil
+}
+
+func (u *userUsecaseImpl) avatarKey(id uint, size int) string {
+	return fmt.Sprintf("%s/%d/%d.jpg", u.avatar.Prefix, id, size)
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// purgeBatchSize limits the users handled by one PurgeAccounts run
+const purgeBatchSize = 100
+
+func (u *userUsecaseImpl) SetDeletionConfig(config usecase.DeletionConfig) {
+	if config.GracePeriod <= 0 {
+		config.GracePeriod = 30 * 24 * time.Hour
+	}
+	u.deletion = config
+}
+
+func (u *userUsecaseImpl) AddPersonalDataHandler(handler usecase.PersonalDataHandler) {
+	u.personalData = append(u.personalData, handler)
+}
+
+func (u *userUsecaseImpl) RequestDeletion(ctx context.Context, id uint, req *dto.DeleteAccountRequest) (*dto.DeleteAccountResponse, error) {
+	user, err := u.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.hasher.CheckPasswordHash(req.Password, user.PasswordHash) {
+		return nil, errors.New("invalid password")
+	}
+
+	// asking again does not push the date back
+	if user.IsDeletionScheduled() {
+		return &dto.DeleteAccountResponse{ScheduledAt: *user.DeletionScheduledAt}, nil
+	}
+
+	scheduledAt := time.Now().Add(u.deletion.GracePeriod)
+	if err := u.repo.UpdateFields(ctx, id, map[string]interface{}{"deletion_scheduled_at": scheduledAt}); err != nil {
+		return nil, fmt.Errorf("failed to schedule deletion: %w", err)
+	}
+
+	if u.mailer != nil && u.deletion.Template != "" {
+		_ = u.mailer.SendWithTemplate(user.Email, u.deletion.Template, map[string]interface{}{
+			"fullname":     user.Fullname,
+			"username":     user.Username,
+			"scheduled_at": scheduledAt.Format(time.RFC1123),
+		})
+	}
+
+	return &dto.DeleteAccountResponse{ScheduledAt: scheduledAt}, nil
+}
+
+func (u *userUsecaseImpl) CancelDeletion(ctx context.Context, id uint) error {
+	user, err := u.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !user.IsDeletionScheduled() {
+		return errors.New("account deletion is not scheduled")
+	}
+
+	return u.repo.UpdateFields(ctx, id, map[string]interface{}{"deletion_scheduled_at": nil})
+}
+
+// PurgeAccounts runs in two steps: users past the grace period are anonymized and soft deleted, so rows of
+// other modules referencing them stay valid, then anonymized users past the retention period are hard deleted.
+// A user anonymized by a run is hard deleted by a later run at the earliest, even with no retention period.
+// A failing user does not stop the run, the errors of every failed user are joined.
+func (u *userUsecaseImpl) PurgeAccounts(ctx context.Context) (int, error) {
+	now := time.Now()
+	purged := 0
+	anonymized := make(map[uint]bool)
+	var errs []error
+
+	due, err := u.repo.FindAll(ctx, 1, purgeBatchSize, func(db *gorm.DB) *gorm.DB {
+		return db.Where("deletion_scheduled_at <= ? AND anonymized_at IS NULL", now)
+	})
+	if err != nil {
+		return purged, fmt.Errorf("failed to find users to anonymize: %w", err)
+	}
+	for i := range due.Data {
+		if !due.Data[i].IsDeletionDue(now) {
+			continue
+		}
+		if err := u.anonymize(ctx, &due.Data[i], now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to anonymize user %d: %w", due.Data[i].ID, err))
+			continue
+		}
+		anonymized[due.Data[i].ID] = true
+		purged++
+	}
+
+	expired, err := u.repo.FindAll(ctx, 1, purgeBatchSize, func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Where("anonymized_at < ?", now.Add(-u.deletion.RetentionPeriod))
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to find users to delete: %w", err))
+		return purged, errors.Join(errs...)
+	}
+	for _, user := range expired.Data {
+		// the database may round anonymized_at down, so the users of this run are skipped by ID
+		if anonymized[user.ID] || !user.IsPurgeDue(now, u.deletion.RetentionPeriod) {
+			continue
+		}
+		err := u.WithTransaction(ctx, func(ctx context.Context) error {
+			for _, handler := range u.personalData {
+				if err := handler.Delete(ctx, user.ID); err != nil {
+					return fmt.Errorf("failed to delete %s data: %w", handler.Name(), err)
+				}
+			}
+			return u.repo.ForceDelete(ctx, user.ID)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete user %d: %w", user.ID, err))
+			continue
+		}
+		purged++
+	}
+
+	return purged, errors.Join(errs...)
+}
+
+func (u *userUsecaseImpl) StartPurgeJob(ctx context.Context) {
+	if u.deletion.PurgeInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(u.deletion.PurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := u.PurgeAccounts(ctx); err != nil {
+					log.Printf("account: purge job: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// anonymize replaces the personal data of the user row and of the other modules, the placeholders keep
+// the unique columns unique
+func (u *userUsecaseImpl) anonymize(ctx context.Context, user *entity.User, now time.Time) error {
+	history, err := user.TransitionTo(entity.StatusDeleted, "account deletion requested", user.ID, nil, now)
+	if err != nil {
+		return err
+	}
+
+	err = u.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, handler := range u.personalData {
+			if err := handler.Anonymize(ctx, user); err != nil {
+				return fmt.Errorf("failed to anonymize %s data: %w", handler.Name(), err)
+			}
+		}
+
+		if err := u.historyRepo.Create(ctx, history); err != nil {
+			return fmt.Errorf("failed to write status history: %w", err)
+		}
+
+		err := u.repo.UpdateFields(ctx, user.ID, map[string]interface{}{
+			"username":             fmt.Sprintf("deleted-%d", user.ID),
+			"email":                fmt.Sprintf("deleted-%d@deleted.invalid", user.ID),
+			"handphone":            fmt.Sprintf("deleted-%d", user.ID),
+			"fullname":             "Deleted User",
+			"auth_key":             "",
+			"password_hash":        "",
+			"password_reset_token": nil,
+			"verification_token":   nil,
+			"dob":                  nil,
+			"main_role":            nil,
+			"avatar":               nil,
+			"address":              nil,
+			"zipcode":              "",
+			"district_id":          0,
+			"subdistrict_id":       0,
+			"city_id":              0,
+			"province_id":          0,
+			"status":               entity.StatusDeleted,
+			"suspended_until":      nil,
+			"login_dashboard":      "N",
+			"anonymized_at":        now,
+			"deleted_at":           now,
+		})
+		if err != nil {
+			return err
+		}
+
+		return u.revokeSessions(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// stored files are not transactional, a failure only leaves orphan files
+	if u.avatarStorage != nil && user.Avatar != nil {
+		for _, size := range u.avatar.Sizes {
+			u.avatarStorage.Delete(ctx, u.avatarKey(user.ID, size))
+		}
+	}
+
+	return nil
+}
+
+// ExportData never includes secrets such as password hashes and tokens.
+func (u *userUsecaseImpl) ExportData(ctx context.Context, id uint) (map[string]interface{}, error) {
+	user, err := u.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile dto.UserResponse
+	if err := copier.Copy(&profile, user); err != nil {
+		return nil, fmt.Errorf("failed to copy user to export: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"profile": profile,
+		"account": map[string]interface{}{
+			"verified":              user.IsVerified(),
+			"deletion_scheduled_at": user.DeletionScheduledAt,
+		},
+	}
+	for _, handler := range u.personalData {
+		section, err := handler.Export(ctx, user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s data: %w", handler.Name(), err)
+		}
+		data[handler.Name()] = section
+	}
+
+	return data, nil
+}
@@ -0,0 +1,258 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/repository"
+	dom_usecase "github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/budimanlai/go-core/account/platform/usecase"
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+// txPool lets WithTransaction begin and commit without a database
+type txPool struct{ gorm.ConnPool }
+
+func (p *txPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &txConn{}, nil
+}
+
+type txConn struct{ gorm.ConnPool }
+
+func (*txConn) Commit() error   { return nil }
+func (*txConn) Rollback() error { return nil }
+
+// txDialector adds savepoints, so transactions can be nested
+type txDialector struct{ tests.DummyDialector }
+
+func (txDialector) SavePoint(tx *gorm.DB, name string) error  { return nil }
+func (txDialector) RollbackTo(tx *gorm.DB, name string) error { return nil }
+
+func openTxDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(txDialector{}, &gorm.Config{DryRun: true, ConnPool: &txPool{}})
+	require.NoError(t, err)
+	return db
+}
+
+// fakeUserRepo keeps the users in memory, FindAll ignores the scopes and returns every user
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[uint]*entity.User
+
+	// updates keeps the fields of every UpdateFields
+	updates []map[string]interface{}
+
+	// failing users make UpdateFields fail
+	failing map[uint]bool
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id any, scopes ...func(*gorm.DB) *gorm.DB) (*entity.User, error) {
+	user, ok := r.users[id.(uint)]
+	if !ok {
+		return nil, nil
+	}
+	out := *user
+	return &out, nil
+}
+
+func (r *fakeUserRepo) FindAll(ctx context.Context, page, limit int, scopes ...func(*gorm.DB) *gorm.DB) (base.PaginationResult[entity.User], error) {
+	var result base.PaginationResult[entity.User]
+	for _, user := range r.users {
+		result.Data = append(result.Data, *user)
+	}
+	sort.Slice(result.Data, func(i, j int) bool { return result.Data[i].ID < result.Data[j].ID })
+	result.Total = int64(len(result.Data))
+	return result, nil
+}
+
+// FindOne matches the value of the first condition of the scopes against the email and the verification token
+func (r *fakeUserRepo) FindOne(ctx context.Context, scopes ...func(*gorm.DB) *gorm.DB) (*entity.User, error) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		return nil, err
+	}
+	stmt := db.Table("users").Scopes(scopes...).Find(&[]map[string]interface{}{}).Statement
+	if len(stmt.Vars) == 0 {
+		return nil, nil
+	}
+	for _, user := range r.users {
+		if user.Email == stmt.Vars[0] || user.VerificationToken != nil && *user.VerificationToken == stmt.Vars[0] {
+			out := *user
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *entity.User) error {
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
+
+func (r *fakeUserRepo) UpdateFields(ctx context.Context, id any, fields map[string]interface{}) error {
+	if r.failing[id.(uint)] {
+		return errors.New("update failed")
+	}
+	r.updates = append(r.updates, fields)
+	user := r.users[id.(uint)]
+	for name, value := range fields {
+		switch name {
+		case "deletion_scheduled_at":
+			if at, ok := value.(time.Time); ok {
+				user.DeletionScheduledAt = &at
+			} else {
+				user.DeletionScheduledAt = nil
+			}
+		case "anonymized_at":
+			at := value.(time.Time)
+			user.AnonymizedAt = &at
+		case "status":
+			user.Status = value.(string)
+		case "suspended_until":
+			until, _ := value.(*time.Time)
+			user.SuspendedUntil = until
+		case "verification_sent_at":
+			at := value.(time.Time)
+			user.VerificationSentAt = &at
+		case "verification_token":
+			if token, ok := value.(string); ok {
+				user.VerificationToken = &token
+			} else {
+				user.VerificationToken = nil
+			}
+		case "email":
+			user.Email = value.(string)
+		case "fullname":
+			user.Fullname = value.(string)
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) ForceDelete(ctx context.Context, id any) error {
+	delete(r.users, id.(uint))
+	return nil
+}
+
+type fakeStatusHistoryRepo struct {
+	repository.StatusHistoryRepository
+	history []entity.StatusHistory
+}
+
+func (r *fakeStatusHistoryRepo) Create(ctx context.Context, history *entity.StatusHistory) error {
+	r.history = append(r.history, *history)
+	return nil
+}
+
+func TestUserUsecase_PurgeAccounts(t *testing.T) {
+	ctx := context.Background()
+	hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	passwordHash, err := hasher.HashPassword("secret")
+	require.NoError(t, err)
+
+	setup := func(config dom_usecase.DeletionConfig) (dom_usecase.UserUsecase, *fakeUserRepo, *fakeStatusHistoryRepo) {
+		users := &fakeUserRepo{users: map[uint]*entity.User{
+			1: {ID: 1, Email: "john@example.com", Status: entity.StatusActive, PasswordHash: passwordHash},
+			2: {ID: 2, Email: "jane@example.com", Status: entity.StatusActive, PasswordHash: passwordHash},
+		}}
+		history := &fakeStatusHistoryRepo{}
+		uc := usecase.NewUserUsecase(openTxDB(t), users, history, hasher, nil)
+		uc.SetDeletionConfig(config)
+		return uc, users, history
+	}
+
+	t.Run("Grace period", func(t *testing.T) {
+		uc, users, _ := setup(dom_usecase.DeletionConfig{GracePeriod: time.Hour})
+
+		_, err := uc.RequestDeletion(ctx, 1, &dto.DeleteAccountRequest{Password: "wrong"})
+		assert.Error(t, err)
+
+		scheduled, err := uc.RequestDeletion(ctx, 1, &dto.DeleteAccountRequest{Password: "secret"})
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), scheduled.ScheduledAt, time.Minute)
+
+		purged, err := uc.PurgeAccounts(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+		assert.Nil(t, users.users[1].AnonymizedAt)
+
+		require.NoError(t, uc.CancelDeletion(ctx, 1))
+		assert.Nil(t, users.users[1].DeletionScheduledAt)
+		assert.Error(t, uc.CancelDeletion(ctx, 1))
+	})
+
+	t.Run("Anonymize then purge on the next run", func(t *testing.T) {
+		uc, users, history := setup(dom_usecase.DeletionConfig{GracePeriod: time.Nanosecond})
+
+		_, err := uc.RequestDeletion(ctx, 1, &dto.DeleteAccountRequest{Password: "secret"})
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+
+		// no retention, still the anonymized row is only deleted by the next run
+		purged, err := uc.PurgeAccounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		require.Contains(t, users.users, uint(1))
+		assert.NotNil(t, users.users[1].AnonymizedAt)
+		assert.Equal(t, entity.StatusDeleted, users.users[1].Status)
+		assert.Equal(t, "deleted-1@deleted.invalid", users.users[1].Email)
+		require.Len(t, history.history, 1)
+		assert.Equal(t, entity.StatusDeleted, history.history[0].ToStatus)
+
+		time.Sleep(time.Millisecond)
+		purged, err = uc.PurgeAccounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.NotContains(t, users.users, uint(1))
+		assert.Contains(t, users.users, uint(2), "users without a request are kept")
+	})
+
+	t.Run("Retention period", func(t *testing.T) {
+		uc, users, _ := setup(dom_usecase.DeletionConfig{GracePeriod: time.Nanosecond, RetentionPeriod: time.Hour})
+
+		_, err := uc.RequestDeletion(ctx, 1, &dto.DeleteAccountRequest{Password: "secret"})
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+
+		for range 2 {
+			_, err := uc.PurgeAccounts(ctx)
+			require.NoError(t, err)
+		}
+		assert.Contains(t, users.users, uint(1))
+
+		anonymizedAt := time.Now().Add(-2 * time.Hour)
+		users.users[1].AnonymizedAt = &anonymizedAt
+		purged, err := uc.PurgeAccounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.NotContains(t, users.users, uint(1))
+	})
+	t.Run("A failing user does not stop the run", func(t *testing.T) {
+		uc, users, _ := setup(dom_usecase.DeletionConfig{GracePeriod: time.Nanosecond})
+
+		for _, id := range []uint{1, 2} {
+			_, err := uc.RequestDeletion(ctx, id, &dto.DeleteAccountRequest{Password: "secret"})
+			require.NoError(t, err)
+		}
+		time.Sleep(time.Millisecond)
+
+		users.failing = map[uint]bool{1: true}
+		purged, err := uc.PurgeAccounts(ctx)
+		assert.ErrorContains(t, err, "failed to anonymize user 1")
+		assert.Equal(t, 1, purged)
+		assert.Nil(t, users.users[1].AnonymizedAt)
+		assert.NotNil(t, users.users[2].AnonymizedAt)
+	})
+}
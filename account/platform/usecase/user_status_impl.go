@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// suspensionBatchSize limits the users reactivated by one ReactivateExpiredSuspensions run
+const suspensionBatchSize = 100
+
+func (u *userUsecaseImpl) ChangeStatus(ctx context.Context, actorID, id uint, status, reason string, until *time.Time) error {
+	user, err := u.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	return u.applyStatus(ctx, user, actorID, status, reason, until)
+}
+
+func (u *userUsecaseImpl) StatusHistory(ctx context.Context, id uint, page, pageSize int) (*dto.ListStatusHistoryResponse, error) {
+	result, err := u.historyRepo.FindAll(ctx, page, pageSize, func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", id).Order("id DESC")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status history: %w", err)
+	}
+
+	history := make([]*dto.StatusHistoryResponse, len(result.Data))
+	if err := copier.Copy(&history, &result.Data); err != nil {
+		return nil, fmt.Errorf("failed to copy status history to response: %w", err)
+	}
+
+	return &dto.ListStatusHistoryResponse{
+		History:    history,
+		TotalCount: result.Total,
+		Page:       result.Page,
+		PageSize:   result.Limit,
+	}, nil
+}
+
+func (u *userUsecaseImpl) ReactivateExpiredSuspensions(ctx context.Context) (int, error) {
+	now := time.Now()
+	expired, err := u.repo.FindAll(ctx, 1, suspensionBatchSize, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND suspended_until <= ?", entity.StatusSuspended, now)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired suspensions: %w", err)
+	}
+
+	// a failing user does not keep the others suspended
+	reactivated := 0
+	var errs []error
+	for i := range expired.Data {
+		if err := u.reactivate(ctx, &expired.Data[i]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to reactivate user %d: %w", expired.Data[i].ID, err))
+			continue
+		}
+		reactivated++
+	}
+	return reactivated, errors.Join(errs...)
+}
+
+func (u *userUsecaseImpl) StartSuspensionJob(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := u.ReactivateExpiredSuspensions(ctx); err != nil {
+					log.Printf("account: suspension job: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// reactivate ends an expired suspension on behalf of the system
+func (u *userUsecaseImpl) reactivate(ctx context.Context, user *entity.User) error {
+	return u.applyStatus(ctx, user, entity.SystemActorID, entity.StatusActive, "suspension expired", nil)
+}
+
+// applyStatus saves the status change together with its history row, and the revocation of the sessions
+// when the user can no longer log in
+func (u *userUsecaseImpl) applyStatus(ctx context.Context, user *entity.User, actorID uint, status, reason string, until *time.Time) error {
+	history, err := user.TransitionTo(status, reason, actorID, until, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return u.WithTransaction(ctx, func(ctx context.Context) error {
+		// only the status columns, the user was read without a lock
+		err := u.repo.UpdateFields(ctx, user.ID, map[string]interface{}{
+			"status":          user.Status,
+			"suspended_until": user.SuspendedUntil,
+			"updated_at":      user.UpdatedAt,
+			"updated_by":      user.UpdatedBy,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		if err := u.historyRepo.Create(ctx, history); err != nil {
+			return fmt.Errorf("failed to write status history: %w", err)
+		}
+		if status != entity.StatusActive {
+			return u.revokeSessions(ctx, user.ID)
+		}
+		return nil
+	})
+}
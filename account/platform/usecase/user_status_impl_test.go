@@ -0,0 +1,34 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/platform/usecase"
+	"github.com/budimanlai/go-core/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserUsecase_ReactivateExpiredSuspensions(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	users := &fakeUserRepo{
+		users: map[uint]*entity.User{
+			1: {ID: 1, Email: "john@example.com", Status: entity.StatusSuspended, SuspendedUntil: &expired},
+			2: {ID: 2, Email: "jane@example.com", Status: entity.StatusSuspended, SuspendedUntil: &expired},
+		},
+		failing: map[uint]bool{1: true},
+	}
+	history := &fakeStatusHistoryRepo{}
+	hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	uc := usecase.NewUserUsecase(openTxDB(t), users, history, hasher, nil)
+
+	// a failing user does not keep the others suspended
+	reactivated, err := uc.ReactivateExpiredSuspensions(context.Background())
+	assert.ErrorContains(t, err, "failed to reactivate user 1")
+	assert.Equal(t, 1, reactivated)
+	assert.Equal(t, entity.StatusSuspended, users.users[1].Status)
+	assert.Equal(t, entity.StatusActive, users.users[2].Status)
+	assert.Nil(t, users.users[2].SuspendedUntil)
+}
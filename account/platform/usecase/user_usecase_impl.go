@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -9,31 +10,54 @@ import (
 	"github.com/budimanlai/go-core/account/domain/repository"
 	"github.com/budimanlai/go-core/account/domain/usecase"
 	"github.com/budimanlai/go-core/account/dto"
-	"github.com/budimanlai/go-core/account/platform/security"
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/security"
+	"github.com/budimanlai/go-core/service"
 	"github.com/budimanlai/go-pkg/helpers"
 
+	auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
+	auth_usecase "github.com/budimanlai/go-core/auth/domain/usecase"
+
 	"github.com/jinzhu/copier"
 	"gorm.io/gorm"
 )
 
 type userUsecaseImpl struct {
+	base.BaseUsecase[entity.User]
+
 	repo             repository.UserRepository
+	historyRepo      repository.StatusHistoryRepository
 	hasher           security.PasswordHasher
+	passwordPolicy   auth_usecase.PasswordPolicyUsecase
+	sessionUC        auth_usecase.UserSessionUsecase
+	verification     usecase.VerificationConfig
+	mailer           service.SMTPMailService
+	avatarStorage    service.StorageService
+	avatar           usecase.AvatarConfig
+	regionValidator  usecase.RegionValidator
+	deletion         usecase.DeletionConfig
+	personalData     []usecase.PersonalDataHandler
 	CustomToResponse func(*entity.User) interface{}
 }
 
-func NewUserUsecase(repo repository.UserRepository, hasher security.PasswordHasher) usecase.UserUsecase {
+// NewUserUsecase creates the account usecase, sessionUC is the auth module usecase issuing login tokens
+func NewUserUsecase(db *gorm.DB, repo repository.UserRepository, historyRepo repository.StatusHistoryRepository,
+	hasher security.PasswordHasher, sessionUC auth_usecase.UserSessionUsecase) usecase.UserUsecase {
 	return &userUsecaseImpl{
+		BaseUsecase:      base.NewBaseUsecase(repo, db),
 		repo:             repo,
+		historyRepo:      historyRepo,
 		hasher:           hasher,
+		sessionUC:        sessionUC,
+		deletion:         usecase.DeletionConfig{GracePeriod: 30 * 24 * time.Hour},
 		CustomToResponse: nil,
 	}
 }
 
-func (u *userUsecaseImpl) Register(req *dto.RegisterRequest) (interface{}, error) {
+func (u *userUsecaseImpl) Register(ctx context.Context, req *dto.RegisterRequest) (interface{}, error) {
 	// Check if email exists
-	existingUser, err := u.repo.FindByEmail(req.Email)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	existingUser, err := u.findBy(ctx, "email", req.Email)
+	if err != nil {
 		return nil, fmt.Errorf("failed to check email: %w", err)
 	}
 	if existingUser != nil {
@@ -41,8 +65,8 @@ func (u *userUsecaseImpl) Register(req *dto.RegisterRequest) (interface{}, error
 	}
 
 	// Check if username exists
-	existingUser, err = u.repo.FindByUsername(req.Username)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	existingUser, err = u.findBy(ctx, "username", req.Username)
+	if err != nil {
 		return nil, fmt.Errorf("failed to check username: %w", err)
 	}
 	if existingUser != nil {
@@ -50,19 +74,34 @@ func (u *userUsecaseImpl) Register(req *dto.RegisterRequest) (interface{}, error
 	}
 
 	// Check if handphone exists
-	existingUser, err = u.repo.FindByHandphone(req.Handphone)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	existingUser, err = u.findBy(ctx, "handphone", req.Handphone)
+	if err != nil {
 		return nil, fmt.Errorf("failed to check handphone: %w", err)
 	}
 	if existingUser != nil {
 		return nil, errors.New("handphone already registered")
 	}
 
+	if err := u.validateRegion(ctx, req.CountryID, req.ProvinceID, req.CityID, req.DistrictID, req.SubdistrictID); err != nil {
+		return nil, err
+	}
+
+	// Check password policy
+	if u.passwordPolicy != nil {
+		identity := &auth_entity.User{Username: req.Username, Email: req.Email, Handphone: req.Handphone}
+		if err := u.passwordPolicy.Validate(ctx, identity, req.Password); err != nil {
+			return nil, err
+		}
+	}
+
 	// Hash password
 	hashedPassword, err := u.hasher.HashPassword(req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
+	if hashedPassword == "" {
+		return nil, errors.New("failed to hash password")
+	}
 
 	// Generate auth key
 	authKey := helpers.GenerateRandomString(16)
@@ -77,38 +116,89 @@ func (u *userUsecaseImpl) Register(req *dto.RegisterRequest) (interface{}, error
 	}
 	user.AuthKey = authKey
 	user.PasswordHash = hashedPassword
-	user.Status = "active"
+	// the user becomes active once the email is verified when login requires it
+	user.Status = entity.StatusActive
+	if u.verification.RequireVerifiedForLogin {
+		user.Status = entity.StatusPendingVerification
+	}
 	user.LoginDashboard = "N"
 	user.VerificationToken = &verificationToken
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
-	if err := u.repo.Create(&user); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+	sendVerification := u.mailer != nil && u.verification.Secret != ""
+	if sendVerification {
+		user.VerificationSentAt = &now
+	}
+
+	err = u.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.Create(ctx, &user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		if u.passwordPolicy != nil {
+			return u.passwordPolicy.Remember(ctx, user.ID, user.PasswordHash)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// a failed email does not undo the registration, the user can ask for a new link
+	if sendVerification {
+		_ = u.sendVerification(&user)
 	}
 
 	return u.toResponse(&user), nil
 }
 
-func (u *userUsecaseImpl) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
-	user, err := u.repo.FindByUsername(req.Username)
+func (u *userUsecaseImpl) Login(ctx context.Context, req *dto.LoginRequest, appID int, fromIP, userAgent string) (*dto.LoginResponse, error) {
+	if u.sessionUC == nil {
+		return nil, errors.New("login is not available")
+	}
+
+	user, err := u.repo.FindOne(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where("username = ? OR email = ?", req.Username, req.Username)
+	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid username or password")
-		}
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
-
-	if !u.hasher.CheckPasswordHash(req.Password, user.PasswordHash) {
+	if user == nil || !u.hasher.CheckPasswordHash(req.Password, user.PasswordHash) {
 		return nil, errors.New("invalid username or password")
 	}
 
+	if user.IsSuspensionExpired(time.Now()) {
+		if err := u.reactivate(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if user.Status == entity.StatusPendingVerification {
+		return nil, errEmailNotVerified
+	}
+
 	if !user.IsActive() {
 		return nil, errors.New("user account is not active")
 	}
 
-	// TODO: Generate JWT token here
-	token := "jwt_token_placeholder"
+	if u.verification.RequireVerifiedForLogin && !user.IsVerified() {
+		return nil, errEmailNotVerified
+	}
+
+	// Upgrade legacy or weaker hash while the plain password is known
+	if u.hasher.NeedsRehash(user.PasswordHash) {
+		if hashedPassword, err := u.hasher.HashPassword(req.Password); err == nil && hashedPassword != "" {
+			u.repo.UpdateFields(ctx, user.ID, map[string]interface{}{
+				"password_hash": hashedPassword,
+			})
+		}
+	}
+
+	// same session and token as an auth module login, so the token works on both modules
+	token, err := u.sessionUC.GenerateToken(ctx, user.ID, appID, fromIP, userAgent)
+	if err != nil {
+		return nil, err
+	}
 
 	return &dto.LoginResponse{
 		Token: token,
@@ -116,54 +206,83 @@ func (u *userUsecaseImpl) Login(req *dto.LoginRequest) (*dto.LoginResponse, erro
 	}, nil
 }
 
-func (u *userUsecaseImpl) GetByID(id uint) (*dto.UserResponse, error) {
-	user, err := u.repo.FindByID(id)
+func (u *userUsecaseImpl) GetByID(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	user, err := u.findUser(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
-		}
-		return nil, fmt.Errorf("failed to find user: %w", err)
+		return nil, err
 	}
 	return u.toResponse(user).(*dto.UserResponse), nil
 }
 
-func (u *userUsecaseImpl) Update(id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
-	user, err := u.repo.FindByID(id)
+func (u *userUsecaseImpl) UpdateProfile(ctx context.Context, id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	user, err := u.findUser(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
-		}
-		return nil, fmt.Errorf("failed to find user: %w", err)
+		return nil, err
 	}
 
 	if err := copier.CopyWithOption(user, req, copier.Option{IgnoreEmpty: true}); err != nil {
 		return nil, fmt.Errorf("failed to copy request to user: %w", err)
 	}
 
+	if err := u.validateRegion(ctx, user.CountryID, user.ProvinceID, user.CityID, user.DistrictID, user.SubdistrictID); err != nil {
+		return nil, err
+	}
+
 	user.UpdatedAt = time.Now()
 	user.UpdatedBy = id
 
-	if err := u.repo.Update(user); err != nil {
+	// only the columns of the request, the user was read without a lock
+	fields := profileFields(req)
+	fields["updated_at"] = user.UpdatedAt
+	fields["updated_by"] = user.UpdatedBy
+	if err := u.repo.UpdateFields(ctx, id, fields); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
 	return u.toResponse(user).(*dto.UserResponse), nil
 }
 
-func (u *userUsecaseImpl) Delete(id uint) error {
-	user, err := u.repo.FindByID(id)
+// profileFields returns the columns of the fields set in the request
+func profileFields(req *dto.UpdateUserRequest) map[string]interface{} {
+	fields := map[string]interface{}{}
+	setField(fields, "fullname", req.Fullname)
+	setField(fields, "dob", req.Dob)
+	setField(fields, "gender", req.Gender)
+	setField(fields, "address", req.Address)
+	setField(fields, "zipcode", req.Zipcode)
+	setField(fields, "district_id", req.DistrictID)
+	setField(fields, "subdistrict_id", req.SubdistrictID)
+	setField(fields, "city_id", req.CityID)
+	setField(fields, "province_id", req.ProvinceID)
+	setField(fields, "country_id", req.CountryID)
+	return fields
+}
+
+// setField sets the column when the request field is present
+func setField[T any](fields map[string]interface{}, column string, value *T) {
+	if value != nil {
+		fields[column] = *value
+	}
+}
+
+func (u *userUsecaseImpl) DeleteUser(ctx context.Context, actorID, id uint, reason string) error {
+	user, err := u.findUser(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
-		}
-		return fmt.Errorf("failed to find user: %w", err)
+		return err
 	}
 
-	user.SoftDelete()
-	return u.repo.Update(user)
+	return u.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.applyStatus(ctx, user, actorID, entity.StatusDeleted, reason, nil); err != nil {
+			return err
+		}
+		if err := u.repo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		return nil
+	})
 }
 
-func (u *userUsecaseImpl) List(page, pageSize int) (*dto.ListUserResponse, error) {
+func (u *userUsecaseImpl) List(ctx context.Context, page, pageSize int) (*dto.ListUserResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -171,105 +290,101 @@ func (u *userUsecaseImpl) List(page, pageSize int) (*dto.ListUserResponse, error
 		pageSize = 10
 	}
 
-	offset := (page - 1) * pageSize
-	users, total, err := u.repo.List(pageSize, offset)
+	result, err := u.repo.FindAll(ctx, page, pageSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
 
-	userResponses := make([]*dto.UserResponse, len(users))
-	if err := copier.Copy(&userResponses, &users); err != nil {
+	userResponses := make([]*dto.UserResponse, len(result.Data))
+	if err := copier.Copy(&userResponses, &result.Data); err != nil {
 		return nil, fmt.Errorf("failed to copy users to response: %w", err)
 	}
 
 	return &dto.ListUserResponse{
 		Users:      userResponses,
-		TotalCount: total,
-		Page:       page,
-		PageSize:   pageSize,
+		TotalCount: result.Total,
+		Page:       result.Page,
+		PageSize:   result.Limit,
 	}, nil
 }
 
-func (u *userUsecaseImpl) Activate(id uint) error {
-	user, err := u.repo.FindByID(id)
+func (u *userUsecaseImpl) EnableDashboard(ctx context.Context, id uint) error {
+	user, err := u.findUser(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
-		}
-		return fmt.Errorf("failed to find user: %w", err)
+		return err
 	}
 
-	user.Activate()
-	return u.repo.Update(user)
-}
-
-func (u *userUsecaseImpl) Deactivate(id uint) error {
-	user, err := u.repo.FindByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
-		}
-		return fmt.Errorf("failed to find user: %w", err)
+	if u.verification.RequireVerifiedForDashboard && !user.IsVerified() {
+		return errEmailNotVerified
 	}
 
-	user.Deactivate()
-	return u.repo.Update(user)
+	user.EnableDashboardAccess()
+	return u.updateDashboardAccess(ctx, user)
 }
 
-func (u *userUsecaseImpl) Suspend(id uint) error {
-	user, err := u.repo.FindByID(id)
+func (u *userUsecaseImpl) DisableDashboard(ctx context.Context, id uint) error {
+	user, err := u.findUser(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
-		}
-		return fmt.Errorf("failed to find user: %w", err)
+		return err
 	}
 
-	user.Suspend()
-	return u.repo.Update(user)
+	user.DisableDashboardAccess()
+	return u.updateDashboardAccess(ctx, user)
 }
 
-func (u *userUsecaseImpl) VerifyEmail(token string) error {
-	user, err := u.repo.FindByVerificationToken(token)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("invalid verification token")
-		}
-		return fmt.Errorf("failed to find user: %w", err)
-	}
+func (u *userUsecaseImpl) updateDashboardAccess(ctx context.Context, user *entity.User) error {
+	return u.repo.UpdateFields(ctx, user.ID, map[string]interface{}{
+		"login_dashboard": user.LoginDashboard,
+		"updated_at":      user.UpdatedAt,
+	})
+}
 
-	user.ClearVerificationToken()
-	return u.repo.Update(user)
+func (u *userUsecaseImpl) SetRegionValidator(validator usecase.RegionValidator) {
+	u.regionValidator = validator
 }
 
-func (u *userUsecaseImpl) EnableDashboard(id uint) error {
-	user, err := u.repo.FindByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
-		}
-		return fmt.Errorf("failed to find user: %w", err)
+func (u *userUsecaseImpl) validateRegion(ctx context.Context, countryID string, provinceID, cityID, districtID, subdistrictID uint) error {
+	if u.regionValidator == nil {
+		return nil
 	}
+	return u.regionValidator.ValidateRegion(ctx, countryID, provinceID, cityID, districtID, subdistrictID)
+}
 
-	user.EnableDashboardAccess()
-	return u.repo.Update(user)
+func (u *userUsecaseImpl) SetPasswordPolicy(policy auth_usecase.PasswordPolicyUsecase) {
+	u.passwordPolicy = policy
+}
+
+func (u *userUsecaseImpl) SetCustomResponse(customToResponse func(*entity.User) interface{}) {
+	u.CustomToResponse = customToResponse
 }
 
-func (u *userUsecaseImpl) DisableDashboard(id uint) error {
-	user, err := u.repo.FindByID(id)
+// findUser returns the user with the given ID, or an error when it does not exist
+func (u *userUsecaseImpl) findUser(ctx context.Context, id uint) (*entity.User, error) {
+	user, err := u.repo.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
-		}
-		return fmt.Errorf("failed to find user: %w", err)
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
 	}
+	return user, nil
+}
 
-	user.DisableDashboardAccess()
-	return u.repo.Update(user)
+// findBy returns the user matching the column value, or nil when none
+func (u *userUsecaseImpl) findBy(ctx context.Context, column string, value interface{}) (*entity.User, error) {
+	return u.repo.FindOne(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where(column+" = ?", value)
+	})
 }
 
-func (u *userUsecaseImpl) SetCustomResponse(customToResponse func(*entity.User) interface{}) {
-	u.CustomToResponse = customToResponse
+func (u *userUsecaseImpl) revokeSessions(ctx context.Context, id uint) error {
+	if u.sessionUC == nil {
+		return nil
+	}
+	if err := u.sessionUC.RevokeSessionsByUserID(ctx, id); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
 }
 
 func (u *userUsecaseImpl) toResponse(user *entity.User) interface{} {
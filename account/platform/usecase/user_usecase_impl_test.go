@@ -0,0 +1,43 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/budimanlai/go-core/account/platform/usecase"
+	"github.com/budimanlai/go-core/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserUsecase_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeUserRepo{users: map[uint]*entity.User{
+		1: {ID: 1, Email: "john@example.com", Fullname: "John", Status: entity.StatusActive},
+	}}
+	hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	uc := usecase.NewUserUsecase(openTxDB(t), users, &fakeStatusHistoryRepo{}, hasher, nil)
+
+	fullname := "John Doe"
+
+	out, err := uc.UpdateProfile(ctx, 1, &dto.UpdateUserRequest{Fullname: &fullname})
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", out.Fullname)
+
+	// only the request columns, a concurrent password change or deletion request is kept
+	require.Len(t, users.updates, 1)
+	assert.ElementsMatch(t, []string{"fullname", "updated_at", "updated_by"}, keys(users.updates[0]))
+
+	require.NoError(t, uc.DisableDashboard(ctx, 1))
+	assert.ElementsMatch(t, []string{"login_dashboard", "updated_at"}, keys(users.updates[1]))
+}
+
+func keys(fields map[string]interface{}) []string {
+	out := make([]string, 0, len(fields))
+	for key := range fields {
+		out = append(out, key)
+	}
+	return out
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	"github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/budimanlai/go-core/service"
+	"github.com/budimanlai/go-pkg/helpers"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const verificationTokenType = "email_verification"
+
+var errEmailNotVerified = errors.New("email is not verified")
+
+func (u *userUsecaseImpl) SetVerification(config usecase.VerificationConfig, mailer service.SMTPMailService) {
+	if config.Template == "" {
+		config.Template = "email_verification"
+	}
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.ResendInterval <= 0 {
+		config.ResendInterval = time.Minute
+	}
+	u.verification = config
+	u.mailer = mailer
+}
+
+// ResendVerification sends a new verification link and invalidates the previous one.
+// Unknown and already verified emails return no error so the endpoint does not reveal registered emails.
+func (u *userUsecaseImpl) ResendVerification(ctx context.Context, req *dto.ResendVerificationRequest) error {
+	if u.mailer == nil || u.verification.Secret == "" {
+		return errors.New("email verification is not available")
+	}
+
+	user, err := u.findBy(ctx, "email", req.Email)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil || user.IsVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	if user.VerificationSentAt != nil && now.Before(user.VerificationSentAt.Add(u.verification.ResendInterval)) {
+		return errors.New("please wait before requesting another verification email")
+	}
+
+	verificationToken := helpers.GenerateRandomString(32)
+	user.VerificationToken = &verificationToken
+	user.VerificationSentAt = &now
+	user.UpdatedAt = now
+	err = u.repo.UpdateFields(ctx, user.ID, map[string]interface{}{
+		"verification_token":   verificationToken,
+		"verification_sent_at": now,
+		"updated_at":           now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return u.sendVerification(user)
+}
+
+func (u *userUsecaseImpl) VerifyEmail(ctx context.Context, token string) error {
+	// without a secret only the raw token stored at registration is accepted
+	if u.verification.Secret == "" {
+		user, err := u.findBy(ctx, "verification_token", token)
+		if err != nil {
+			return fmt.Errorf("failed to find user: %w", err)
+		}
+		if user == nil {
+			return errors.New("invalid verification token")
+		}
+
+		return u.markVerified(ctx, user)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(u.verification.Secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return errors.New("verification token has expired")
+		}
+		return errors.New("invalid verification token")
+	}
+
+	typ, _ := claims["typ"].(string)
+	sub, _ := claims["sub"].(string)
+	nonce, _ := claims["vtk"].(string)
+	id, err := strconv.ParseUint(sub, 10, 32)
+	if typ != verificationTokenType || err != nil {
+		return errors.New("invalid verification token")
+	}
+
+	user, err := u.repo.FindByID(ctx, uint(id))
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return errors.New("invalid verification token")
+	}
+	if user.IsVerified() {
+		return nil
+	}
+
+	// a resend replaces the stored token, so older links stop working
+	if subtle.ConstantTimeCompare([]byte(nonce), []byte(*user.VerificationToken)) != 1 {
+		return errors.New("invalid verification token")
+	}
+
+	return u.markVerified(ctx, user)
+}
+
+// markVerified clears the verification token and activates a user waiting for the verification
+func (u *userUsecaseImpl) markVerified(ctx context.Context, user *entity.User) error {
+	user.ClearVerificationToken()
+	return u.WithTransaction(ctx, func(ctx context.Context) error {
+		err := u.repo.UpdateFields(ctx, user.ID, map[string]interface{}{
+			"verification_token": nil,
+			"updated_at":         user.UpdatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		if user.Status == entity.StatusPendingVerification {
+			return u.applyStatus(ctx, user, user.ID, entity.StatusActive, "email verified", nil)
+		}
+		return nil
+	})
+}
+
+func (u *userUsecaseImpl) CheckDashboardAccess(ctx context.Context, id uint) error {
+	user, err := u.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsActive() {
+		return errors.New("user account is not active")
+	}
+	if !user.CanLoginDashboard() {
+		return errors.New("dashboard access is not allowed")
+	}
+	if u.verification.RequireVerifiedForDashboard && !user.IsVerified() {
+		return errEmailNotVerified
+	}
+	return nil
+}
+
+func (u *userUsecaseImpl) sendVerification(user *entity.User) error {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"typ": verificationTokenType,
+		"sub": strconv.FormatUint(uint64(user.ID), 10),
+		"vtk": *user.VerificationToken,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(u.verification.TTL).Unix(),
+	}).SignedString([]byte(u.verification.Secret))
+	if err != nil {
+		return fmt.Errorf("failed to sign verification token: %w", err)
+	}
+
+	link := token
+	if u.verification.URL != "" {
+		link = u.verification.URL + "?token=" + url.QueryEscape(token)
+	}
+
+	return u.mailer.SendWithTemplate(user.Email, u.verification.Template, map[string]interface{}{
+		"fullname":   user.Fullname,
+		"username":   user.Username,
+		"link":       link,
+		"token":      token,
+		"expires_in": int(u.verification.TTL.Minutes()),
+	})
+}
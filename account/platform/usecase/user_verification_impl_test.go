@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/account/domain/entity"
+	dom_usecase "github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/account/dto"
+	"github.com/budimanlai/go-core/account/platform/usecase"
+	"github.com/budimanlai/go-core/security"
+	"github.com/budimanlai/go-core/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMailer keeps the template data of the sent emails
+type fakeMailer struct {
+	service.SMTPMailService
+	sent []map[string]interface{}
+}
+
+func (m *fakeMailer) SendWithTemplate(to, templateName string, templateData map[string]interface{}) error {
+	m.sent = append(m.sent, templateData)
+	return nil
+}
+
+func TestUserUsecase_Verification(t *testing.T) {
+	ctx := context.Background()
+	const secret = "verification-secret"
+
+	setup := func(config dom_usecase.VerificationConfig) (dom_usecase.UserUsecase, *fakeUserRepo, *fakeMailer) {
+		pending := ""
+		users := &fakeUserRepo{users: map[uint]*entity.User{
+			1: {ID: 1, Email: "john@example.com", Status: entity.StatusPendingVerification, VerificationToken: &pending},
+		}}
+		hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+		uc := usecase.NewUserUsecase(openTxDB(t), users, &fakeStatusHistoryRepo{}, hasher, nil)
+		mailer := &fakeMailer{}
+		config.Secret = secret
+		uc.SetVerification(config, mailer)
+		return uc, users, mailer
+	}
+
+	t.Run("Resend throttle", func(t *testing.T) {
+		uc, users, mailer := setup(dom_usecase.VerificationConfig{ResendInterval: time.Hour})
+		req := &dto.ResendVerificationRequest{Email: "john@example.com"}
+
+		require.NoError(t, uc.ResendVerification(ctx, req))
+		assert.Error(t, uc.ResendVerification(ctx, req))
+		assert.Len(t, mailer.sent, 1)
+
+		sentAt := time.Now().Add(-2 * time.Hour)
+		users.users[1].VerificationSentAt = &sentAt
+		require.NoError(t, uc.ResendVerification(ctx, req))
+		assert.Len(t, mailer.sent, 2)
+
+		// unknown emails are not revealed
+		assert.NoError(t, uc.ResendVerification(ctx, &dto.ResendVerificationRequest{Email: "nobody@example.com"}))
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		uc, users, mailer := setup(dom_usecase.VerificationConfig{})
+		require.NoError(t, uc.ResendVerification(ctx, &dto.ResendVerificationRequest{Email: "john@example.com"}))
+		require.Len(t, mailer.sent, 1)
+
+		require.NoError(t, uc.VerifyEmail(ctx, mailer.sent[0]["token"].(string)))
+		assert.True(t, users.users[1].IsVerified())
+		assert.Equal(t, entity.StatusActive, users.users[1].Status)
+	})
+
+	t.Run("Token expiry", func(t *testing.T) {
+		uc, users, _ := setup(dom_usecase.VerificationConfig{})
+		sign := func(exp time.Time) string {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"typ": "email_verification",
+				"sub": strconv.Itoa(1),
+				"vtk": "",
+				"exp": exp.Unix(),
+			}).SignedString([]byte(secret))
+			require.NoError(t, err)
+			return token
+		}
+
+		err := uc.VerifyEmail(ctx, sign(time.Now().Add(-time.Minute)))
+		assert.EqualError(t, err, "verification token has expired")
+		assert.False(t, users.users[1].IsVerified())
+
+		require.NoError(t, uc.VerifyEmail(ctx, sign(time.Now().Add(time.Minute))))
+		assert.True(t, users.users[1].IsVerified())
+	})
+}
+
+func TestUserUsecase_CheckDashboardAccess(t *testing.T) {
+	ctx := context.Background()
+	token := "pending"
+	users := &fakeUserRepo{users: map[uint]*entity.User{
+		1: {ID: 1, Status: entity.StatusActive, LoginDashboard: "Y"},
+		2: {ID: 2, Status: entity.StatusActive, LoginDashboard: "Y", VerificationToken: &token},
+		3: {ID: 3, Status: entity.StatusActive, LoginDashboard: "N"},
+	}}
+	hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	uc := usecase.NewUserUsecase(openTxDB(t), users, &fakeStatusHistoryRepo{}, hasher, nil)
+
+	assert.NoError(t, uc.CheckDashboardAccess(ctx, 2), "verification not required")
+
+	uc.SetVerification(dom_usecase.VerificationConfig{Secret: "secret", RequireVerifiedForDashboard: true}, &fakeMailer{})
+	assert.NoError(t, uc.CheckDashboardAccess(ctx, 1))
+	assert.EqualError(t, uc.CheckDashboardAccess(ctx, 2), "email is not verified", "enabled before the verification was required")
+	assert.Error(t, uc.CheckDashboardAccess(ctx, 3))
+}
@@ -0,0 +1,97 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/gofiber/fiber/v2"
+
+	dom_apikey_repository "github.com/budimanlai/go-core/apikey/domain/repository"
+	dom_apikey_usecase "github.com/budimanlai/go-core/apikey/domain/usecase"
+	apikey_http "github.com/budimanlai/go-core/apikey/platform/http"
+	impl_apikey_repository "github.com/budimanlai/go-core/apikey/platform/repository"
+	impl_apikey_usecase "github.com/budimanlai/go-core/apikey/platform/usecase"
+	apikey_service "github.com/budimanlai/go-core/apikey/service"
+	auth_repository "github.com/budimanlai/go-core/auth/repository"
+)
+
+type APIKeyManagerDefaultImpl struct {
+	factory *base.Factory
+
+	// repository
+	APIKeyRepo dom_apikey_repository.APIKeyRepository
+
+	// usecase
+	APIKeyUsecase dom_apikey_usecase.APIKeyUsecase
+
+	// handler
+	APIKeyHandler *apikey_http.APIKeyHandler
+
+	// service
+	Config dom_apikey_usecase.APIKeyConfig
+
+	// RateLimiter counts the requests of the keys having a rate limit, defaults to an in-process limiter
+	RateLimiter apikey_service.RateLimiter
+
+	// RateLimitWindow is the window of the key rate limits, defaults to 1 minute
+	RateLimitWindow time.Duration
+
+	// Header is the header carrying the key, defaults to "X-API-Key"
+	Header string
+
+	// middleware
+	// Middleware authenticates the machine clients, it is set by InitManager
+	Middleware fiber.Handler
+
+	// AdminMiddleware is for the routes managing the keys
+	AdminMiddleware fiber.Handler
+}
+
+func NewAPIKeyManagerDefaultImpl(factory *base.Factory) *APIKeyManagerDefaultImpl {
+	return &APIKeyManagerDefaultImpl{
+		factory:         factory,
+		RateLimitWindow: time.Minute,
+		Header:          apikey_http.DefaultAPIKeyHeader,
+	}
+}
+
+func (m *APIKeyManagerDefaultImpl) SetConfig(config dom_apikey_usecase.APIKeyConfig) {
+	m.Config = config
+}
+
+func (m *APIKeyManagerDefaultImpl) SetRateLimiter(limiter apikey_service.RateLimiter, window time.Duration) {
+	m.RateLimiter = limiter
+	m.RateLimitWindow = window
+}
+
+func (m *APIKeyManagerDefaultImpl) SetAdminMiddleware(middleware fiber.Handler) {
+	m.AdminMiddleware = middleware
+}
+
+func (m *APIKeyManagerDefaultImpl) InitManager() {
+	m.APIKeyRepo = impl_apikey_repository.NewAPIKeyRepository(m.factory)
+	m.APIKeyUsecase = impl_apikey_usecase.NewAPIKeyUsecase(m.factory.DB, m.APIKeyRepo,
+		auth_repository.NewUserRepositoryImpl(m.factory), m.Config)
+
+	if m.RateLimiter == nil {
+		m.RateLimiter = apikey_service.NewRateLimiterMemoryImpl()
+	}
+	m.Middleware = apikey_http.NewAPIKeyMiddleware(m.APIKeyUsecase, m.RateLimiter, m.Header, m.RateLimitWindow)
+
+	// the key management routes are never left open
+	if m.AdminMiddleware == nil {
+		m.AdminMiddleware = func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusUnauthorized) }
+	}
+}
+
+func (m *APIKeyManagerDefaultImpl) SetRoute(app fiber.Router) {
+	m.APIKeyHandler = apikey_http.NewAPIKeyHandler(m.APIKeyUsecase)
+
+	adminAPI := app.Group("/api-keys", m.AdminMiddleware)
+	adminAPI.Get("/", m.APIKeyHandler.List)
+	adminAPI.Post("/", m.APIKeyHandler.Create)
+	adminAPI.Get("/:id", m.APIKeyHandler.GetByID)
+	adminAPI.Put("/:id", m.APIKeyHandler.Update)
+	adminAPI.Delete("/:id", m.APIKeyHandler.Revoke)
+	adminAPI.Post("/:id/rotate", m.APIKeyHandler.Rotate)
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// APIKey authenticates a machine client. Only the hash of the key is stored, the prefix is the public part
+// of the key used to find it.
+type APIKey struct {
+	ID      uint
+	Name    string
+	Prefix  string
+	KeyHash string
+	// Scopes is the space separated list of granted scopes
+	Scopes string
+	// OwnerUserID is the user the client acts for, 0 for a client acting on its own
+	OwnerUserID uint
+	// RateLimit is the maximum number of requests per window, 0 is unlimited
+	RateLimit  int
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	LastUsedIP string
+	RevokedAt  *time.Time
+	CreatedBy  uint
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ScopeList returns the granted scopes
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// HasScope checks if the key is granted the scope
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.ScopeList(), scope)
+}
+
+// IsRevoked checks if the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// IsExpired checks if the key has passed its expiry
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !k.ExpiresAt.After(now)
+}
+
+// IsUsable checks if the key can authenticate a request
+func (k *APIKey) IsUsable(now time.Time) bool {
+	return !k.IsRevoked() && !k.IsExpired(now)
+}
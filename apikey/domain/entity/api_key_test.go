@@ -0,0 +1,29 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/apikey/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_HasScope(t *testing.T) {
+	key := entity.APIKey{Scopes: "orders.read  orders.write"}
+
+	assert.Equal(t, []string{"orders.read", "orders.write"}, key.ScopeList())
+	assert.True(t, key.HasScope("orders.write"))
+	assert.False(t, key.HasScope("orders"))
+	assert.False(t, (&entity.APIKey{}).HasScope(""))
+}
+
+func TestAPIKey_IsUsable(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	assert.True(t, (&entity.APIKey{}).IsUsable(now))
+	assert.True(t, (&entity.APIKey{ExpiresAt: &future}).IsUsable(now))
+	assert.False(t, (&entity.APIKey{ExpiresAt: &past}).IsUsable(now), "expired")
+	assert.False(t, (&entity.APIKey{RevokedAt: &past}).IsUsable(now), "revoked")
+}
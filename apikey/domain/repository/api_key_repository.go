@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	"github.com/budimanlai/go-core/apikey/domain/entity"
+	"github.com/budimanlai/go-core/apikey/models"
+)
+
+type APIKeyRepository interface {
+	base.BaseRepository[entity.APIKey, models.APIKey]
+}
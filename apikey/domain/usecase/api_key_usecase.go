@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/budimanlai/go-core/apikey/domain/entity"
+	"github.com/budimanlai/go-core/apikey/dto"
+	"github.com/budimanlai/go-core/base"
+)
+
+// APIKeyConfig controls the format and the bookkeeping of the API keys.
+type APIKeyConfig struct {
+	// Prefix starts every key so leaked keys are easy to spot, defaults to "gck"
+	Prefix string
+
+	// LastUsedInterval is the minimum time between two writes of the last used time of a key,
+	// defaults to 1 minute
+	LastUsedInterval time.Duration
+}
+
+type APIKeyUsecase interface {
+	base.BaseUsecase[entity.APIKey]
+
+	// Issue creates a new key, the plain key is only returned here
+	Issue(ctx context.Context, actorID uint, req *dto.CreateAPIKeyRequest) (*dto.IssuedAPIKeyResponse, error)
+
+	// List lists the keys, of one owner when ownerID is not zero
+	List(ctx context.Context, ownerID uint, page, pageSize int) (*dto.ListAPIKeyResponse, error)
+
+	GetByID(ctx context.Context, id uint) (*dto.APIKeyResponse, error)
+	UpdateKey(ctx context.Context, id uint, req *dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error)
+
+	// Rotate replaces the secret of the key, the previous key stops working at once
+	Rotate(ctx context.Context, id uint) (*dto.IssuedAPIKeyResponse, error)
+
+	// Revoke disables the key, the row is kept for the record
+	Revoke(ctx context.Context, id uint) error
+
+	// Authenticate returns the usable key matching the plain key and records its use, a key owned by a
+	// user that is not active is rejected
+	Authenticate(ctx context.Context, key, fromIP string) (*entity.APIKey, error)
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/budimanlai/go-pkg/types"
+)
+
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name" validate:"required,max=100"`
+	Scopes      []string   `json:"scopes" validate:"max=50,dive,required,max=100"`
+	OwnerUserID uint       `json:"owner_user_id"`
+	RateLimit   int        `json:"rate_limit" validate:"min=0"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// UpdateAPIKeyRequest replaces the settings of a key, a nil ExpiresAt removes the expiry
+type UpdateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"max=50,dive,required,max=100"`
+	RateLimit int        `json:"rate_limit" validate:"min=0"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+	ID          uint          `json:"id"`
+	Name        string        `json:"name"`
+	Prefix      string        `json:"prefix"`
+	Scopes      []string      `json:"scopes"`
+	OwnerUserID uint          `json:"owner_user_id"`
+	RateLimit   int           `json:"rate_limit"`
+	ExpiresAt   *time.Time    `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time    `json:"last_used_at,omitempty"`
+	LastUsedIP  string        `json:"last_used_ip,omitempty"`
+	RevokedAt   *time.Time    `json:"revoked_at,omitempty"`
+	CreatedBy   uint          `json:"created_by"`
+	CreatedAt   types.UTCTime `json:"created_at"`
+	UpdatedAt   types.UTCTime `json:"updated_at"`
+}
+
+// IssuedAPIKeyResponse carries the plain key, it is only returned when the key is created or rotated
+type IssuedAPIKeyResponse struct {
+	APIKey *APIKeyResponse `json:"api_key"`
+	Key    string          `json:"key"`
+}
+
+type ListAPIKeyResponse struct {
+	APIKeys    []*APIKeyResponse `json:"api_keys"`
+	TotalCount int64             `json:"total_count"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+}
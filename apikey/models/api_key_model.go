@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+type APIKey struct {
+	ID          uint       `gorm:"column:id;primaryKey;autoIncrement"`
+	Name        string     `gorm:"column:name;type:varchar(100);not null"`
+	Prefix      string     `gorm:"column:prefix;type:varchar(32);not null;uniqueIndex"`
+	KeyHash     string     `gorm:"column:key_hash;type:char(64);not null"`
+	Scopes      string     `gorm:"column:scopes;type:text;not null"`
+	OwnerUserID uint       `gorm:"column:owner_user_id;not null;default:0;index"`
+	RateLimit   int        `gorm:"column:rate_limit;not null;default:0"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at"`
+	LastUsedAt  *time.Time `gorm:"column:last_used_at"`
+	LastUsedIP  string     `gorm:"column:last_used_ip;type:varchar(45);not null;default:''"`
+	RevokedAt   *time.Time `gorm:"column:revoked_at"`
+	CreatedBy   uint       `gorm:"column:created_by;not null"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
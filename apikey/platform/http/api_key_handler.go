@@ -0,0 +1,118 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/budimanlai/go-core/apikey/domain/usecase"
+	"github.com/budimanlai/go-core/apikey/dto"
+	auth_http "github.com/budimanlai/go-core/auth/handler/http"
+
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/budimanlai/go-pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyHandler struct {
+	usecase usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(usecase usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *APIKeyHandler) List(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+	ownerID, _ := strconv.ParseUint(c.Query("owner_id", "0"), 10, 32)
+
+	keys, err := h.usecase.List(c.Context(), uint(ownerID), page, pageSize)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", keys)
+}
+
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+
+	issued, err := h.usecase.Issue(c.Context(), auth_http.GetUserID(c), &req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", issued)
+}
+
+func (h *APIKeyHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+
+	apiKey, err := h.usecase.GetByID(c.Context(), uint(id))
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", apiKey)
+}
+
+func (h *APIKeyHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+
+	var req dto.UpdateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequestI18n(c, "app.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+
+	apiKey, err := h.usecase.UpdateKey(c.Context(), uint(id), &req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", apiKey)
+}
+
+func (h *APIKeyHandler) Rotate(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+
+	issued, err := h.usecase.Rotate(c.Context(), uint(id))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", issued)
+}
+
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, "Invalid ID")
+	}
+
+	if err := h.usecase.Revoke(c.Context(), uint(id)); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessI18n(c, "app.success", nil)
+}
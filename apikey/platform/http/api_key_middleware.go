@@ -0,0 +1,63 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/budimanlai/go-core/apikey/domain/usecase"
+	"github.com/budimanlai/go-core/apikey/service"
+	auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
+
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultAPIKeyHeader is the header carrying the API key
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// NewAPIKeyMiddleware authenticates the API key of the header and stores the AuthContext of the key,
+// like the private middleware does for a user token. Keys with a rate limit are counted per window
+// when a limiter is given.
+func NewAPIKeyMiddleware(apiKeyUC usecase.APIKeyUsecase, limiter service.RateLimiter, header string, window time.Duration) fiber.Handler {
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(header)
+		if key == "" {
+			return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+		}
+
+		apiKey, err := apiKeyUC.Authenticate(c.Context(), key, c.IP())
+		if err != nil {
+			return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.invalid_api_key", nil)
+		}
+
+		// a limiter failure lets the request through rather than failing every client
+		if limiter != nil && apiKey.RateLimit > 0 {
+			allowed, err := limiter.Allow(c.Context(), strconv.FormatUint(uint64(apiKey.ID), 10), apiKey.RateLimit, window)
+			if err == nil && !allowed {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(window.Seconds())))
+				return response.ErrorI18n(c, fiber.StatusTooManyRequests, "auth.error.rate_limited", nil)
+			}
+		}
+
+		authCtx := &auth_entity.AuthContext{
+			UserID:   apiKey.OwnerUserID,
+			APIKeyID: apiKey.ID,
+			Scopes:   apiKey.ScopeList(),
+		}
+		c.Locals(auth_entity.AuthContextKey{}, authCtx)
+		c.SetUserContext(auth_entity.InjectAuthContext(c.UserContext(), authCtx))
+		c.Locals("api_key_id", apiKey.ID)
+		if apiKey.OwnerUserID != 0 {
+			c.Locals("user_id", fmt.Sprintf("%v", apiKey.OwnerUserID))
+		}
+		return c.Next()
+	}
+}
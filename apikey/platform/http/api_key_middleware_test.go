@@ -0,0 +1,64 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/apikey/domain/entity"
+	"github.com/budimanlai/go-core/apikey/domain/usecase"
+	apikey_http "github.com/budimanlai/go-core/apikey/platform/http"
+	"github.com/budimanlai/go-core/apikey/service"
+	auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPIKeyUsecase authenticates the keys of its map, other keys are rejected like revoked or expired ones
+type fakeAPIKeyUsecase struct {
+	usecase.APIKeyUsecase
+	keys map[string]*entity.APIKey
+}
+
+func (u *fakeAPIKeyUsecase) Authenticate(ctx context.Context, key, fromIP string) (*entity.APIKey, error) {
+	apiKey, ok := u.keys[key]
+	if !ok {
+		return nil, errors.New("invalid API key")
+	}
+	return apiKey, nil
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	apiKeyUC := &fakeAPIKeyUsecase{keys: map[string]*entity.APIKey{
+		"limited": {ID: 1, OwnerUserID: 7, RateLimit: 2, Scopes: "region.read"},
+	}}
+
+	app := fiber.New()
+	app.Use(apikey_http.NewAPIKeyMiddleware(apiKeyUC, service.NewRateLimiterMemoryImpl(), "", time.Hour))
+	app.Get("/", func(c *fiber.Ctx) error {
+		authCtx := c.Locals(auth_entity.AuthContextKey{}).(*auth_entity.AuthContext)
+		assert.Equal(t, uint(7), authCtx.UserID)
+		assert.Equal(t, uint(1), authCtx.APIKeyID)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	request := func(key string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		if key != "" {
+			req.Header.Set(apikey_http.DefaultAPIKeyHeader, key)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusUnauthorized, request(""))
+	assert.Equal(t, fiber.StatusUnauthorized, request("revoked"))
+
+	assert.Equal(t, fiber.StatusNoContent, request("limited"))
+	assert.Equal(t, fiber.StatusNoContent, request("limited"))
+	assert.Equal(t, fiber.StatusTooManyRequests, request("limited"))
+}
@@ -0,0 +1,53 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/apikey/domain/entity"
+	model "github.com/budimanlai/go-core/apikey/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.APIKey, model.APIKey](apiKeyMapper{})
+}
+
+// apiKeyMapper maps entity.APIKey and model.APIKey without reflection
+type apiKeyMapper struct{}
+
+func (apiKeyMapper) ToModel(e *entity.APIKey, m *model.APIKey) error {
+	m.ID = e.ID
+	m.Name = e.Name
+	m.Prefix = e.Prefix
+	m.KeyHash = e.KeyHash
+	m.Scopes = e.Scopes
+	m.OwnerUserID = e.OwnerUserID
+	m.RateLimit = e.RateLimit
+	m.ExpiresAt = e.ExpiresAt
+	m.LastUsedAt = e.LastUsedAt
+	m.LastUsedIP = e.LastUsedIP
+	m.RevokedAt = e.RevokedAt
+	m.CreatedBy = e.CreatedBy
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+	return nil
+}
+
+func (apiKeyMapper) ToEntity(m *model.APIKey, e *entity.APIKey) error {
+	e.ID = m.ID
+	e.Name = m.Name
+	e.Prefix = m.Prefix
+	e.KeyHash = m.KeyHash
+	e.Scopes = m.Scopes
+	e.OwnerUserID = m.OwnerUserID
+	e.RateLimit = m.RateLimit
+	e.ExpiresAt = m.ExpiresAt
+	e.LastUsedAt = m.LastUsedAt
+	e.LastUsedIP = m.LastUsedIP
+	e.RevokedAt = m.RevokedAt
+	e.CreatedBy = m.CreatedBy
+	e.CreatedAt = m.CreatedAt
+	e.UpdatedAt = m.UpdatedAt
+	return nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	"github.com/budimanlai/go-core/apikey/domain/entity"
+	"github.com/budimanlai/go-core/apikey/domain/repository"
+	"github.com/budimanlai/go-core/apikey/models"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/apikey/domain/entity.APIKey -model github.com/budimanlai/go-core/apikey/models.APIKey
+
+type apiKeyRepositoryImpl struct {
+	base.BaseRepository[entity.APIKey, models.APIKey]
+}
+
+func NewAPIKeyRepository(f *base.Factory) repository.APIKeyRepository {
+	return &apiKeyRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.APIKey, models.APIKey](f),
+	}
+}
@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/budimanlai/go-core/apikey/domain/entity"
+	"github.com/budimanlai/go-core/apikey/domain/repository"
+	"github.com/budimanlai/go-core/apikey/domain/usecase"
+	"github.com/budimanlai/go-core/apikey/dto"
+	auth_repository "github.com/budimanlai/go-core/auth/domain/repository"
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-pkg/types"
+
+	"gorm.io/gorm"
+)
+
+var errInvalidAPIKey = errors.New("invalid API key")
+
+type apiKeyUsecaseImpl struct {
+	base.BaseUsecase[entity.APIKey]
+
+	repo     repository.APIKeyRepository
+	userRepo auth_repository.UserRepository
+	config   usecase.APIKeyConfig
+}
+
+// NewAPIKeyUsecase creates the API key usecase, userRepo finds the owners of the keys so a key stops
+// working with its owner account
+func NewAPIKeyUsecase(db *gorm.DB, repo repository.APIKeyRepository, userRepo auth_repository.UserRepository,
+	config usecase.APIKeyConfig) usecase.APIKeyUsecase {
+	if config.Prefix == "" {
+		config.Prefix = "gck"
+	}
+	if config.LastUsedInterval <= 0 {
+		config.LastUsedInterval = time.Minute
+	}
+
+	return &apiKeyUsecaseImpl{
+		BaseUsecase: base.NewBaseUsecase(repo, db),
+		repo:        repo,
+		userRepo:    userRepo,
+		config:      config,
+	}
+}
+
+func (u *apiKeyUsecaseImpl) Issue(ctx context.Context, actorID uint, req *dto.CreateAPIKeyRequest) (*dto.IssuedAPIKeyResponse, error) {
+	scopes, err := joinScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	prefix, key, hash, err := u.generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	apiKey := entity.APIKey{
+		Name:        req.Name,
+		Prefix:      prefix,
+		KeyHash:     hash,
+		Scopes:      scopes,
+		OwnerUserID: req.OwnerUserID,
+		RateLimit:   req.RateLimit,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedBy:   actorID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := u.repo.Create(ctx, &apiKey); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return &dto.IssuedAPIKeyResponse{APIKey: toResponse(&apiKey), Key: key}, nil
+}
+
+func (u *apiKeyUsecaseImpl) List(ctx context.Context, ownerID uint, page, pageSize int) (*dto.ListAPIKeyResponse, error) {
+	result, err := u.repo.FindAll(ctx, page, pageSize, func(db *gorm.DB) *gorm.DB {
+		if ownerID != 0 {
+			db = db.Where("owner_user_id = ?", ownerID)
+		}
+		return db.Order("id DESC")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	keys := make([]*dto.APIKeyResponse, len(result.Data))
+	for i := range result.Data {
+		keys[i] = toResponse(&result.Data[i])
+	}
+
+	return &dto.ListAPIKeyResponse{
+		APIKeys:    keys,
+		TotalCount: result.Total,
+		Page:       result.Page,
+		PageSize:   result.Limit,
+	}, nil
+}
+
+func (u *apiKeyUsecaseImpl) GetByID(ctx context.Context, id uint) (*dto.APIKeyResponse, error) {
+	apiKey, err := u.findKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(apiKey), nil
+}
+
+func (u *apiKeyUsecaseImpl) UpdateKey(ctx context.Context, id uint, req *dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	apiKey, err := u.findKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.IsRevoked() {
+		return nil, errors.New("API key is revoked")
+	}
+
+	scopes, err := joinScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.Name = req.Name
+	apiKey.Scopes = scopes
+	apiKey.RateLimit = req.RateLimit
+	apiKey.ExpiresAt = req.ExpiresAt
+	apiKey.UpdatedAt = time.Now()
+	if err := u.repo.Update(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return toResponse(apiKey), nil
+}
+
+func (u *apiKeyUsecaseImpl) Rotate(ctx context.Context, id uint) (*dto.IssuedAPIKeyResponse, error) {
+	apiKey, err := u.findKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !apiKey.IsUsable(time.Now()) {
+		return nil, errors.New("API key is revoked or expired")
+	}
+
+	prefix, key, hash, err := u.generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.Prefix = prefix
+	apiKey.KeyHash = hash
+	apiKey.UpdatedAt = time.Now()
+	if err := u.repo.Update(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to rotate API key: %w", err)
+	}
+
+	return &dto.IssuedAPIKeyResponse{APIKey: toResponse(apiKey), Key: key}, nil
+}
+
+func (u *apiKeyUsecaseImpl) Revoke(ctx context.Context, id uint) error {
+	apiKey, err := u.findKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	if apiKey.IsRevoked() {
+		return nil
+	}
+
+	return u.repo.UpdateFields(ctx, id, map[string]interface{}{"revoked_at": time.Now()})
+}
+
+func (u *apiKeyUsecaseImpl) Authenticate(ctx context.Context, key, fromIP string) (*entity.APIKey, error) {
+	prefix, _, ok := strings.Cut(key, ".")
+	if !ok || !strings.HasPrefix(prefix, u.config.Prefix+"_") {
+		return nil, errInvalidAPIKey
+	}
+
+	apiKey, err := u.findBy(ctx, "prefix", prefix)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil || subtle.ConstantTimeCompare([]byte(hashKey(key)), []byte(apiKey.KeyHash)) != 1 {
+		return nil, errInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !apiKey.IsUsable(now) {
+		return nil, errInvalidAPIKey
+	}
+
+	// the key acts for its owner, a suspended, inactive or deleted owner disables it
+	if apiKey.OwnerUserID != 0 {
+		owner, err := u.userRepo.FindByID(ctx, apiKey.OwnerUserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find API key owner: %w", err)
+		}
+		if owner == nil || !owner.IsActive() {
+			return nil, errInvalidAPIKey
+		}
+	}
+
+	// a write per request would be too much for busy clients
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= u.config.LastUsedInterval || apiKey.LastUsedIP != fromIP {
+		u.repo.UpdateFields(ctx, apiKey.ID, map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": fromIP,
+		})
+		apiKey.LastUsedAt = &now
+		apiKey.LastUsedIP = fromIP
+	}
+
+	return apiKey, nil
+}
+
+// generateKey returns a new key as "<prefix>.<secret>", with its prefix and hash
+func (u *apiKeyUsecaseImpl) generateKey() (prefix, key, hash string, err error) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	prefix = u.config.Prefix + "_" + hex.EncodeToString(id)
+	key = prefix + "." + base64.RawURLEncoding.EncodeToString(secret)
+	return prefix, key, hashKey(key), nil
+}
+
+// findKey returns the key with the given ID, or an error when it does not exist
+func (u *apiKeyUsecaseImpl) findKey(ctx context.Context, id uint) (*entity.APIKey, error) {
+	apiKey, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+	if apiKey == nil {
+		return nil, errors.New("API key not found")
+	}
+	return apiKey, nil
+}
+
+func (u *apiKeyUsecaseImpl) findBy(ctx context.Context, column string, value interface{}) (*entity.APIKey, error) {
+	apiKey, err := u.repo.FindOne(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where(column+" = ?", value)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+	return apiKey, nil
+}
+
+// hashKey hashes the whole key, the random secret makes a slow password hash unnecessary
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// joinScopes stores the scopes space separated, so a scope cannot contain spaces
+func joinScopes(scopes []string) (string, error) {
+	for _, scope := range scopes {
+		if strings.ContainsAny(scope, " \t\r\n") {
+			return "", fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+	return strings.Join(scopes, " "), nil
+}
+
+func toResponse(apiKey *entity.APIKey) *dto.APIKeyResponse {
+	return &dto.APIKeyResponse{
+		ID:          apiKey.ID,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		Scopes:      apiKey.ScopeList(),
+		OwnerUserID: apiKey.OwnerUserID,
+		RateLimit:   apiKey.RateLimit,
+		ExpiresAt:   apiKey.ExpiresAt,
+		LastUsedAt:  apiKey.LastUsedAt,
+		LastUsedIP:  apiKey.LastUsedIP,
+		RevokedAt:   apiKey.RevokedAt,
+		CreatedBy:   apiKey.CreatedBy,
+		CreatedAt:   types.UTCTime(apiKey.CreatedAt),
+		UpdatedAt:   types.UTCTime(apiKey.UpdatedAt),
+	}
+}
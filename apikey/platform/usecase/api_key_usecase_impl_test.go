@@ -0,0 +1,164 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/apikey/domain/entity"
+	"github.com/budimanlai/go-core/apikey/domain/repository"
+	dom_usecase "github.com/budimanlai/go-core/apikey/domain/usecase"
+	"github.com/budimanlai/go-core/apikey/dto"
+	"github.com/budimanlai/go-core/apikey/platform/usecase"
+	auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
+	auth_repository "github.com/budimanlai/go-core/auth/domain/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+// fakeAPIKeyRepo keeps the keys in memory, FindOne matches the value of the first condition against the prefix
+type fakeAPIKeyRepo struct {
+	repository.APIKeyRepository
+	keys map[uint]*entity.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Create(ctx context.Context, apiKey *entity.APIKey) error {
+	apiKey.ID = uint(len(r.keys) + 1)
+	stored := *apiKey
+	r.keys[apiKey.ID] = &stored
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) FindByID(ctx context.Context, id any, scopes ...func(*gorm.DB) *gorm.DB) (*entity.APIKey, error) {
+	apiKey, ok := r.keys[id.(uint)]
+	if !ok {
+		return nil, nil
+	}
+	out := *apiKey
+	return &out, nil
+}
+
+func (r *fakeAPIKeyRepo) FindOne(ctx context.Context, scopes ...func(*gorm.DB) *gorm.DB) (*entity.APIKey, error) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		return nil, err
+	}
+	stmt := db.Table("api_keys").Scopes(scopes...).Find(&[]map[string]interface{}{}).Statement
+	for _, apiKey := range r.keys {
+		if len(stmt.Vars) > 0 && apiKey.Prefix == stmt.Vars[0] {
+			out := *apiKey
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAPIKeyRepo) Update(ctx context.Context, apiKey *entity.APIKey) error {
+	stored := *apiKey
+	r.keys[apiKey.ID] = &stored
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) UpdateFields(ctx context.Context, id any, fields map[string]interface{}) error {
+	if at, ok := fields["revoked_at"].(time.Time); ok {
+		r.keys[id.(uint)].RevokedAt = &at
+	}
+	return nil
+}
+
+type fakeOwnerRepo struct {
+	auth_repository.UserRepository
+	users map[uint]*auth_entity.User
+}
+
+func (r *fakeOwnerRepo) FindByID(ctx context.Context, id any, scopes ...func(*gorm.DB) *gorm.DB) (*auth_entity.User, error) {
+	user, ok := r.users[id.(uint)]
+	if !ok {
+		return nil, nil
+	}
+	out := *user
+	return &out, nil
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (dom_usecase.APIKeyUsecase, *fakeAPIKeyRepo, *fakeOwnerRepo) {
+		db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+		require.NoError(t, err)
+		keys := &fakeAPIKeyRepo{keys: map[uint]*entity.APIKey{}}
+		owners := &fakeOwnerRepo{users: map[uint]*auth_entity.User{7: {ID: 7, Status: "active"}}}
+		return usecase.NewAPIKeyUsecase(db, keys, owners, dom_usecase.APIKeyConfig{}), keys, owners
+	}
+
+	t.Run("Usable key", func(t *testing.T) {
+		uc, _, _ := setup(t)
+		issued, err := uc.Issue(ctx, 1, &dto.CreateAPIKeyRequest{Name: "ci", OwnerUserID: 7, Scopes: []string{"region.read"}})
+		require.NoError(t, err)
+
+		apiKey, err := uc.Authenticate(ctx, issued.Key, "10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, issued.APIKey.ID, apiKey.ID)
+		assert.Equal(t, "10.0.0.1", apiKey.LastUsedIP)
+
+		_, err = uc.Authenticate(ctx, issued.Key+"x", "10.0.0.1")
+		assert.Error(t, err)
+	})
+
+	t.Run("Revoked key", func(t *testing.T) {
+		uc, _, _ := setup(t)
+		issued, err := uc.Issue(ctx, 1, &dto.CreateAPIKeyRequest{Name: "ci"})
+		require.NoError(t, err)
+
+		require.NoError(t, uc.Revoke(ctx, issued.APIKey.ID))
+		_, err = uc.Authenticate(ctx, issued.Key, "10.0.0.1")
+		assert.Error(t, err)
+	})
+
+	t.Run("Expired key", func(t *testing.T) {
+		uc, keys, _ := setup(t)
+		expiresAt := time.Now().Add(time.Hour)
+		issued, err := uc.Issue(ctx, 1, &dto.CreateAPIKeyRequest{Name: "ci", ExpiresAt: &expiresAt})
+		require.NoError(t, err)
+
+		expired := time.Now().Add(-time.Second)
+		keys.keys[issued.APIKey.ID].ExpiresAt = &expired
+		_, err = uc.Authenticate(ctx, issued.Key, "10.0.0.1")
+		assert.Error(t, err)
+	})
+
+	t.Run("Owner not active", func(t *testing.T) {
+		uc, _, owners := setup(t)
+		issued, err := uc.Issue(ctx, 1, &dto.CreateAPIKeyRequest{Name: "ci", OwnerUserID: 7})
+		require.NoError(t, err)
+
+		owners.users[7].Status = "suspended"
+		_, err = uc.Authenticate(ctx, issued.Key, "10.0.0.1")
+		assert.Error(t, err)
+
+		delete(owners.users, 7)
+		_, err = uc.Authenticate(ctx, issued.Key, "10.0.0.1")
+		assert.Error(t, err)
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		uc, _, _ := setup(t)
+		issued, err := uc.Issue(ctx, 1, &dto.CreateAPIKeyRequest{Name: "ci"})
+		require.NoError(t, err)
+
+		rotated, err := uc.Rotate(ctx, issued.APIKey.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, issued.Key, rotated.Key)
+
+		_, err = uc.Authenticate(ctx, issued.Key, "10.0.0.1")
+		assert.Error(t, err, "the previous key stops working at once")
+		_, err = uc.Authenticate(ctx, rotated.Key, "10.0.0.1")
+		assert.NoError(t, err)
+
+		require.NoError(t, uc.Revoke(ctx, issued.APIKey.ID))
+		_, err = uc.Rotate(ctx, issued.APIKey.ID)
+		assert.Error(t, err, "a revoked key cannot be rotated")
+	})
+}
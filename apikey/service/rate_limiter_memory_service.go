@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryRateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimiterMemoryImpl counts in process, suitable for single instance deployments where Redis is not available.
+type RateLimiterMemoryImpl struct {
+	mu        sync.Mutex
+	windows   map[string]*memoryRateWindow
+	lastSweep time.Time
+}
+
+func NewRateLimiterMemoryImpl() RateLimiter {
+	return &RateLimiterMemoryImpl{
+		windows: make(map[string]*memoryRateWindow),
+	}
+}
+
+func (l *RateLimiterMemoryImpl) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now, window)
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &memoryRateWindow{resetAt: now.Add(window)}
+		l.windows[key] = w
+	}
+
+	w.count++
+	return w.count <= limit, nil
+}
+
+// sweep drops the ended windows at most once per window, so idle keys do not pile up
+func (l *RateLimiterMemoryImpl) sweep(now time.Time, window time.Duration) {
+	if now.Sub(l.lastSweep) < window {
+		return
+	}
+	l.lastSweep = now
+
+	for key, w := range l.windows {
+		if !now.Before(w.resetAt) {
+			delete(l.windows, key)
+		}
+	}
+}
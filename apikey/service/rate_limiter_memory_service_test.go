@@ -0,0 +1,36 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/apikey/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterMemoryImpl_Allow(t *testing.T) {
+	ctx := context.Background()
+	limiter := service.NewRateLimiterMemoryImpl()
+
+	for i := 0; i < 3; i++ {
+		allowed, err := limiter.Allow(ctx, "key-1", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.True(t, allowed, "request %d", i+1)
+	}
+
+	allowed, _ := limiter.Allow(ctx, "key-1", 3, time.Hour)
+	assert.False(t, allowed, "over the limit")
+
+	allowed, _ = limiter.Allow(ctx, "key-2", 3, time.Hour)
+	assert.True(t, allowed, "keys are counted apart")
+
+	// a new window starts once the previous one ends
+	allowed, _ = limiter.Allow(ctx, "key-3", 1, 20*time.Millisecond)
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow(ctx, "key-3", 1, 20*time.Millisecond)
+	assert.False(t, allowed)
+	time.Sleep(30 * time.Millisecond)
+	allowed, _ = limiter.Allow(ctx, "key-3", 1, 20*time.Millisecond)
+	assert.True(t, allowed)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimiterRedisImpl shares the counters between instances
+type RateLimiterRedisImpl struct {
+	rdb *redis.Client
+}
+
+func NewRateLimiterRedisImpl(rdb *redis.Client) RateLimiter {
+	return &RateLimiterRedisImpl{
+		rdb: rdb,
+	}
+}
+
+func (l *RateLimiterRedisImpl) windowKey(key string, window time.Duration) string {
+	return fmt.Sprintf("apikey:ratelimit:%s:%d", key, time.Now().UnixNano()/int64(window))
+}
+
+func (l *RateLimiterRedisImpl) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	windowKey := l.windowKey(key, window)
+
+	pipe := l.rdb.TxPipeline()
+	count := pipe.Incr(ctx, windowKey)
+	pipe.Expire(ctx, windowKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	return count.Val() <= int64(limit), nil
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter counts the requests of a key in fixed windows.
+type RateLimiter interface {
+	// Allow counts a request of the key and reports if it is within limit requests per window
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
@@ -1,9 +1,14 @@
 package auth
 
 import (
+	"context"
+
 	"github.com/budimanlai/go-core/base"
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/budimanlai/go-core/security"
+
+	dom_auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
 	dom_auth_repository "github.com/budimanlai/go-core/auth/domain/repository"
 	dom_auth_usecase "github.com/budimanlai/go-core/auth/domain/usecase"
 	dom_auth_handler "github.com/budimanlai/go-core/auth/handler/http"
@@ -20,14 +25,19 @@ type AuthManagerDefaultImpl struct {
 	factory *base.Factory
 
 	// repository
-	UserRepo        dom_auth_repository.UserRepository
-	UserSessionRepo dom_auth_repository.UserSessionRepository
-	OtpRepo         dom_auth_repository.OtpRepository
+	UserRepo            dom_auth_repository.UserRepository
+	UserSessionRepo     dom_auth_repository.UserSessionRepository
+	OtpRepo             dom_auth_repository.OtpRepository
+	UserIdentityRepo    dom_auth_repository.UserIdentityRepository
+	PasswordHistoryRepo dom_auth_repository.PasswordHistoryRepository
 
 	// usecase
-	UserUsecase        dom_auth_usecase.UserUsecase
-	UserSessionUsecase dom_auth_usecase.UserSessionUsecase
-	OtpUsecase         dom_auth_usecase.OtpUsecase
+	UserUsecase           dom_auth_usecase.UserUsecase
+	UserSessionUsecase    dom_auth_usecase.UserSessionUsecase
+	OtpUsecase            dom_auth_usecase.OtpUsecase
+	ExternalAuthUsecase   dom_auth_usecase.ExternalAuthUsecase
+	PasswordlessUsecase   dom_auth_usecase.PasswordlessUsecase
+	PasswordPolicyUsecase dom_auth_usecase.PasswordPolicyUsecase
 
 	// handler
 	AuthHandler *dom_auth_handler.AuthHandler
@@ -37,18 +47,57 @@ type AuthManagerDefaultImpl struct {
 	JwtConfig        pkg_auth.JWTConfig
 	OtpSenderService auth_service.OtpSenderService
 	OtpConfig        impl_auth_usecase.OtpConfig
+	SessionStore     auth_service.SessionStore
+	TokenService     auth_service.TokenService
+	ClaimsProvider   auth_service.ClaimsProvider
+
+	// KeySet signs tokens with asymmetric, rotating keys published at /.well-known/jwks.json, optional
+	KeySet auth_service.KeySet
+
+	// AuthContextResolvers enrich the AuthContext of each authenticated request (roles, scopes)
+	AuthContextResolvers []auth_service.AuthContextResolver
+
+	// SessionPolicy limits the number of active sessions per user and per app
+	SessionPolicy dom_auth_entity.SessionPolicy
+
+	// PasswordPolicy is applied to new passwords in register, reset and change password
+	PasswordPolicy          dom_auth_entity.PasswordPolicy
+	BreachedPasswordChecker auth_service.BreachedPasswordChecker
+
+	// PasswordHasher hashes new passwords, legacy hashes are upgraded on login when it is a MultiHasher
+	PasswordHasher security.PasswordHasher
 
 	// middleware
 	// PublicMiddleware is for routes that do not require user session
 	PublicMiddleware fiber.Handler
 
-	// PrivateMiddleware is for routes that require valid user session
+	// PrivateMiddleware is for routes that require valid user session. It rejects sessions of another app
+	// than the request's, so run AppMiddleware first on routes used by other apps than the default one.
 	PrivateMiddleware fiber.Handler
+
+	// AppMiddleware identifies the calling app by its basic-auth key or the AppHeader
+	AppMiddleware fiber.Handler
+
+	// apps
+	AppRegistry auth_service.AppRegistry
+	AppHeader   string
+
+	// external identity providers
+	OAuthProviders     []auth_service.OIDCProvider
+	OAuthStateStore    auth_service.OAuthStateStore
+	ExternalAuthConfig impl_auth_usecase.ExternalAuthConfig
+
+	// passwordless login, routes are registered only when enabled
+	PasswordlessEnabled bool
+	PasswordlessConfig  impl_auth_usecase.PasswordlessConfig
 }
 
 func NewAuthManagerDefaultImpl(factory *base.Factory) *AuthManagerDefaultImpl {
 	return &AuthManagerDefaultImpl{
-		factory: factory,
+		factory:        factory,
+		SessionPolicy:  dom_auth_entity.DefaultSessionPolicy(),
+		PasswordPolicy: dom_auth_entity.DefaultPasswordPolicy(),
+		PasswordHasher: security.NewBcryptHasher(),
 	}
 }
 
@@ -67,6 +116,82 @@ func (m *AuthManagerDefaultImpl) SetOtpSenderService(otpSenderService auth_servi
 	m.OtpConfig = config
 }
 
+// SetTokenService sets the service signing tokens, defaults to one signing with JwtConfig
+func (m *AuthManagerDefaultImpl) SetTokenService(tokenService auth_service.TokenService) {
+	m.TokenService = tokenService
+}
+
+// SetKeySet sets the asymmetric keys (RS256, ES256, EdDSA) signing the tokens instead of the JwtConfig secret.
+// The tokens are then verified by the public keys served at /.well-known/jwks.json.
+func (m *AuthManagerDefaultImpl) SetKeySet(keySet auth_service.KeySet) {
+	m.KeySet = keySet
+}
+
+// SetClaimsProvider sets the provider of extra claims put in issued tokens
+func (m *AuthManagerDefaultImpl) SetClaimsProvider(provider auth_service.ClaimsProvider) {
+	m.ClaimsProvider = provider
+}
+
+// AddAuthContextResolver adds a resolver enriching the AuthContext of each authenticated request
+func (m *AuthManagerDefaultImpl) AddAuthContextResolver(resolver auth_service.AuthContextResolver) {
+	m.AuthContextResolvers = append(m.AuthContextResolvers, resolver)
+}
+
+// SetSessionStore sets the store used to cache active sessions (Redis or in-process),
+// so the private middleware does not hit the database on every request
+func (m *AuthManagerDefaultImpl) SetSessionStore(store auth_service.SessionStore) {
+	m.SessionStore = store
+}
+
+// SetSessionPolicy sets the max active sessions per user and per app,
+// and whether a new login evicts the oldest session or is rejected
+func (m *AuthManagerDefaultImpl) SetSessionPolicy(policy dom_auth_entity.SessionPolicy) {
+	m.SessionPolicy = policy
+}
+
+// SetPasswordPolicy sets the rules for new passwords: length, character classes, history and identifiers
+func (m *AuthManagerDefaultImpl) SetPasswordPolicy(policy dom_auth_entity.PasswordPolicy) {
+	m.PasswordPolicy = policy
+}
+
+// SetBreachedPasswordChecker sets the breached password list used when the policy has CheckBreached,
+// e.g. auth_service.NewBreachedPasswordFileImpl with a local Have I Been Pwned copy
+func (m *AuthManagerDefaultImpl) SetBreachedPasswordChecker(checker auth_service.BreachedPasswordChecker) {
+	m.BreachedPasswordChecker = checker
+}
+
+// SetPasswordHasher sets the password hasher, e.g. security.NewMultiHasher with Argon2id
+// as primary and bcrypt as legacy to migrate existing hashes on login
+func (m *AuthManagerDefaultImpl) SetPasswordHasher(hasher security.PasswordHasher) {
+	m.PasswordHasher = hasher
+}
+
+// SetAppRegistry sets the registry of apps sharing this user base.
+// header is the request header carrying the app ID, empty uses X-App-ID.
+func (m *AuthManagerDefaultImpl) SetAppRegistry(registry auth_service.AppRegistry, header string) {
+	m.AppRegistry = registry
+	m.AppHeader = header
+}
+
+// AddOAuthProvider adds an OpenID Connect provider for external login, e.g. auth_service.GoogleProviderConfig
+func (m *AuthManagerDefaultImpl) AddOAuthProvider(config auth_service.OAuthProviderConfig) {
+	m.OAuthProviders = append(m.OAuthProviders, auth_service.NewOIDCProviderImpl(config, nil))
+}
+
+// SetOAuthStateStore sets the store of pending authorizations, defaults to in-process memory.
+// Use the Redis store when running more than one instance.
+func (m *AuthManagerDefaultImpl) SetOAuthStateStore(store auth_service.OAuthStateStore, config impl_auth_usecase.ExternalAuthConfig) {
+	m.OAuthStateStore = store
+	m.ExternalAuthConfig = config
+}
+
+// EnablePasswordless enables login by OTP or magic link. An empty MagicLinkSecret falls back to the JwtConfig secret,
+// magic links are sent with the OtpSenderService when it implements MagicLinkSenderService.
+func (m *AuthManagerDefaultImpl) EnablePasswordless(config impl_auth_usecase.PasswordlessConfig) {
+	m.PasswordlessEnabled = true
+	m.PasswordlessConfig = config
+}
+
 func (m *AuthManagerDefaultImpl) SetPublicMiddleware(middleware fiber.Handler) {
 	m.PublicMiddleware = middleware
 }
@@ -83,6 +208,19 @@ func (m *AuthManagerDefaultImpl) InitManager() {
 }
 
 func (m *AuthManagerDefaultImpl) initMiddleware() {
+	if m.AppMiddleware == nil {
+		if m.AppRegistry != nil {
+			m.AppMiddleware = dom_auth_handler.NewAppMiddleware(m.AppRegistry, m.AppHeader)
+		} else {
+			m.AppMiddleware = func(c *fiber.Ctx) error { return c.Next() }
+		}
+	}
+
+	// the go-pkg middleware only knows the JwtConfig secret
+	if m.PrivateMiddleware == nil && m.KeySet != nil {
+		m.PrivateMiddleware = dom_auth_handler.NewJWTMiddleware(m.TokenService, m.UserSessionUsecase.SuccessHandler)
+	}
+
 	if m.PrivateMiddleware == nil {
 		m.JwtService.SetSuccessHandler(m.UserSessionUsecase.SuccessHandler)
 		m.PrivateMiddleware = m.JwtService.Middleware()
@@ -93,39 +231,123 @@ func (m *AuthManagerDefaultImpl) initService() {
 	if m.JwtService == nil {
 		m.JwtService = pkg_auth.NewJWTAuth(m.JwtConfig)
 	}
+
+	if m.TokenService == nil && m.KeySet != nil {
+		m.TokenService = auth_service.NewKeySetTokenServiceImpl(m.KeySet, m.JwtConfig.Issuer, m.JwtConfig.ExpirationTime)
+		m.KeySet.StartRotation(context.Background())
+	}
+
+	// a custom JwtService without JwtConfig keeps signing its own tokens
+	if m.TokenService == nil && m.JwtConfig.SecretKey != "" {
+		m.TokenService = auth_service.NewTokenServiceImpl(m.JwtConfig)
+	}
 }
 
 func (m *AuthManagerDefaultImpl) initContainer() {
 	m.UserRepo = impl_auth_repository.NewUserRepositoryImpl(m.factory)
 	m.UserSessionRepo = impl_auth_repository.NewUserSessionRepositoryImpl(m.factory)
 	m.OtpRepo = impl_auth_repository.NewOtpRepositoryImpl(m.factory)
+	m.UserIdentityRepo = impl_auth_repository.NewUserIdentityRepositoryImpl(m.factory)
+	m.PasswordHistoryRepo = impl_auth_repository.NewPasswordHistoryRepositoryImpl(m.factory)
 }
 
 func (m *AuthManagerDefaultImpl) initUsecase() {
 	m.UserSessionUsecase = impl_auth_usecase.NewUserSessionUsecaseImpl(m.factory.DB, m.UserSessionRepo, m.UserRepo, m.JwtService)
-	m.UserSessionUsecase.SetMultipleLoginAllowed(false) // allow multiple login
+	m.UserSessionUsecase.SetSessionPolicy(m.SessionPolicy)
+	m.UserSessionUsecase.SetPasswordHasher(m.PasswordHasher)
+	if m.AppRegistry != nil {
+		m.UserSessionUsecase.SetAppRegistry(m.AppRegistry)
+	}
+	if m.TokenService != nil {
+		m.UserSessionUsecase.SetTokenService(m.TokenService)
+	}
+	if m.ClaimsProvider != nil {
+		m.UserSessionUsecase.SetClaimsProvider(m.ClaimsProvider)
+	}
+	for _, resolver := range m.AuthContextResolvers {
+		m.UserSessionUsecase.AddAuthContextResolver(resolver)
+	}
+	if m.SessionStore != nil {
+		m.UserSessionUsecase.SetSessionStore(m.SessionStore)
+	}
 
 	m.OtpUsecase = usecase.NewOtpUsecaseImpl(m.factory.DB, m.OtpRepo, m.OtpConfig)
 	m.OtpUsecase.SetSender(m.OtpSenderService)
 
+	m.PasswordPolicyUsecase = impl_auth_usecase.NewPasswordPolicyUsecaseImpl(m.factory.DB, m.PasswordHistoryRepo, m.PasswordPolicy)
+	m.PasswordPolicyUsecase.SetPasswordHasher(m.PasswordHasher)
+	if m.BreachedPasswordChecker != nil {
+		m.PasswordPolicyUsecase.SetBreachedChecker(m.BreachedPasswordChecker)
+	}
+
 	m.UserUsecase = impl_auth_usecase.NewUserUsecaseImpl(m.factory.DB, m.UserRepo, m.OtpUsecase, m.UserSessionUsecase)
+	m.UserUsecase.SetPasswordPolicy(m.PasswordPolicyUsecase)
+	m.UserUsecase.SetPasswordHasher(m.PasswordHasher)
+	if notifier, ok := m.OtpSenderService.(auth_service.IdentifierChangeNotifier); ok {
+		m.UserUsecase.SetNotifier(notifier)
+	}
+
+	m.ExternalAuthUsecase = impl_auth_usecase.NewExternalAuthUsecaseImpl(m.factory.DB, m.UserIdentityRepo, m.UserRepo,
+		m.UserSessionUsecase, m.OAuthStateStore, m.ExternalAuthConfig)
+	for _, provider := range m.OAuthProviders {
+		m.ExternalAuthUsecase.RegisterProvider(provider)
+	}
+
+	if m.PasswordlessConfig.MagicLinkSecret == "" {
+		m.PasswordlessConfig.MagicLinkSecret = m.JwtConfig.SecretKey
+	}
+	m.PasswordlessUsecase = impl_auth_usecase.NewPasswordlessUsecaseImpl(m.factory.DB, m.UserRepo, m.OtpUsecase,
+		m.UserSessionUsecase, m.PasswordlessConfig)
+	if sender, ok := m.OtpSenderService.(auth_service.MagicLinkSenderService); ok {
+		m.PasswordlessUsecase.SetMagicLinkSender(sender)
+	}
 }
 
 func (m *AuthManagerDefaultImpl) SetRoute(app fiber.Router) {
-	m.AuthHandler = dom_auth_handler.NewAuthHandler(m.UserUsecase, m.UserSessionUsecase, m.OtpUsecase)
+	m.AuthHandler = dom_auth_handler.NewAuthHandler(m.UserUsecase, m.UserSessionUsecase, m.OtpUsecase, m.ExternalAuthUsecase, m.PasswordlessUsecase)
+
+	// Public keys verifying the tokens
+	if m.KeySet != nil {
+		app.Get("/.well-known/jwks.json", dom_auth_handler.JWKS(m.KeySet))
+	}
 
 	// Basic Auth Middleware
 	authEndpoint := app.Group("/auth")
-	authEndpoint.Post("/login", m.PublicMiddleware, m.AuthHandler.Login)
-	authEndpoint.Post("/otp/request", m.PublicMiddleware, m.AuthHandler.RequestOtp)
-	authEndpoint.Post("/otp/status", m.PublicMiddleware, m.AuthHandler.StatusOTP)
-	authEndpoint.Post("/otp/verify", m.PublicMiddleware, m.AuthHandler.VerifyOTP)
-	authEndpoint.Post("/password/reset", m.PublicMiddleware, m.AuthHandler.ResetPassword)
-	authEndpoint.Post("/register", m.PublicMiddleware, m.AuthHandler.Register)
+	authEndpoint.Post("/login", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.Login)
+	authEndpoint.Post("/otp/request", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.RequestOtp)
+	authEndpoint.Post("/otp/status", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.StatusOTP)
+	authEndpoint.Post("/otp/verify", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.VerifyOTP)
+	authEndpoint.Post("/password/reset", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.ResetPassword)
+	authEndpoint.Post("/register", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.Register)
+
+	// Passwordless login
+	if m.PasswordlessEnabled {
+		authEndpoint.Post("/passwordless/request", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.PasswordlessRequest)
+		authEndpoint.Post("/passwordless/verify", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.PasswordlessVerify)
+		authEndpoint.Post("/passwordless/magic", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.PasswordlessMagicLink)
+	}
+
+	// External identity providers, the callback is reached by a browser redirect so it has no basic auth
+	if len(m.OAuthProviders) > 0 {
+		authEndpoint.Get("/oauth/:provider", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.OAuthAuthURL)
+		authEndpoint.Get("/oauth/:provider/nonce", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.OAuthNonce)
+		authEndpoint.Post("/oauth/:provider/token", m.PublicMiddleware, m.AppMiddleware, m.AuthHandler.OAuthIDToken)
+		authEndpoint.Get("/oauth/:provider/callback", m.AuthHandler.OAuthCallback)
+		authEndpoint.Post("/oauth/:provider/callback", m.AuthHandler.OAuthCallback)
+	}
 
 	// JWT Auth Middleware
-	jwtRestAPI := app.Group("/auth", m.PrivateMiddleware)
+	jwtRestAPI := app.Group("/auth", m.AppMiddleware, m.PrivateMiddleware)
 	jwtRestAPI.Post("/logout", m.AuthHandler.Logout)
 	jwtRestAPI.Post("/token/verify", m.AuthHandler.VerifyToken)
 	jwtRestAPI.Post("/token/refresh", m.AuthHandler.RefreshToken)
+	jwtRestAPI.Get("/identities", m.AuthHandler.GetIdentities)
+
+	// credentials stay out of reach of the support staff impersonating the user
+	deny := dom_auth_handler.DenyImpersonation
+	jwtRestAPI.Post("/password/change", deny, m.AuthHandler.ChangePassword)
+	jwtRestAPI.Post("/identifier/change", deny, m.AuthHandler.ChangeIdentifierRequest)
+	jwtRestAPI.Post("/identifier/change/confirm", deny, m.AuthHandler.ChangeIdentifierConfirm)
+	jwtRestAPI.Post("/identities/:provider", deny, m.AuthHandler.LinkIdentity)
+	jwtRestAPI.Delete("/identities/:provider", deny, m.AuthHandler.UnlinkIdentity)
 }
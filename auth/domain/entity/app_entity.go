@@ -0,0 +1,46 @@
+package entity
+
+import "crypto/subtle"
+
+// DefaultAppID is used when the request does not identify an app
+const DefaultAppID = 1
+
+type App struct {
+	ID   int
+	Name string
+
+	// AuthKeys are the basic-auth keys identifying this app on public routes
+	AuthKeys []string
+
+	// LoginChannels are the allowed login channels ("email", "phone"), empty allows all
+	LoginChannels []string
+
+	// Audience is the expected JWT audience for tokens of this app, empty skips the check
+	Audience string
+
+	// SessionPolicy overrides the default session limits for this app, optional
+	SessionPolicy *SessionPolicy
+}
+
+// AllowsChannel checks if users may log in to this app via the given channel
+func (a *App) AllowsChannel(channel string) bool {
+	if len(a.LoginChannels) == 0 {
+		return true
+	}
+	for _, c := range a.LoginChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAuthKey checks if the given basic-auth key belongs to this app.
+// Every key is compared in constant time, so the timing does not reveal how much of a key matched.
+func (a *App) HasAuthKey(key string) bool {
+	found := 0
+	for _, k := range a.AuthKeys {
+		found |= subtle.ConstantTimeCompare([]byte(k), []byte(key))
+	}
+	return found == 1
+}
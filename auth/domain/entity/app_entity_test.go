@@ -0,0 +1,19 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HasAuthKey(t *testing.T) {
+	app := entity.App{AuthKeys: []string{"current-key", "previous-key"}}
+
+	assert.True(t, app.HasAuthKey("current-key"))
+	assert.True(t, app.HasAuthKey("previous-key"))
+	assert.False(t, app.HasAuthKey("current"))
+	assert.False(t, app.HasAuthKey("current-key-2"))
+	assert.False(t, app.HasAuthKey(""))
+	assert.False(t, (&entity.App{}).HasAuthKey(""))
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"context"
+	"slices"
+)
+
+// AuthContextKey is the key of the AuthContext in context.Context and fiber Locals.
+// Fiber Locals are fasthttp user values, so the AuthContext stored by the private
+// middleware is also readable from c.Context() passed down to usecases.
+type AuthContextKey struct{}
+
+// AuthContext is the authenticated caller of the current request
+type AuthContext struct {
+	UserID    uint
+	SessionID int
+	AppID     int
+
+	// Token is the opaque session token carried in the "ses" claim
+	Token string
+
+	// ImpersonatorID is the staff user acting as UserID, 0 when the user itself is authenticated
+	ImpersonatorID uint
+
+	// APIKeyID is the API key authenticating a machine client, UserID is then the key owner if any
+	APIKeyID uint
+
+	Roles  []string
+	Scopes []string
+
+	// Claims are the raw JWT claims, including the optional extra claims
+	Claims map[string]interface{}
+}
+
+// IsImpersonated checks if a staff user is acting as the user
+func (a *AuthContext) IsImpersonated() bool {
+	return a.ImpersonatorID != 0
+}
+
+// HasRole checks if the caller has the given role
+func (a *AuthContext) HasRole(role string) bool {
+	return slices.Contains(a.Roles, role)
+}
+
+// HasScope checks if the caller has the given scope
+func (a *AuthContext) HasScope(scope string) bool {
+	return slices.Contains(a.Scopes, scope)
+}
+
+// InjectAuthContext stores the AuthContext into ctx
+func InjectAuthContext(ctx context.Context, auth *AuthContext) context.Context {
+	return context.WithValue(ctx, AuthContextKey{}, auth)
+}
+
+// ExtractAuthContext returns the AuthContext stored in ctx, or nil for anonymous requests
+func ExtractAuthContext(ctx context.Context) *AuthContext {
+	if auth, ok := ctx.Value(AuthContextKey{}).(*AuthContext); ok {
+		return auth
+	}
+	return nil
+}
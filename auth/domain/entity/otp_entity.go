@@ -8,5 +8,6 @@ type Otp struct {
 	TrxID     string
 	PinCode   string
 	Status    string
+	Attempts  int
 	CreatedAt time.Time
 }
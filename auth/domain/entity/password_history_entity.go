@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// PasswordHistory keeps a previous password hash of a user, to prevent reuse
+type PasswordHistory struct {
+	ID           int
+	UserID       uint
+	PasswordHash string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minIdentifierLength ignores identifiers too short to be meaningful inside a password
+const minIdentifierLength = 4
+
+type PasswordPolicy struct {
+	// MinLength bounds the password length in characters, MaxLength in bytes as bcrypt only uses
+	// the first 72 bytes, 0 means no bound
+	MinLength int
+	MaxLength int
+
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+
+	// HistorySize rejects the current password and the last N ones, 0 disables the history
+	HistorySize int
+
+	// BlockIdentifiers rejects passwords containing the username, email or handphone
+	BlockIdentifiers bool
+
+	// CheckBreached rejects passwords found in the breached password list
+	CheckBreached bool
+}
+
+// DefaultPasswordPolicy requires at least 8 characters and at most 72 bytes (the bcrypt limit) without the user's identifiers
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        72,
+		BlockIdentifiers: true,
+	}
+}
+
+// Check validates the password against the length, character class and identifier rules.
+// History and breached checks need storage and are done by the password policy usecase.
+func (p PasswordPolicy) Check(password string, identifiers ...string) error {
+	if p.MinLength > 0 && utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters", p.MinLength)
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return fmt.Errorf("password must be at most %d bytes", p.MaxLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		return errors.New("password must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		return errors.New("password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		return errors.New("password must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		return errors.New("password must contain a symbol")
+	}
+
+	if p.BlockIdentifiers {
+		lowered := strings.ToLower(password)
+		for _, identifier := range identifiers {
+			// the local part of an email is what users put in passwords
+			if at := strings.Index(identifier, "@"); at > 0 {
+				identifier = identifier[:at]
+			}
+			identifier = strings.ToLower(strings.TrimPrefix(identifier, "+"))
+			if len(identifier) >= minIdentifierLength && strings.Contains(lowered, identifier) {
+				return errors.New("password must not contain your username, email or handphone")
+			}
+		}
+	}
+
+	return nil
+}
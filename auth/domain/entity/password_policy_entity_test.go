@@ -0,0 +1,35 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	policy := entity.PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        20,
+		RequireUpper:     true,
+		RequireDigit:     true,
+		BlockIdentifiers: true,
+	}
+
+	assert.NoError(t, policy.Check("Secret123", "john@example.com", "628123456789"))
+
+	assert.Error(t, policy.Check("Sec1"), "too short")
+	assert.Error(t, policy.Check("Secret1234567890123456"), "too long")
+	assert.Error(t, policy.Check("secret123"), "no uppercase")
+	assert.Error(t, policy.Check("SecretPass"), "no digit")
+	assert.Error(t, policy.Check("John12345", "john@example.com"), "email local part")
+	assert.Error(t, policy.Check("Pw628123456789", "+628123456789"), "handphone")
+
+	// short identifiers are ignored
+	assert.NoError(t, policy.Check("Bob12345X", "bob"))
+
+	// the maximum is in bytes, what bcrypt hashes
+	assert.NoError(t, entity.DefaultPasswordPolicy().Check(strings.Repeat("a", 72)))
+	assert.Error(t, entity.DefaultPasswordPolicy().Check(strings.Repeat("é", 37)), "74 bytes")
+}
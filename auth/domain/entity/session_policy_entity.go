@@ -0,0 +1,38 @@
+package entity
+
+const (
+	// SessionLimitEvictOldest revokes the oldest active sessions to make room for the new one
+	SessionLimitEvictOldest = "evict_oldest"
+
+	// SessionLimitRejectNew rejects the new login while the limit is reached
+	SessionLimitRejectNew = "reject_new"
+)
+
+type SessionPolicy struct {
+	// MaxSessions is the maximum active sessions per user across all apps, 0 means unlimited
+	MaxSessions int
+
+	// AppMaxSessions is the maximum active sessions per user for a given app ID, 0 means unlimited
+	AppMaxSessions map[int]int
+
+	// OnLimit is the behavior when a limit is reached: SessionLimitEvictOldest or SessionLimitRejectNew
+	OnLimit string
+}
+
+// DefaultSessionPolicy allows a single active session per user, a new login evicts the old one
+func DefaultSessionPolicy() SessionPolicy {
+	return SessionPolicy{
+		MaxSessions: 1,
+		OnLimit:     SessionLimitEvictOldest,
+	}
+}
+
+// AppLimit returns the max active sessions for the given app ID, 0 means unlimited
+func (p SessionPolicy) AppLimit(appID int) int {
+	return p.AppMaxSessions[appID]
+}
+
+// IsRejectNew checks if a new login must be rejected when the limit is reached
+func (p SessionPolicy) IsRejectNew() bool {
+	return p.OnLimit == SessionLimitRejectNew
+}
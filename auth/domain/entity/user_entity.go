@@ -15,8 +15,16 @@ type User struct {
 	UpdatedAt    time.Time
 	CreatedBy    uint
 	UpdatedBy    uint
+
+	// VerificationToken is set until the email is verified
+	VerificationToken *string
 }
 
 func (u *User) IsActive() bool {
 	return u.Status == "active"
 }
+
+// IsVerified checks if the email has been verified
+func (u *User) IsVerified() bool {
+	return u.VerificationToken == nil
+}
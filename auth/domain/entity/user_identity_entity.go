@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// UserIdentity links a user to an account at an external identity provider
+type UserIdentity struct {
+	ID        int
+	UserID    uint
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
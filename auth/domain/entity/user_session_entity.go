@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type UserSession struct {
 	ID           int
@@ -12,4 +15,47 @@ type UserSession struct {
 	RemoveOn     *time.Time
 	FromIP       string
 	UserAgent    string
+
+	// ImpersonatorID is the staff user acting as UserID, 0 for a session of the user itself
+	ImpersonatorID uint
+
+	// ExpireOn ends the session regardless of the token expiry, nil never expires
+	ExpireOn *time.Time
+}
+
+// IsImpersonated checks if the session was issued to a staff user acting as the user
+func (s *UserSession) IsImpersonated() bool {
+	return s.ImpersonatorID != 0
+}
+
+// IsExpired checks if the session has passed its end time
+func (s *UserSession) IsExpired(now time.Time) bool {
+	return s.ExpireOn != nil && !s.ExpireOn.After(now)
+}
+
+// SessionsToEvict returns the n sessions a new login evicts, the oldest first by creation time then by ID
+func SessionsToEvict(sessions []UserSession, n int) []UserSession {
+	if n <= 0 {
+		return nil
+	}
+
+	sorted := slices.Clone(sessions)
+	slices.SortStableFunc(sorted, func(a, b UserSession) int {
+		if c := a.CreateOn.Compare(b.CreateOn); c != 0 {
+			return c
+		}
+		return a.ID - b.ID
+	})
+	return sorted[:min(n, len(sorted))]
+}
+
+// SessionOption customizes a session created by GenerateSession
+type SessionOption func(*UserSession)
+
+// WithImpersonator marks the session as issued to the impersonator, it ends at expireOn
+func WithImpersonator(impersonatorID uint, expireOn time.Time) SessionOption {
+	return func(s *UserSession) {
+		s.ImpersonatorID = impersonatorID
+		s.ExpireOn = &expireOn
+	}
 }
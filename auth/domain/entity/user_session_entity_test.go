@@ -0,0 +1,48 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSession_WithImpersonator(t *testing.T) {
+	now := time.Now()
+
+	session := entity.UserSession{UserID: 7}
+	assert.False(t, session.IsImpersonated())
+	assert.False(t, session.IsExpired(now))
+
+	entity.WithImpersonator(3, now.Add(time.Minute))(&session)
+	assert.True(t, session.IsImpersonated())
+	assert.Equal(t, uint(3), session.ImpersonatorID)
+	assert.False(t, session.IsExpired(now))
+	assert.True(t, session.IsExpired(now.Add(time.Minute)))
+}
+
+func TestSessionsToEvict(t *testing.T) {
+	now := time.Now()
+	sessions := []entity.UserSession{
+		{ID: 4, CreateOn: now},
+		{ID: 2, CreateOn: now.Add(-time.Hour)},
+		{ID: 3, CreateOn: now.Add(-2 * time.Hour)},
+		{ID: 1, CreateOn: now},
+	}
+
+	ids := func(sessions []entity.UserSession) []int {
+		out := []int{}
+		for _, session := range sessions {
+			out = append(out, session.ID)
+		}
+		return out
+	}
+
+	// the oldest first, the ID breaks ties of the same creation time
+	assert.Equal(t, []int{3, 2, 1}, ids(entity.SessionsToEvict(sessions, 3)))
+	assert.Equal(t, []int{3}, ids(entity.SessionsToEvict(sessions, 1)))
+	assert.Equal(t, []int{3, 2, 1, 4}, ids(entity.SessionsToEvict(sessions, 10)))
+	assert.Empty(t, entity.SessionsToEvict(sessions, 0))
+	assert.Equal(t, 4, sessions[0].ID, "the input is not reordered")
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	model "github.com/budimanlai/go-core/auth/models"
+	"github.com/budimanlai/go-core/base"
+)
+
+type UserIdentityRepository interface {
+	base.BaseRepository[entity.UserIdentity, model.UserIdentity]
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-core/base"
+)
+
+type ExternalAuthUsecase interface {
+	base.BaseUsecase[entity.UserIdentity]
+
+	// RegisterProvider adds an external identity provider
+	RegisterProvider(provider service.OIDCProvider)
+
+	// HasProvider returns whether the named provider is registered
+	HasProvider(name string) bool
+
+	// AuthURL starts the authorization code + PKCE flow and returns the provider's consent URL
+	AuthURL(ctx context.Context, providerName string, appID int) (string, error)
+
+	// Callback completes the flow started by AuthURL and logs the linked user in
+	Callback(ctx context.Context, providerName, state, code, fromIP, userAgent string) (*dto.LoginResponse, error)
+
+	// IssueNonce returns a single use nonce for a native SDK login or link at the provider
+	IssueNonce(ctx context.Context, providerName string, appID int) (string, error)
+
+	// LoginWithIDToken logs in with an ID token obtained by a native SDK on the client, carrying a nonce of IssueNonce
+	LoginWithIDToken(ctx context.Context, providerName, idToken, nonce string, appID int, fromIP, userAgent string) (*dto.LoginResponse, error)
+
+	// Link links the external identity of the ID token, carrying a nonce of IssueNonce, to the given user
+	Link(ctx context.Context, userID uint, providerName, idToken, nonce string) error
+
+	// Unlink removes the user's linked identity at the given provider
+	Unlink(ctx context.Context, userID uint, providerName string) error
+
+	// GetIdentities returns the identities linked to the given user
+	GetIdentities(ctx context.Context, userID uint) ([]entity.UserIdentity, error)
+}
@@ -21,6 +21,9 @@ type OtpUsecase interface {
 	// VerifyOtp verifies the OTP pin code for the given phone number and transaction ID.
 	VerifyOtp(ctx context.Context, phoneNumber, trx_id, pin_code string) error
 
+	// Consume marks the OTP with the given status as used, so it logs in only once.
+	Consume(ctx context.Context, identifier, trx_id, status string) error
+
 	// SetSender sets the OTP sender service.
 	SetSender(sender service.OtpSenderService)
 
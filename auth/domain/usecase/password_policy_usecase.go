@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/security"
+)
+
+type PasswordPolicyUsecase interface {
+	base.BaseUsecase[entity.PasswordHistory]
+
+	// GetPolicy returns the active password policy
+	GetPolicy() entity.PasswordPolicy
+
+	// SetPolicy sets the password policy
+	SetPolicy(policy entity.PasswordPolicy)
+
+	// SetBreachedChecker sets the checker used when the policy has CheckBreached
+	SetBreachedChecker(checker service.BreachedPasswordChecker)
+
+	// SetPasswordHasher sets the hasher comparing the password history
+	SetPasswordHasher(hasher security.PasswordHasher)
+
+	// Validate checks a new password of the user against the policy, the user ID is 0 for a new user
+	Validate(ctx context.Context, user *entity.User, password string) error
+
+	// Remember adds the new password hash to the user's history and drops the ones beyond the history size
+	Remember(ctx context.Context, userID uint, passwordHash string) error
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-core/base"
+)
+
+type PasswordlessUsecase interface {
+	base.BaseUsecase[entity.User]
+
+	// SetMagicLinkSender sets the service sending magic links, magic links are disabled without it
+	SetMagicLinkSender(sender service.MagicLinkSenderService)
+
+	// RequestLogin sends an OTP or a magic link to the user identified by email or phone
+	RequestLogin(ctx context.Context, request dto.PasswordlessRequest, appID int) (*dto.PasswordlessResponse, error)
+
+	// VerifyOtp logs the user in with the OTP sent by RequestLogin
+	VerifyOtp(ctx context.Context, request dto.PasswordlessVerifyRequest, appID int, fromIP, userAgent string) (*dto.LoginResponse, error)
+
+	// ConsumeMagicLink logs the user in with the one-time token of a magic link
+	ConsumeMagicLink(ctx context.Context, token, fromIP, userAgent string) (*dto.LoginResponse, error)
+}
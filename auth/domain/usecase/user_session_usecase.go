@@ -5,7 +5,9 @@ import (
 
 	"github.com/budimanlai/go-core/auth/domain/entity"
 	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-core/auth/service"
 	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/security"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -19,14 +21,54 @@ type UserSessionUsecase interface {
 	// SetMultipleLoginAllowed sets whether multiple logins are allowed for a user
 	SetMultipleLoginAllowed(allowed bool)
 
+	// GetSessionPolicy returns the active session policy
+	GetSessionPolicy() entity.SessionPolicy
+
+	// SetSessionPolicy sets the max active sessions per user and per app, and the behavior when reached
+	SetSessionPolicy(policy entity.SessionPolicy)
+
+	// SetPasswordHasher sets the hasher checking passwords on login, legacy hashes are upgraded
+	SetPasswordHasher(hasher security.PasswordHasher)
+
+	// SetTokenService sets the service signing tokens with extra claims
+	SetTokenService(tokenService service.TokenService)
+
+	// SetClaimsProvider sets the provider of extra claims put in issued tokens
+	SetClaimsProvider(provider service.ClaimsProvider)
+
+	// AddAuthContextResolver adds a resolver enriching the AuthContext of each authenticated request
+	AddAuthContextResolver(resolver service.AuthContextResolver)
+
+	// SetAppRegistry sets the registry of apps sharing this user base
+	SetAppRegistry(registry service.AppRegistry)
+
+	// SetRequireVerified rejects the logins of users who have not verified their email, on every login method
+	SetRequireVerified(required bool)
+
+	// SetSessionStore sets the store used to cache active sessions
+	SetSessionStore(store service.SessionStore)
+
 	// RevokeSessionsByUserID revokes all sessions for a given user ID
-	RevokeSessionsByUserID(ctx context.Context, userID uint)
+	RevokeSessionsByUserID(ctx context.Context, userID uint) error
+
+	// RevokeOtherSessions revokes all sessions for a given user ID except the one with the given token
+	RevokeOtherSessions(ctx context.Context, userID uint, currentToken string) error
+
+	// InvalidateSessionsByUserID drops the cached sessions for a given user ID without revoking them
+	InvalidateSessionsByUserID(ctx context.Context, userID uint)
+
+	// GenerateSession generates a new user session for the given app, impersonation sessions are not counted
+	// by the session policy
+	GenerateSession(ctx context.Context, userID uint, appID int, fromIP, userAgent string, opts ...entity.SessionOption) (*entity.UserSession, error)
+
+	// SignToken signs the JWT token of a session created by GenerateSession
+	SignToken(ctx context.Context, session *entity.UserSession) (string, error)
 
-	// GenerateSession generates a new user session
-	GenerateSession(ctx context.Context, userID uint, fromIP, userAgent string) (*entity.UserSession, error)
+	// RevokeSession revokes the session with the given ID
+	RevokeSession(ctx context.Context, sessionID int) error
 
-	// Login authenticates a user and returns a token if successful
-	Login(ctx context.Context, username, password, fromIP, userAgent string) (*dto.LoginResponse, error)
+	// Login authenticates a user to the given app and returns a token if successful
+	Login(ctx context.Context, appID int, username, password, fromIP, userAgent string) (*dto.LoginResponse, error)
 
 	// Logout logs out a user by revoking their session token
 	Logout(ctx context.Context, tokenString string) error
@@ -40,6 +82,6 @@ type UserSessionUsecase interface {
 	// SuccessHandler handles successful JWT authentication
 	SuccessHandler(c *fiber.Ctx, claims jwt.MapClaims) error
 
-	// GenerateToken creates a new user session and generates a JWT token for the given user ID
-	GenerateToken(ctx context.Context, user_id uint, fromIP, userAgent string) (string, error)
+	// GenerateToken creates a new user session and generates a JWT token for the given user ID and app ID
+	GenerateToken(ctx context.Context, user_id uint, appID int, fromIP, userAgent string) (string, error)
 }
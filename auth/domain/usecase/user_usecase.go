@@ -5,7 +5,9 @@ import (
 
 	"github.com/budimanlai/go-core/auth/domain/entity"
 	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-core/auth/service"
 	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/security"
 )
 
 type UserUsecase interface {
@@ -16,4 +18,25 @@ type UserUsecase interface {
 
 	// Register registers a new user and returns login response
 	Register(ctx context.Context, req dto.RegisterRequest) (*dto.LoginResponse, error)
+
+	// UpdateStatus changes the user's status and invalidates the user's cached sessions
+	UpdateStatus(ctx context.Context, userID uint, status string) error
+
+	// ChangePassword changes the password of a logged in user and revokes the user's other sessions
+	ChangePassword(ctx context.Context, userID uint, currentToken string, req dto.ChangePasswordRequest) error
+
+	// RequestChangeIdentifier sends an OTP to the new email or handphone of a logged in user
+	RequestChangeIdentifier(ctx context.Context, userID uint, req dto.ChangeIdentifierRequest) (*dto.ChangeIdentifierResponse, error)
+
+	// ConfirmChangeIdentifier verifies the OTP, replaces the email or handphone and notifies the old one
+	ConfirmChangeIdentifier(ctx context.Context, userID uint, req dto.ChangeIdentifierConfirmRequest) error
+
+	// SetPasswordPolicy sets the policy applied to new passwords in register, reset and change password
+	SetPasswordPolicy(passwordPolicyUC PasswordPolicyUsecase)
+
+	// SetPasswordHasher sets the hasher used for new passwords and the current password check
+	SetPasswordHasher(hasher security.PasswordHasher)
+
+	// SetNotifier sets the service notifying the old identifier after a change
+	SetNotifier(notifier service.IdentifierChangeNotifier)
 }
@@ -0,0 +1,25 @@
+package dto
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
+}
+
+type ChangeIdentifierRequest struct {
+	Channel    string `json:"channel" validate:"required,oneof=phone email"`
+	Identifier string `json:"identifier" validate:"required"`
+}
+
+type ChangeIdentifierResponse struct {
+	Identifier string `json:"identifier"`
+	TrxID      string `json:"trx_id"`
+	WaUrl      string `json:"wa_url,omitempty"`
+}
+
+type ChangeIdentifierConfirmRequest struct {
+	Channel    string `json:"channel" validate:"required,oneof=phone email"`
+	Identifier string `json:"identifier" validate:"required"`
+	TrxID      string `json:"trx_id" validate:"required"`
+	PinCode    string `json:"pin_code" validate:"required"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type OAuthURLResponse struct {
+	AuthURL string `json:"auth_url"`
+}
+
+type OAuthNonceResponse struct {
+	Nonce string `json:"nonce"`
+}
+
+type OAuthCallbackRequest struct {
+	State string `json:"state" form:"state" query:"state" validate:"required"`
+	Code  string `json:"code" form:"code" query:"code" validate:"required"`
+}
+
+type OAuthIDTokenRequest struct {
+	IDToken string `json:"id_token" validate:"required"`
+	Nonce   string `json:"nonce" validate:"required"`
+}
+
+type UserIdentity struct {
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -4,6 +4,9 @@ type OtpRequest struct {
 	Channel    string `json:"channel" validate:"required,oneof=phone email"`
 	Identifier string `json:"identifier" validate:"required"`
 	TrxID      string `json:"trx_id" validate:"required"`
+
+	// SkipSend generates the OTP without sending it, when the caller delivers it another way
+	SkipSend bool `json:"-"`
 }
 
 type OtpResponse struct {
@@ -0,0 +1,24 @@
+package dto
+
+type PasswordlessRequest struct {
+	Channel    string `json:"channel" validate:"required,oneof=phone email"`
+	Identifier string `json:"identifier" validate:"required"`
+	// Method is "otp" (default) or "magic_link", magic links are sent by email only
+	Method string `json:"method" validate:"omitempty,oneof=otp magic_link"`
+}
+
+type PasswordlessResponse struct {
+	Identifier string `json:"identifier"`
+	TrxID      string `json:"trx_id"`
+	WaUrl      string `json:"wa_url,omitempty"`
+}
+
+type PasswordlessVerifyRequest struct {
+	Identifier string `json:"identifier" validate:"required"`
+	TrxID      string `json:"trx_id" validate:"required"`
+	PinCode    string `json:"pin_code" validate:"required"`
+}
+
+type MagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
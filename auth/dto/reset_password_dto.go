@@ -4,7 +4,7 @@ type ResetPasswordRequest struct {
 	Channel         string `json:"channel" validate:"required,oneof=phone email"`
 	Identifier      string `json:"identifier" validate:"required"`
 	TrxID           string `json:"trx_id" validate:"required"`
-	Password        string `json:"password" validate:"required,min=7,max=18"`
+	Password        string `json:"password" validate:"required"`
 	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
 }
 
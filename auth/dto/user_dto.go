@@ -18,4 +18,5 @@ type RegisterRequest struct {
 	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
 	FromIP          string
 	UserAgent       string
+	AppID           int
 }
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultAppHeader is the header carrying the app ID when the app is not identified by its basic-auth key
+const DefaultAppHeader = "X-App-ID"
+
+// NewAppMiddleware identifies the calling app by its basic-auth key or by the app header,
+// and stores it in c.Locals("app") and c.Locals("app_id").
+// Requests presenting an unknown app, or a header conflicting with the basic-auth key, are rejected.
+// Requests that do not identify any app are passed through.
+func NewAppMiddleware(registry service.AppRegistry, header string) fiber.Handler {
+	if header == "" {
+		header = DefaultAppHeader
+	}
+
+	return func(c *fiber.Ctx) error {
+		// 1. identify app by basic-auth key
+		app := registry.FindByAuthKey(basicAuthKey(c.Get(fiber.HeaderAuthorization)))
+
+		// 2. identify app by header
+		if value := c.Get(header); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unknown_app", nil)
+			}
+
+			headerApp := registry.FindByID(id)
+			if headerApp == nil || (app != nil && app.ID != headerApp.ID) {
+				return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unknown_app", nil)
+			}
+			app = headerApp
+		}
+
+		if app != nil {
+			c.Locals("app", app)
+			c.Locals("app_id", app.ID)
+		}
+
+		return c.Next()
+	}
+}
+
+// AppFromCtx returns the app identified by the app middleware, or nil if none
+func AppFromCtx(c *fiber.Ctx) *entity.App {
+	app, _ := c.Locals("app").(*entity.App)
+	return app
+}
+
+// AppIDFromCtx returns the ID of the app identified by the app middleware, or entity.DefaultAppID if none
+func AppIDFromCtx(c *fiber.Ctx) int {
+	if appID, ok := c.Locals("app_id").(int); ok {
+		return appID
+	}
+	return entity.DefaultAppID
+}
+
+// basicAuthKey extracts the key (username) from a basic authorization header
+func basicAuthKey(authorization string) string {
+	const prefix = "Basic "
+	if !strings.HasPrefix(authorization, prefix) {
+		return ""
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(authorization[len(prefix):])
+	if err != nil {
+		return ""
+	}
+
+	key, _, _ := strings.Cut(string(decoded), ":")
+	return key
+}
+
+// allowsChannel checks if the calling app allows the given login channel
+func allowsChannel(c *fiber.Ctx, channel string) bool {
+	app := AppFromCtx(c)
+	return app == nil || app.AllowsChannel(channel)
+}
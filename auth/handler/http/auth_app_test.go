@@ -0,0 +1,70 @@
+package http_test
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	auth_http "github.com/budimanlai/go-core/auth/handler/http"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppMiddleware(t *testing.T) {
+	registry := service.NewAppRegistryImpl(
+		entity.App{ID: 1, Name: "web", AuthKeys: []string{"web-key"}},
+		entity.App{ID: 2, Name: "mobile", AuthKeys: []string{"mobile-key"}},
+	)
+
+	app := fiber.New()
+	app.Get("/", auth_http.NewAppMiddleware(registry, ""), func(c *fiber.Ctx) error {
+		return c.SendString(strconv.Itoa(auth_http.AppIDFromCtx(c)))
+	})
+
+	basic := func(key string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(key+":"))
+	}
+
+	tests := []struct {
+		name   string
+		auth   string
+		appID  string
+		status int
+		body   string
+	}{
+		{name: "no app is the default app", status: fiber.StatusOK, body: "1"},
+		{name: "basic-auth key", auth: basic("mobile-key"), status: fiber.StatusOK, body: "2"},
+		{name: "app header", appID: "2", status: fiber.StatusOK, body: "2"},
+		{name: "key and matching header", auth: basic("mobile-key"), appID: "2", status: fiber.StatusOK, body: "2"},
+		{name: "unknown key passes without app", auth: basic("other-key"), status: fiber.StatusOK, body: "1"},
+		{name: "key and conflicting header", auth: basic("mobile-key"), appID: "1", status: fiber.StatusUnauthorized},
+		{name: "unknown app header", appID: "3", status: fiber.StatusUnauthorized},
+		{name: "invalid app header", appID: "web", status: fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.auth != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.auth)
+			}
+			if tt.appID != "" {
+				req.Header.Set(auth_http.DefaultAppHeader, tt.appID)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			if tt.body != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/budimanlai/go-pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ChangePassword godoc
+// @Summary      Change Password
+// @Description  Change the password of the current user and log out the other sessions
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ChangePasswordRequest  true  "Change Password Request"
+// @Success      200      {object}  response.SuccessResponse
+// @Failure      400      {object}  response.ErrorResponse
+// @Failure      401      {object}  response.ErrorResponse
+// @Router       /auth/password/change [post]
+func (h *AuthHandler) ChangePassword(ctx *fiber.Ctx) error {
+	auth := GetAuthContext(ctx)
+	if auth == nil {
+		return response.ErrorI18n(ctx, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	// validate request
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	err := h.UserUC.ChangePassword(ctx.Context(), auth.UserID, auth.Token, req)
+	if err != nil {
+		return response.BadRequestI18n(ctx, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(ctx, "auth.success", nil)
+}
+
+// ChangeIdentifierRequest godoc
+// @Summary      Request Email or Handphone Change
+// @Description  Send an OTP to the new email or handphone of the current user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ChangeIdentifierRequest  true  "Change Identifier Request"
+// @Success      200      {object}  dto.ChangeIdentifierResponse
+// @Failure      400      {object}  response.ErrorResponse
+// @Failure      401      {object}  response.ErrorResponse
+// @Router       /auth/identifier/change [post]
+func (h *AuthHandler) ChangeIdentifierRequest(ctx *fiber.Ctx) error {
+	var req dto.ChangeIdentifierRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	// validate request
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	out, err := h.UserUC.RequestChangeIdentifier(ctx.Context(), GetUserID(ctx), req)
+	if err != nil {
+		return response.BadRequestI18n(ctx, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(ctx, "app.success", out)
+}
+
+// ChangeIdentifierConfirm godoc
+// @Summary      Confirm Email or Handphone Change
+// @Description  Verify the OTP of the new email or handphone and replace the old one
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ChangeIdentifierConfirmRequest  true  "Change Identifier Confirm Request"
+// @Success      200      {object}  response.SuccessResponse
+// @Failure      400      {object}  response.ErrorResponse
+// @Failure      401      {object}  response.ErrorResponse
+// @Router       /auth/identifier/change/confirm [post]
+func (h *AuthHandler) ChangeIdentifierConfirm(ctx *fiber.Ctx) error {
+	var req dto.ChangeIdentifierConfirmRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	// validate request
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	err := h.UserUC.ConfirmChangeIdentifier(ctx.Context(), GetUserID(ctx), req)
+	if err != nil {
+		return response.BadRequestI18n(ctx, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(ctx, "auth.success", nil)
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAuthContext returns the AuthContext resolved by the private middleware, or nil for anonymous requests
+func GetAuthContext(c *fiber.Ctx) *entity.AuthContext {
+	auth, _ := c.Locals(entity.AuthContextKey{}).(*entity.AuthContext)
+	return auth
+}
+
+// GetUserID returns the authenticated user ID, or 0 for anonymous requests
+func GetUserID(c *fiber.Ctx) uint {
+	if auth := GetAuthContext(c); auth != nil {
+		return auth.UserID
+	}
+	return 0
+}
+
+// GetSessionID returns the authenticated session ID, or 0 for anonymous requests
+func GetSessionID(c *fiber.Ctx) int {
+	if auth := GetAuthContext(c); auth != nil {
+		return auth.SessionID
+	}
+	return 0
+}
+
+// RequireScope rejects callers not granted the scope, from the token claims or the API key.
+// It runs after the private or the API key middleware.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := GetAuthContext(c)
+		if auth == nil {
+			return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+		}
+		if !auth.HasScope(scope) {
+			return response.ErrorI18n(c, fiber.StatusForbidden, "auth.error.missing_scope", nil)
+		}
+		return c.Next()
+	}
+}
+
+// DenyImpersonation rejects impersonation sessions, it guards the sensitive routes such as password changes.
+// It runs after the private middleware.
+func DenyImpersonation(c *fiber.Ctx) error {
+	if auth := GetAuthContext(c); auth != nil && auth.IsImpersonated() {
+		return response.ErrorI18n(c, fiber.StatusForbidden, "auth.error.impersonation_not_allowed", nil)
+	}
+	return c.Next()
+}
@@ -3,22 +3,28 @@ package http
 import (
 	"github.com/budimanlai/go-core/auth/domain/usecase"
 	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-pkg/helpers"
 	"github.com/budimanlai/go-pkg/response"
 	"github.com/budimanlai/go-pkg/validator"
 	"github.com/gofiber/fiber/v2"
 )
 
 type AuthHandler struct {
-	UserUC        usecase.UserUsecase
-	UserSessionUC usecase.UserSessionUsecase
-	OtpUC         usecase.OtpUsecase
+	UserUC         usecase.UserUsecase
+	UserSessionUC  usecase.UserSessionUsecase
+	OtpUC          usecase.OtpUsecase
+	ExternalAuthUC usecase.ExternalAuthUsecase
+	PasswordlessUC usecase.PasswordlessUsecase
 }
 
-func NewAuthHandler(userUsecase usecase.UserUsecase, userSessionUsecase usecase.UserSessionUsecase, otpUsecase usecase.OtpUsecase) *AuthHandler {
+func NewAuthHandler(userUsecase usecase.UserUsecase, userSessionUsecase usecase.UserSessionUsecase, otpUsecase usecase.OtpUsecase,
+	externalAuthUsecase usecase.ExternalAuthUsecase, passwordlessUsecase usecase.PasswordlessUsecase) *AuthHandler {
 	return &AuthHandler{
-		UserSessionUC: userSessionUsecase,
-		OtpUC:         otpUsecase,
-		UserUC:        userUsecase,
+		UserSessionUC:  userSessionUsecase,
+		OtpUC:          otpUsecase,
+		UserUC:         userUsecase,
+		ExternalAuthUC: externalAuthUsecase,
+		PasswordlessUC: passwordlessUsecase,
 	}
 }
 
@@ -44,7 +50,16 @@ func (h *AuthHandler) Login(ctx *fiber.Ctx) error {
 		return response.ValidationErrorI18n(ctx, err)
 	}
 
-	loginResponse, err := h.UserSessionUC.Login(ctx.Context(), req.Username, req.Password, ctx.IP(), ctx.Get("User-Agent"))
+	// check if the app allows the login channel
+	channel := "phone"
+	if helpers.IsValidEmail(req.Username) {
+		channel = "email"
+	}
+	if !allowsChannel(ctx, channel) {
+		return response.ErrorI18n(ctx, fiber.StatusForbidden, "auth.error.channel_not_allowed", nil)
+	}
+
+	loginResponse, err := h.UserSessionUC.Login(ctx.Context(), AppIDFromCtx(ctx), req.Username, req.Password, ctx.IP(), ctx.Get("User-Agent"))
 	if err != nil {
 		return response.ErrorI18n(ctx, fiber.StatusUnauthorized, "auth.error.invalid_credentials", nil)
 	}
@@ -98,6 +113,10 @@ func (h *AuthHandler) ResetPassword(ctx *fiber.Ctx) error {
 		return response.ValidationErrorI18n(ctx, err)
 	}
 
+	if !allowsChannel(ctx, req.Channel) {
+		return response.ErrorI18n(ctx, fiber.StatusForbidden, "auth.error.channel_not_allowed", nil)
+	}
+
 	// reset user password
 	err := h.UserUC.ResetPassword(ctx.Context(), req)
 	if err != nil {
@@ -132,8 +151,13 @@ func (h *AuthHandler) Register(ctx *fiber.Ctx) error {
 		return response.ValidationErrorI18n(ctx, err)
 	}
 
+	if !allowsChannel(ctx, req.Channel) {
+		return response.ErrorI18n(ctx, fiber.StatusForbidden, "auth.error.channel_not_allowed", nil)
+	}
+
 	req.FromIP = ctx.IP()
 	req.UserAgent = ctx.Get("User-Agent")
+	req.AppID = AppIDFromCtx(ctx)
 
 	// register user
 	out, err := h.UserUC.Register(ctx.Context(), req)
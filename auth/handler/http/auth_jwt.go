@@ -0,0 +1,47 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// NewJWTMiddleware verifies the bearer token with the TokenService and passes its claims to successHandler.
+// It replaces the go-pkg JWT middleware when tokens are signed with asymmetric, rotating keys.
+func NewJWTMiddleware(tokenService service.TokenService, successHandler func(*fiber.Ctx, jwt.MapClaims) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		tokenString, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || tokenString == "" {
+			return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+		}
+
+		claims, err := tokenService.ParseToken(tokenString)
+		if err != nil {
+			return response.ErrorI18n(c, fiber.StatusUnauthorized, "auth.error.invalid_token", nil)
+		}
+
+		if err := successHandler(c, claims); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// JWKS godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys verifying the issued tokens
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  service.JSONWebKeySet
+// @Router       /.well-known/jwks.json [get]
+func JWKS(keySet service.KeySet) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// served raw, JWKS consumers do not understand the response envelope
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.JSON(keySet.JWKS())
+	}
+}
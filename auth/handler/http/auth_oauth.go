@@ -0,0 +1,193 @@
+package http
+
+import (
+	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/budimanlai/go-pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// OAuthAuthURL godoc
+// @Summary      Start External Login
+// @Description  Start the authorization code + PKCE flow and return the provider's consent URL
+// @Tags         Auth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Success      200       {object}  dto.OAuthURLResponse
+// @Failure      404       {object}  response.ErrorResponse
+// @Router       /auth/oauth/{provider} [get]
+func (h *AuthHandler) OAuthAuthURL(ctx *fiber.Ctx) error {
+	provider := ctx.Params("provider")
+	if !h.ExternalAuthUC.HasProvider(provider) {
+		return response.ErrorI18n(ctx, fiber.StatusNotFound, "auth.error.unknown_provider", nil)
+	}
+
+	authURL, err := h.ExternalAuthUC.AuthURL(ctx.Context(), provider, AppIDFromCtx(ctx))
+	if err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusInternalServerError, "app.error.internal", nil)
+	}
+
+	return response.SuccessI18n(ctx, "app.success", dto.OAuthURLResponse{AuthURL: authURL})
+}
+
+// OAuthNonce godoc
+// @Summary      External Login Nonce
+// @Description  Issue a single use nonce for the provider's native SDK, send it back with the ID token
+// @Tags         Auth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Success      200       {object}  dto.OAuthNonceResponse
+// @Failure      404       {object}  response.ErrorResponse
+// @Router       /auth/oauth/{provider}/nonce [get]
+func (h *AuthHandler) OAuthNonce(ctx *fiber.Ctx) error {
+	provider := ctx.Params("provider")
+	if !h.ExternalAuthUC.HasProvider(provider) {
+		return response.ErrorI18n(ctx, fiber.StatusNotFound, "auth.error.unknown_provider", nil)
+	}
+
+	nonce, err := h.ExternalAuthUC.IssueNonce(ctx.Context(), provider, AppIDFromCtx(ctx))
+	if err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusInternalServerError, "app.error.internal", nil)
+	}
+
+	return response.SuccessI18n(ctx, "app.success", dto.OAuthNonceResponse{Nonce: nonce})
+}
+
+// OAuthCallback godoc
+// @Summary      External Login Callback
+// @Description  Complete the authorization code flow and log the linked user in. Accepts query (GET) or form_post (POST).
+// @Tags         Auth
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Param        state     query     string  true  "State"
+// @Param        code      query     string  true  "Authorization code"
+// @Success      200       {object}  dto.LoginResponse
+// @Failure      400       {object}  response.ErrorResponse
+// @Failure      401       {object}  response.ErrorResponse
+// @Router       /auth/oauth/{provider}/callback [get]
+func (h *AuthHandler) OAuthCallback(ctx *fiber.Ctx) error {
+	var req dto.OAuthCallbackRequest
+	if ctx.Method() == fiber.MethodPost {
+		if err := ctx.BodyParser(&req); err != nil {
+			return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+		}
+	} else if err := ctx.QueryParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	loginResponse, err := h.ExternalAuthUC.Callback(ctx.Context(), ctx.Params("provider"), req.State, req.Code, ctx.IP(), ctx.Get("User-Agent"))
+	if err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusUnauthorized, "auth.error.external_login_failed", nil)
+	}
+
+	return response.SuccessI18n(ctx, "app.success", loginResponse)
+}
+
+// OAuthIDToken godoc
+// @Summary      External Login with ID Token
+// @Description  Log in with an ID token obtained by the provider's native SDK
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        provider  path      string                   true  "Provider name"
+// @Param        request   body      dto.OAuthIDTokenRequest  true  "ID Token Request"
+// @Success      200       {object}  dto.LoginResponse
+// @Failure      400       {object}  response.ErrorResponse
+// @Failure      401       {object}  response.ErrorResponse
+// @Router       /auth/oauth/{provider}/token [post]
+func (h *AuthHandler) OAuthIDToken(ctx *fiber.Ctx) error {
+	var req dto.OAuthIDTokenRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	loginResponse, err := h.ExternalAuthUC.LoginWithIDToken(ctx.Context(), ctx.Params("provider"), req.IDToken, req.Nonce,
+		AppIDFromCtx(ctx), ctx.IP(), ctx.Get("User-Agent"))
+	if err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusUnauthorized, "auth.error.external_login_failed", nil)
+	}
+
+	return response.SuccessI18n(ctx, "app.success", loginResponse)
+}
+
+// GetIdentities godoc
+// @Summary      Linked Identities
+// @Description  List the external identities linked to the current user
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {array}   dto.UserIdentity
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/identities [get]
+func (h *AuthHandler) GetIdentities(ctx *fiber.Ctx) error {
+	identities, err := h.ExternalAuthUC.GetIdentities(ctx.Context(), GetUserID(ctx))
+	if err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusInternalServerError, "app.error.internal", nil)
+	}
+
+	out := make([]dto.UserIdentity, 0, len(identities))
+	for _, identity := range identities {
+		out = append(out, dto.UserIdentity{
+			Provider:  identity.Provider,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+
+	return response.SuccessI18n(ctx, "app.success", out)
+}
+
+// LinkIdentity godoc
+// @Summary      Link Identity
+// @Description  Link an external identity to the current user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        provider  path      string                   true  "Provider name"
+// @Param        request   body      dto.OAuthIDTokenRequest  true  "ID Token Request"
+// @Success      200       {object}  response.SuccessResponse
+// @Failure      400       {object}  response.ErrorResponse
+// @Router       /auth/identities/{provider} [post]
+func (h *AuthHandler) LinkIdentity(ctx *fiber.Ctx) error {
+	var req dto.OAuthIDTokenRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	err := h.ExternalAuthUC.Link(ctx.Context(), GetUserID(ctx), ctx.Params("provider"), req.IDToken, req.Nonce)
+	if err != nil {
+		return response.BadRequestI18n(ctx, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(ctx, "auth.success", nil)
+}
+
+// UnlinkIdentity godoc
+// @Summary      Unlink Identity
+// @Description  Remove the current user's linked identity at the given provider
+// @Tags         Auth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Success      200       {object}  response.SuccessResponse
+// @Failure      400       {object}  response.ErrorResponse
+// @Router       /auth/identities/{provider} [delete]
+func (h *AuthHandler) UnlinkIdentity(ctx *fiber.Ctx) error {
+	err := h.ExternalAuthUC.Unlink(ctx.Context(), GetUserID(ctx), ctx.Params("provider"))
+	if err != nil {
+		return response.BadRequestI18n(ctx, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(ctx, "auth.success", nil)
+}
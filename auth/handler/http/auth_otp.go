@@ -28,6 +28,10 @@ func (h *AuthHandler) RequestOtp(ctx *fiber.Ctx) error {
 		return response.ValidationErrorI18n(ctx, err)
 	}
 
+	if !allowsChannel(ctx, req.Channel) {
+		return response.ErrorI18n(ctx, fiber.StatusForbidden, "auth.error.channel_not_allowed", nil)
+	}
+
 	resp, err := h.OtpUC.GenerateOTP(ctx.Context(), req)
 	if err != nil {
 		return response.BadRequestI18n(ctx, err.Error(), nil)
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/budimanlai/go-pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PasswordlessRequest godoc
+// @Summary      Request Passwordless Login
+// @Description  Send an OTP or a magic link to log in without password
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.PasswordlessRequest  true  "Passwordless Request"
+// @Success      200      {object}  dto.PasswordlessResponse
+// @Failure      400      {object}  response.ErrorResponse
+// @Router       /auth/passwordless/request [post]
+func (h *AuthHandler) PasswordlessRequest(ctx *fiber.Ctx) error {
+	var req dto.PasswordlessRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	// validate request
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	if !allowsChannel(ctx, req.Channel) {
+		return response.ErrorI18n(ctx, fiber.StatusForbidden, "auth.error.channel_not_allowed", nil)
+	}
+
+	out, err := h.PasswordlessUC.RequestLogin(ctx.Context(), req, AppIDFromCtx(ctx))
+	if err != nil {
+		return response.BadRequestI18n(ctx, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(ctx, "app.success", out)
+}
+
+// PasswordlessVerify godoc
+// @Summary      Passwordless Login with OTP
+// @Description  Verify the OTP sent by the passwordless request and return JWT token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.PasswordlessVerifyRequest  true  "Passwordless Verify Request"
+// @Success      200      {object}  dto.LoginResponse
+// @Failure      400      {object}  response.ErrorResponse
+// @Failure      401      {object}  response.ErrorResponse
+// @Router       /auth/passwordless/verify [post]
+func (h *AuthHandler) PasswordlessVerify(ctx *fiber.Ctx) error {
+	var req dto.PasswordlessVerifyRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	// validate request
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	loginResponse, err := h.PasswordlessUC.VerifyOtp(ctx.Context(), req, AppIDFromCtx(ctx), ctx.IP(), ctx.Get("User-Agent"))
+	if err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusUnauthorized, "auth.error.invalid_otp", nil)
+	}
+
+	return response.SuccessI18n(ctx, "app.success", loginResponse)
+}
+
+// PasswordlessMagicLink godoc
+// @Summary      Passwordless Login with Magic Link
+// @Description  Consume the one-time token of a magic link and return JWT token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.MagicLinkRequest  true  "Magic Link Request"
+// @Success      200      {object}  dto.LoginResponse
+// @Failure      400      {object}  response.ErrorResponse
+// @Failure      401      {object}  response.ErrorResponse
+// @Router       /auth/passwordless/magic [post]
+func (h *AuthHandler) PasswordlessMagicLink(ctx *fiber.Ctx) error {
+	var req dto.MagicLinkRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	// validate request
+	if err := validator.ValidateStructWithContext(ctx, &req); err != nil {
+		return response.ValidationErrorI18n(ctx, err)
+	}
+
+	loginResponse, err := h.PasswordlessUC.ConsumeMagicLink(ctx.Context(), req.Token, ctx.IP(), ctx.Get("User-Agent"))
+	if err != nil {
+		return response.ErrorI18n(ctx, fiber.StatusUnauthorized, "auth.error.invalid_magic_link", nil)
+	}
+
+	return response.SuccessI18n(ctx, "app.success", loginResponse)
+}
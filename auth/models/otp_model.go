@@ -8,6 +8,7 @@ type Otp struct {
 	TrxID     string    `gorm:"column:trx_id;type:varchar(50);default:''"`
 	PinCode   string    `gorm:"column:pin_code;type:char(6);default:''"`
 	Status    string    `gorm:"column:status;type:varchar(15);default:'waiting'"`
+	Attempts  int       `gorm:"column:attempts;not null;default:0"`
 	CreatedAt time.Time `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
 }
 
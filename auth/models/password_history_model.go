@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type PasswordHistory struct {
+	ID           int       `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID       int       `gorm:"column:user_id;not null;index"`
+	PasswordHash string    `gorm:"column:password_hash;type:varchar(255);not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "user_password_histories"
+}
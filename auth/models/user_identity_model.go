@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type UserIdentity struct {
+	ID        int       `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID    int       `gorm:"column:user_id;not null;index"`
+	Provider  string    `gorm:"column:provider;type:varchar(50);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string    `gorm:"column:subject;type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string    `gorm:"column:email;type:varchar(255);default:''"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	ID           int       `gorm:"column:id;primaryKey;autoIncrement"`
@@ -10,11 +14,17 @@ type User struct {
 	PasswordHash string    `gorm:"column:password_hash;type:varchar(255);not null"`
 	Email        string    `gorm:"column:email;type:varchar(255);not null;uniqueIndex"`
 	Handphone    string    `gorm:"column:handphone;type:varchar(20);not null;default:'';uniqueIndex"`
-	Status       string    `gorm:"column:status;type:varchar(15);not null;default:'active'"`
+	Status       string    `gorm:"column:status;type:varchar(25);not null;default:'active'"`
 	CreatedBy    uint      `gorm:"column:created_by;type:int;not null"`
 	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedBy    uint      `gorm:"column:updated_by;type:int;not null"`
 	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime:milli"`
+
+	// VerificationToken is set until the email is verified in the account module
+	VerificationToken *string `gorm:"column:verification_token;type:varchar(255);uniqueIndex"`
+
+	// DeletedAt hides users deleted from the account module
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 func (User) TableName() string {
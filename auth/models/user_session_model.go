@@ -14,6 +14,9 @@ type UserSession struct {
 	RemoveOn     *time.Time `gorm:"column:remove_on"`
 	FromIP       string     `gorm:"column:from_ip;type:varchar(15);default:'';not null"`
 	UserAgent    string     `gorm:"column:user_agent;type:varchar(256)"`
+
+	ImpersonatorID uint       `gorm:"column:impersonator_id;not null;default:0;index"`
+	ExpireOn       *time.Time `gorm:"column:expire_on"`
 }
 
 func (UserSession) TableName() string {
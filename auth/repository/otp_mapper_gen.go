@@ -0,0 +1,39 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	model "github.com/budimanlai/go-core/auth/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.Otp, model.Otp](otpMapper{})
+}
+
+// otpMapper maps entity.Otp and model.Otp without reflection
+type otpMapper struct{}
+
+func (otpMapper) ToModel(e *entity.Otp, m *model.Otp) error {
+	m.ID = e.ID
+	m.Handphone = e.Handphone
+	m.TrxID = e.TrxID
+	m.PinCode = e.PinCode
+	m.Status = e.Status
+	m.Attempts = e.Attempts
+	m.CreatedAt = e.CreatedAt
+	return nil
+}
+
+func (otpMapper) ToEntity(m *model.Otp, e *entity.Otp) error {
+	e.ID = m.ID
+	e.Handphone = m.Handphone
+	e.TrxID = m.TrxID
+	e.PinCode = m.PinCode
+	e.Status = m.Status
+	e.Attempts = m.Attempts
+	e.CreatedAt = m.CreatedAt
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/budimanlai/go-core/base"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/auth/domain/entity.Otp -model github.com/budimanlai/go-core/auth/models.Otp
+
 type OtpRepositoryImpl struct {
 	base.BaseRepository[entity.Otp, model.Otp]
 }
@@ -0,0 +1,33 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	model "github.com/budimanlai/go-core/auth/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.PasswordHistory, model.PasswordHistory](passwordHistoryMapper{})
+}
+
+// passwordHistoryMapper maps entity.PasswordHistory and model.PasswordHistory without reflection
+type passwordHistoryMapper struct{}
+
+func (passwordHistoryMapper) ToModel(e *entity.PasswordHistory, m *model.PasswordHistory) error {
+	m.ID = e.ID
+	m.UserID = int(e.UserID)
+	m.PasswordHash = e.PasswordHash
+	m.CreatedAt = e.CreatedAt
+	return nil
+}
+
+func (passwordHistoryMapper) ToEntity(m *model.PasswordHistory, e *entity.PasswordHistory) error {
+	e.ID = m.ID
+	e.UserID = uint(m.UserID)
+	e.PasswordHash = m.PasswordHash
+	e.CreatedAt = m.CreatedAt
+	return nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	repository "github.com/budimanlai/go-core/auth/domain/repository"
+	model "github.com/budimanlai/go-core/auth/models"
+	"github.com/budimanlai/go-core/base"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/auth/domain/entity.PasswordHistory -model github.com/budimanlai/go-core/auth/models.PasswordHistory
+
+type PasswordHistoryRepositoryImpl struct {
+	base.BaseRepository[entity.PasswordHistory, model.PasswordHistory]
+}
+
+func NewPasswordHistoryRepositoryImpl(f *base.Factory) repository.PasswordHistoryRepository {
+	return &PasswordHistoryRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.PasswordHistory, model.PasswordHistory](f),
+	}
+}
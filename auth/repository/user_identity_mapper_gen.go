@@ -0,0 +1,37 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	model "github.com/budimanlai/go-core/auth/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.UserIdentity, model.UserIdentity](userIdentityMapper{})
+}
+
+// userIdentityMapper maps entity.UserIdentity and model.UserIdentity without reflection
+type userIdentityMapper struct{}
+
+func (userIdentityMapper) ToModel(e *entity.UserIdentity, m *model.UserIdentity) error {
+	m.ID = e.ID
+	m.UserID = int(e.UserID)
+	m.Provider = e.Provider
+	m.Subject = e.Subject
+	m.Email = e.Email
+	m.CreatedAt = e.CreatedAt
+	return nil
+}
+
+func (userIdentityMapper) ToEntity(m *model.UserIdentity, e *entity.UserIdentity) error {
+	e.ID = m.ID
+	e.UserID = uint(m.UserID)
+	e.Provider = m.Provider
+	e.Subject = m.Subject
+	e.Email = m.Email
+	e.CreatedAt = m.CreatedAt
+	return nil
+}
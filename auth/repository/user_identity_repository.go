@@ -0,0 +1,20 @@
+package repository
+
+import (
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	repository "github.com/budimanlai/go-core/auth/domain/repository"
+	model "github.com/budimanlai/go-core/auth/models"
+	"github.com/budimanlai/go-core/base"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/auth/domain/entity.UserIdentity -model github.com/budimanlai/go-core/auth/models.UserIdentity
+
+type UserIdentityRepositoryImpl struct {
+	base.BaseRepository[entity.UserIdentity, model.UserIdentity]
+}
+
+func NewUserIdentityRepositoryImpl(f *base.Factory) repository.UserIdentityRepository {
+	return &UserIdentityRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.UserIdentity, model.UserIdentity](f),
+	}
+}
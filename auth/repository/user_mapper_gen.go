@@ -0,0 +1,51 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	model "github.com/budimanlai/go-core/auth/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.User, model.User](userMapper{})
+}
+
+// userMapper maps entity.User and model.User without reflection
+type userMapper struct{}
+
+func (userMapper) ToModel(e *entity.User, m *model.User) error {
+	m.ID = int(e.ID)
+	m.Username = e.Username
+	m.Fullname = e.Fullname
+	m.AuthKey = e.AuthKey
+	m.PasswordHash = e.PasswordHash
+	m.Email = e.Email
+	m.Handphone = e.Handphone
+	m.Status = e.Status
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+	m.CreatedBy = e.CreatedBy
+	m.UpdatedBy = e.UpdatedBy
+	m.VerificationToken = e.VerificationToken
+	return nil
+}
+
+func (userMapper) ToEntity(m *model.User, e *entity.User) error {
+	e.ID = uint(m.ID)
+	e.Username = m.Username
+	e.Fullname = m.Fullname
+	e.AuthKey = m.AuthKey
+	e.PasswordHash = m.PasswordHash
+	e.Email = m.Email
+	e.Handphone = m.Handphone
+	e.Status = m.Status
+	e.CreatedBy = m.CreatedBy
+	e.CreatedAt = m.CreatedAt
+	e.UpdatedBy = m.UpdatedBy
+	e.UpdatedAt = m.UpdatedAt
+	e.VerificationToken = m.VerificationToken
+	return nil
+}
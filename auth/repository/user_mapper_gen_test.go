@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/jinzhu/copier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	model "github.com/budimanlai/go-core/auth/models"
+	_ "github.com/budimanlai/go-core/auth/repository"
+)
+
+func newUserModel() model.User {
+	now := time.Now()
+	return model.User{
+		ID:           42,
+		Username:     "budi",
+		Fullname:     "Budi Santoso",
+		AuthKey:      "key",
+		PasswordHash: "hash",
+		Email:        "budi@example.com",
+		Handphone:    "628123456789",
+		Status:       "active",
+		CreatedBy:    1,
+		CreatedAt:    now,
+		UpdatedBy:    1,
+		UpdatedAt:    now,
+	}
+}
+
+func TestUserMapper(t *testing.T) {
+	mapper := base.MapperFor[entity.User, model.User]()
+	m := newUserModel()
+
+	var e entity.User
+	require.NoError(t, mapper.ToEntity(&m, &e))
+	assert.Equal(t, uint(42), e.ID)
+	assert.Equal(t, m.Email, e.Email)
+	assert.Equal(t, m.UpdatedAt, e.UpdatedAt)
+
+	var back model.User
+	require.NoError(t, mapper.ToModel(&e, &back))
+	assert.Equal(t, m, back)
+}
+
+func BenchmarkUserMapper_Generated(b *testing.B) {
+	mapper := base.MapperFor[entity.User, model.User]()
+	m := newUserModel()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		var e entity.User
+		if err := mapper.ToEntity(&m, &e); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUserMapper_Copier(b *testing.B) {
+	m := newUserModel()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		var e entity.User
+		if err := copier.Copy(&e, &m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUserMapper_GeneratedSlice(b *testing.B) {
+	mapper := base.MapperFor[entity.User, model.User]()
+	models := make([]model.User, 100)
+	for i := range models {
+		models[i] = newUserModel()
+	}
+	b.ReportAllocs()
+
+	for b.Loop() {
+		entities := make([]entity.User, len(models))
+		for i := range models {
+			if err := mapper.ToEntity(&models[i], &entities[i]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkUserMapper_CopierSlice(b *testing.B) {
+	models := make([]model.User, 100)
+	for i := range models {
+		models[i] = newUserModel()
+	}
+	b.ReportAllocs()
+
+	for b.Loop() {
+		var entities []entity.User
+		if err := copier.Copy(&entities, &models); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	model "github.com/budimanlai/go-core/auth/models"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/auth/domain/entity.User -model github.com/budimanlai/go-core/auth/models.User
+
 type userRepositoryImpl struct {
 	base.BaseRepository[entity.User, model.User]
 }
@@ -0,0 +1,47 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/auth/domain/entity"
+	model "github.com/budimanlai/go-core/auth/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.UserSession, model.UserSession](userSessionMapper{})
+}
+
+// userSessionMapper maps entity.UserSession and model.UserSession without reflection
+type userSessionMapper struct{}
+
+func (userSessionMapper) ToModel(e *entity.UserSession, m *model.UserSession) error {
+	m.ID = e.ID
+	m.AppID = e.AppID
+	m.UserID = int(e.UserID)
+	m.Tokens = e.Tokens
+	m.CreateOn = e.CreateOn
+	m.LastAccessOn = e.LastAccessOn
+	m.RemoveOn = e.RemoveOn
+	m.FromIP = e.FromIP
+	m.UserAgent = e.UserAgent
+	m.ImpersonatorID = e.ImpersonatorID
+	m.ExpireOn = e.ExpireOn
+	return nil
+}
+
+func (userSessionMapper) ToEntity(m *model.UserSession, e *entity.UserSession) error {
+	e.ID = m.ID
+	e.AppID = m.AppID
+	e.UserID = uint(m.UserID)
+	e.Tokens = m.Tokens
+	e.CreateOn = m.CreateOn
+	e.LastAccessOn = m.LastAccessOn
+	e.RemoveOn = m.RemoveOn
+	e.FromIP = m.FromIP
+	e.UserAgent = m.UserAgent
+	e.ImpersonatorID = m.ImpersonatorID
+	e.ExpireOn = m.ExpireOn
+	return nil
+}
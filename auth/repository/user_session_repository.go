@@ -7,6 +7,8 @@ import (
 	"github.com/budimanlai/go-core/base"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/auth/domain/entity.UserSession -model github.com/budimanlai/go-core/auth/models.UserSession
+
 type userSessionRepositoryImpl struct {
 	base.BaseRepository[entity.UserSession, model.UserSession]
 }
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+)
+
+type AppRegistryImpl struct {
+	mu   sync.RWMutex
+	apps map[int]entity.App
+}
+
+func NewAppRegistryImpl(apps ...entity.App) AppRegistry {
+	r := &AppRegistryImpl{
+		apps: make(map[int]entity.App),
+	}
+	for _, app := range apps {
+		r.Register(app)
+	}
+	return r
+}
+
+// Register adds or replaces an app in the registry.
+func (r *AppRegistryImpl) Register(app entity.App) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apps[app.ID] = app
+}
+
+// FindByID returns the app with the given ID, or nil if it is not registered.
+func (r *AppRegistryImpl) FindByID(id int) *entity.App {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	app, ok := r.apps[id]
+	if !ok {
+		return nil
+	}
+	return &app
+}
+
+// FindByAuthKey returns the app owning the given basic-auth key, or nil if none.
+func (r *AppRegistryImpl) FindByAuthKey(key string) *entity.App {
+	if key == "" {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, app := range r.apps {
+		if app.HasAuthKey(key) {
+			return &app
+		}
+	}
+	return nil
+}
+
+// All returns every registered app ordered by ID.
+func (r *AppRegistryImpl) All() []entity.App {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]entity.App, 0, len(r.apps))
+	for _, app := range r.apps {
+		out = append(out, app)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// HasApps reports whether at least one app is registered.
+func (r *AppRegistryImpl) HasApps() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.apps) > 0
+}
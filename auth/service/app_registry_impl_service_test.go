@@ -0,0 +1,46 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppRegistryImpl(t *testing.T) {
+	registry := service.NewAppRegistryImpl()
+	assert.False(t, registry.HasApps())
+	assert.Empty(t, registry.All())
+
+	registry.Register(entity.App{ID: 2, Name: "mobile", AuthKeys: []string{"mobile-key", "mobile-old-key"}})
+	registry.Register(entity.App{ID: 1, Name: "web", AuthKeys: []string{"web-key"}})
+	assert.True(t, registry.HasApps())
+
+	all := registry.All()
+	require.Len(t, all, 2)
+	assert.Equal(t, 1, all[0].ID, "ordered by ID")
+	assert.Equal(t, 2, all[1].ID)
+
+	app := registry.FindByID(2)
+	require.NotNil(t, app)
+	assert.Equal(t, "mobile", app.Name)
+	assert.Nil(t, registry.FindByID(3))
+
+	// every key of an app identifies it
+	for _, key := range []string{"mobile-key", "mobile-old-key"} {
+		app := registry.FindByAuthKey(key)
+		require.NotNil(t, app, key)
+		assert.Equal(t, 2, app.ID)
+	}
+	assert.Nil(t, registry.FindByAuthKey(""))
+	assert.Nil(t, registry.FindByAuthKey("mobile"))
+	assert.Nil(t, registry.FindByAuthKey("mobile-key-2"))
+
+	// register replaces the app with the same ID
+	registry.Register(entity.App{ID: 2, Name: "mobile", AuthKeys: []string{"mobile-new-key"}})
+	assert.Nil(t, registry.FindByAuthKey("mobile-key"))
+	assert.NotNil(t, registry.FindByAuthKey("mobile-new-key"))
+	assert.Len(t, registry.All(), 2)
+}
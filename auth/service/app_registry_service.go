@@ -0,0 +1,20 @@
+package service
+
+import "github.com/budimanlai/go-core/auth/domain/entity"
+
+type AppRegistry interface {
+	// Register adds or replaces an app in the registry.
+	Register(app entity.App)
+
+	// FindByID returns the app with the given ID, or nil if it is not registered.
+	FindByID(id int) *entity.App
+
+	// FindByAuthKey returns the app owning the given basic-auth key, or nil if none.
+	FindByAuthKey(key string) *entity.App
+
+	// All returns every registered app.
+	All() []entity.App
+
+	// HasApps reports whether at least one app is registered.
+	HasApps() bool
+}
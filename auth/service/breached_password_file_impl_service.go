@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hashPrefixLength is the k-anonymity prefix length of the Have I Been Pwned range format
+const hashPrefixLength = 5
+
+// BreachedPasswordFileImpl checks SHA-1 password hashes against a local copy of the
+// Have I Been Pwned list, the password never leaves the host.
+//
+// The path is either a directory of range files named by the 5 character hash prefix
+// (e.g. "5BAA6" or "5BAA6.txt") holding "SUFFIX:COUNT" lines, as served by the range API,
+// or a single file of "HASH:COUNT" lines ordered by hash, searched without loading it.
+type BreachedPasswordFileImpl struct {
+	path string
+}
+
+func NewBreachedPasswordFileImpl(path string) BreachedPasswordChecker {
+	return &BreachedPasswordFileImpl{
+		path: path,
+	}
+}
+
+func (s *BreachedPasswordFileImpl) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false, err
+	}
+	if info.IsDir() {
+		return s.searchRange(hash)
+	}
+	return s.searchSorted(hash, info.Size())
+}
+
+// searchRange scans the range file of the hash prefix
+func (s *BreachedPasswordFileImpl) searchRange(hash string) (bool, error) {
+	prefix, suffix := hash[:hashPrefixLength], hash[hashPrefixLength:]
+
+	f, err := os.Open(filepath.Join(s.path, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(filepath.Join(s.path, prefix))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if lineHash(scanner.Text()) == suffix {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// searchSorted binary searches the ordered hash file by byte offset
+func (s *BreachedPasswordFileImpl) searchSorted(hash string, size int64) (bool, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	// lo is always the start of a line, the searched line starts in [lo, hi)
+	lo, hi := int64(0), size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		// first line starting at or after mid
+		start := lo
+		if mid > lo {
+			if start, err = nextLineStart(f, mid-1); err != nil {
+				return false, err
+			}
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		line, end, err := readLine(f, start)
+		if err != nil {
+			return false, err
+		}
+
+		switch cmp := strings.Compare(lineHash(line), hash); {
+		case cmp == 0:
+			return true, nil
+		case cmp < 0:
+			lo = end
+		default:
+			hi = start
+		}
+	}
+	return false, nil
+}
+
+// nextLineStart returns the offset following the first newline at or after offset
+func nextLineStart(f *os.File, offset int64) (int64, error) {
+	buf := make([]byte, 128)
+	for {
+		n, err := f.ReadAt(buf, offset)
+		if i := strings.IndexByte(string(buf[:n]), '\n'); i >= 0 {
+			return offset + int64(i) + 1, nil
+		}
+		offset += int64(n)
+		if err == io.EOF {
+			return offset, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// readLine reads the line starting at offset and returns it with the offset of the next line
+func readLine(f *os.File, offset int64) (string, int64, error) {
+	end, err := nextLineStart(f, offset)
+	if err != nil {
+		return "", 0, err
+	}
+
+	buf := make([]byte, end-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil && err != io.EOF {
+		return "", 0, err
+	}
+	return strings.TrimRight(string(buf), "\r\n"), end, nil
+}
+
+// lineHash returns the upper case hash of a "HASH:COUNT" line
+func lineHash(line string) string {
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.ToUpper(strings.TrimSpace(line))
+}
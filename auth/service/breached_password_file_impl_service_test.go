@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestBreachedPasswordFileImpl(t *testing.T) {
+	breached := []string{"password", "123456", "qwerty", "letmein", "iloveyou", "monkey", "dragon"}
+	ctx := context.Background()
+
+	t.Run("Sorted File", func(t *testing.T) {
+		var lines []string
+		for i, password := range breached {
+			lines = append(lines, fmt.Sprintf("%s:%d", sha1Hex(password), i+1))
+		}
+		sort.Strings(lines)
+
+		path := filepath.Join(t.TempDir(), "pwned.txt")
+		require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o644))
+		checker := service.NewBreachedPasswordFileImpl(path)
+
+		for _, password := range breached {
+			found, err := checker.IsBreached(ctx, password)
+			require.NoError(t, err)
+			assert.True(t, found, password)
+		}
+
+		found, err := checker.IsBreached(ctx, "correct horse battery staple")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("Range Directory", func(t *testing.T) {
+		dir := t.TempDir()
+		hash := sha1Hex("password")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(hash[5:]+":3861493\n"), 0o644))
+		checker := service.NewBreachedPasswordFileImpl(dir)
+
+		found, err := checker.IsBreached(ctx, "password")
+		require.NoError(t, err)
+		assert.True(t, found)
+
+		found, err = checker.IsBreached(ctx, "correct horse battery staple")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
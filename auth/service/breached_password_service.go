@@ -0,0 +1,8 @@
+package service
+
+import "context"
+
+type BreachedPasswordChecker interface {
+	// IsBreached returns whether the password appears in a known data breach
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// KeySetImpl keeps the signing keys in memory. Instances sharing tokens must share keys:
+// load them with AddKey and rotate from a single place instead of using StartRotation on each instance.
+type KeySetImpl struct {
+	mu     sync.RWMutex
+	config KeySetConfig
+	keys   []SigningKey
+}
+
+// NewKeySetImpl creates a KeySet with the given keys, generating a first key when none is given.
+func NewKeySetImpl(config KeySetConfig, keys ...SigningKey) (KeySet, error) {
+	if config.Method == "" {
+		config.Method = "RS256"
+	}
+	if !isAsymmetricMethod(config.Method) {
+		return nil, fmt.Errorf("unsupported signing method %q", config.Method)
+	}
+	if config.RotationInterval > 0 && config.Overlap <= 0 {
+		return nil, errors.New("key set overlap is required with scheduled rotation, set it to at least the token lifetime")
+	}
+
+	ks := &KeySetImpl{config: config}
+	for _, key := range keys {
+		ks.AddKey(key)
+	}
+	if current := ks.Current(); current == nil || current.ExpiresAt != nil {
+		if err := ks.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+func isAsymmetricMethod(method string) bool {
+	switch method {
+	case "RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA":
+		return true
+	}
+	return false
+}
+
+// GenerateSigningKey generates a new key for the given signing method
+func GenerateSigningKey(method string) (*SigningKey, error) {
+	var signer crypto.Signer
+	var err error
+
+	switch method {
+	case "RS256", "RS384", "RS512":
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ES256":
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ES384":
+		signer, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "ES512":
+		signer, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case "EdDSA":
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing method %q", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kid := make([]byte, 8)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		ID:         hex.EncodeToString(kid),
+		Method:     method,
+		PrivateKey: signer,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// ParsePrivateKeyPEM parses a PKCS#8, PKCS#1 (RSA) or SEC 1 (EC) PEM private key, e.g. to load keys with AddKey
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.New("unsupported private key type")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func (ks *KeySetImpl) Current() *SigningKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if len(ks.keys) == 0 {
+		return nil
+	}
+	key := ks.keys[len(ks.keys)-1]
+	return &key
+}
+
+func (ks *KeySetImpl) PublicKey(kid string) interface{} {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+	for _, key := range ks.keys {
+		if key.ID == kid && (key.ExpiresAt == nil || now.Before(*key.ExpiresAt)) {
+			return key.PrivateKey.Public()
+		}
+	}
+	return nil
+}
+
+func (ks *KeySetImpl) AddKey(key SigningKey) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if key.ExpiresAt != nil {
+		if !time.Now().Before(*key.ExpiresAt) {
+			return
+		}
+		// a rotated out key goes before the signing key, which stays last
+		n := len(ks.keys)
+		if n > 0 && ks.keys[n-1].ExpiresAt == nil {
+			ks.keys = append(ks.keys[:n-1], key, ks.keys[n-1])
+		} else {
+			ks.keys = append(ks.keys, key)
+		}
+		return
+	}
+
+	ks.retireCurrent()
+	ks.keys = append(ks.keys, key)
+}
+
+func (ks *KeySetImpl) Rotate() error {
+	key, err := GenerateSigningKey(ks.config.Method)
+	if err != nil {
+		return err
+	}
+
+	ks.AddKey(*key)
+	return nil
+}
+
+// retireCurrent sets the expiry of the signing key and drops keys past their overlap window.
+// The caller must hold the lock.
+func (ks *KeySetImpl) retireCurrent() {
+	now := time.Now()
+	if n := len(ks.keys); n > 0 && ks.keys[n-1].ExpiresAt == nil {
+		expiresAt := now.Add(ks.config.Overlap)
+		ks.keys[n-1].ExpiresAt = &expiresAt
+	}
+
+	keys := ks.keys[:0]
+	for _, key := range ks.keys {
+		if key.ExpiresAt == nil || now.Before(*key.ExpiresAt) {
+			keys = append(keys, key)
+		}
+	}
+	ks.keys = keys
+}
+
+func (ks *KeySetImpl) StartRotation(ctx context.Context) {
+	if ks.config.RotationInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(ks.config.RotationInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ks.Rotate()
+			}
+		}
+	}()
+}
+
+func (ks *KeySetImpl) JWKS() JSONWebKeySet {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range ks.keys {
+		if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+			continue
+		}
+		jwk, err := NewJSONWebKey(key.ID, key.Method, key.PrivateKey.Public())
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySetTokenServiceImpl(t *testing.T) {
+	for _, method := range []string{"RS256", "ES256", "EdDSA"} {
+		t.Run(method, func(t *testing.T) {
+			keySet, err := service.NewKeySetImpl(service.KeySetConfig{Method: method, Overlap: time.Hour})
+			require.NoError(t, err)
+			tokenService := service.NewKeySetTokenServiceImpl(keySet, "go-core", time.Hour)
+
+			tokenString, err := tokenService.GenerateToken("session-token", nil)
+			require.NoError(t, err)
+
+			// kid header points to the current key
+			token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, keySet.Current().ID, token.Header["kid"])
+
+			claims, err := tokenService.ParseToken(tokenString)
+			require.NoError(t, err)
+			assert.Equal(t, "session-token", claims["ses"])
+
+			// the published JWK verifies the token
+			jwks := keySet.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			pub, err := jwks.Keys[0].PublicKey()
+			require.NoError(t, err)
+			_, err = jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return pub, nil })
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestKeySetImpl_Rotate(t *testing.T) {
+	t.Run("Overlap Keeps Old Key", func(t *testing.T) {
+		keySet, err := service.NewKeySetImpl(service.KeySetConfig{Method: "ES256", Overlap: time.Hour})
+		require.NoError(t, err)
+		tokenService := service.NewKeySetTokenServiceImpl(keySet, "", time.Hour)
+
+		oldToken, err := tokenService.GenerateToken("old", nil)
+		require.NoError(t, err)
+		oldKey := keySet.Current().ID
+
+		require.NoError(t, keySet.Rotate())
+		assert.NotEqual(t, oldKey, keySet.Current().ID)
+		assert.Len(t, keySet.JWKS().Keys, 2)
+
+		_, err = tokenService.ParseToken(oldToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("No Overlap Drops Old Key", func(t *testing.T) {
+		keySet, err := service.NewKeySetImpl(service.KeySetConfig{Method: "ES256"})
+		require.NoError(t, err)
+		tokenService := service.NewKeySetTokenServiceImpl(keySet, "", time.Hour)
+
+		oldToken, err := tokenService.GenerateToken("old", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, keySet.Rotate())
+		assert.Len(t, keySet.JWKS().Keys, 1)
+
+		_, err = tokenService.ParseToken(oldToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("Scheduled Rotation Requires Overlap", func(t *testing.T) {
+		_, err := service.NewKeySetImpl(service.KeySetConfig{Method: "ES256", RotationInterval: time.Hour})
+		assert.Error(t, err)
+	})
+
+	t.Run("Loaded Keys Keep Their Expiry", func(t *testing.T) {
+		retired, err := service.GenerateSigningKey("ES256")
+		require.NoError(t, err)
+		expiresAt := time.Now().Add(time.Minute)
+		retired.ExpiresAt = &expiresAt
+
+		expired, err := service.GenerateSigningKey("ES256")
+		require.NoError(t, err)
+		expiredAt := time.Now().Add(-time.Minute)
+		expired.ExpiresAt = &expiredAt
+
+		current, err := service.GenerateSigningKey("ES256")
+		require.NoError(t, err)
+
+		keySet, err := service.NewKeySetImpl(service.KeySetConfig{Method: "ES256", Overlap: time.Hour}, *current, *retired, *expired)
+		require.NoError(t, err)
+
+		assert.Equal(t, current.ID, keySet.Current().ID)
+		assert.Nil(t, keySet.Current().ExpiresAt)
+		assert.NotNil(t, keySet.PublicKey(retired.ID))
+		assert.Nil(t, keySet.PublicKey(expired.ID))
+		assert.Len(t, keySet.JWKS().Keys, 2)
+	})
+
+	t.Run("Unsupported Method", func(t *testing.T) {
+		_, err := service.NewKeySetImpl(service.KeySetConfig{Method: "HS256"})
+		assert.Error(t, err)
+	})
+}
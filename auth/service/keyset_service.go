@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"time"
+)
+
+// SigningKey is an asymmetric key of a KeySet, identified in tokens by the "kid" header
+type SigningKey struct {
+	ID         string
+	Method     string
+	PrivateKey crypto.Signer
+	CreatedAt  time.Time
+
+	// ExpiresAt is set when the key is rotated out, it keeps verifying tokens until then
+	ExpiresAt *time.Time
+}
+
+type KeySetConfig struct {
+	// Method is the signing method: RS256, RS384, RS512, ES256, ES384, ES512 or EdDSA
+	Method string
+
+	// RotationInterval is how often a new signing key is generated, 0 disables scheduled rotation
+	RotationInterval time.Duration
+
+	// Overlap is how long a rotated out key keeps verifying tokens, set it to at least the token lifetime.
+	// It is required with scheduled rotation, which would otherwise invalidate every token it signed.
+	Overlap time.Duration
+}
+
+type KeySet interface {
+	// Current returns the key signing new tokens
+	Current() *SigningKey
+
+	// PublicKey returns the public key verifying tokens with the given key ID, nil if unknown or expired
+	PublicKey(kid string) interface{}
+
+	// AddKey adds a key, e.g. loaded from storage, and makes it the signing key.
+	// A key with an ExpiresAt was already rotated out, it keeps that expiry and only verifies tokens.
+	AddKey(key SigningKey)
+
+	// Rotate generates a new signing key, the previous one keeps verifying tokens for the overlap window
+	Rotate() error
+
+	// StartRotation rotates the signing key every RotationInterval until ctx is done
+	StartRotation(ctx context.Context)
+
+	// JWKS returns the public keys of the set, for the /.well-known/jwks.json endpoint
+	JWKS() JSONWebKeySet
+}
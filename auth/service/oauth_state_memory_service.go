@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OAuthStateStoreMemoryImpl is an in-process OAuthStateStore, suitable for single instance deployments.
+type OAuthStateStoreMemoryImpl struct {
+	mu     sync.Mutex
+	states map[string]OAuthState
+}
+
+func NewOAuthStateStoreMemoryImpl() OAuthStateStore {
+	return &OAuthStateStoreMemoryImpl{
+		states: make(map[string]OAuthState),
+	}
+}
+
+func (s *OAuthStateStoreMemoryImpl) Save(ctx context.Context, state string, data OAuthState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// drop expired states so abandoned logins do not pile up
+	now := time.Now()
+	for k, v := range s.states {
+		if now.After(v.ExpiresAt) {
+			delete(s.states, k)
+		}
+	}
+
+	s.states[state] = data
+	return nil
+}
+
+func (s *OAuthStateStoreMemoryImpl) Take(ctx context.Context, state string) (*OAuthState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.states[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.states, state)
+
+	if time.Now().After(data.ExpiresAt) {
+		return nil, nil
+	}
+	return &data, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type OAuthStateStoreRedisImpl struct {
+	rdb *redis.Client
+}
+
+func NewOAuthStateStoreRedisImpl(rdb *redis.Client) OAuthStateStore {
+	return &OAuthStateStoreRedisImpl{
+		rdb: rdb,
+	}
+}
+
+func (s *OAuthStateStoreRedisImpl) key(state string) string {
+	return fmt.Sprintf("auth:oauth:state:%s", state)
+}
+
+func (s *OAuthStateStoreRedisImpl) Save(ctx context.Context, state string, data OAuthState) error {
+	val, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.rdb.Set(ctx, s.key(state), val, time.Until(data.ExpiresAt)).Err()
+}
+
+func (s *OAuthStateStoreRedisImpl) Take(ctx context.Context, state string) (*OAuthState, error) {
+	val, err := s.rdb.GetDel(ctx, s.key(state)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var data OAuthState
+	if err := json.Unmarshal([]byte(val), &data); err != nil {
+		return nil, err
+	}
+	if time.Now().After(data.ExpiresAt) {
+		return nil, nil
+	}
+	return &data, nil
+}
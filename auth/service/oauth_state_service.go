@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// OAuthState is the server side state of a pending authorization, keyed by the "state" parameter.
+type OAuthState struct {
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	AppID        int
+	ExpiresAt    time.Time
+}
+
+type OAuthStateStore interface {
+	// Save stores the state until it expires
+	Save(ctx context.Context, state string, data OAuthState) error
+
+	// Take returns and removes the state, nil when unknown or expired, so a state is only used once
+	Take(ctx context.Context, state string) (*OAuthState, error)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type OIDCProviderImpl struct {
+	config     OAuthProviderConfig
+	httpClient *http.Client
+	jwks       *JWKSClient
+}
+
+func NewOIDCProviderImpl(config OAuthProviderConfig, httpClient *http.Client) OIDCProvider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCProviderImpl{
+		config:     config,
+		httpClient: httpClient,
+		jwks:       NewJWKSClient(config.JWKSURL, httpClient, time.Hour),
+	}
+}
+
+// CodeChallengeS256 derives the PKCE code challenge from the code verifier.
+func CodeChallengeS256(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (p *OIDCProviderImpl) Name() string {
+	return p.config.Name
+}
+
+func (p *OIDCProviderImpl) AuthCodeURL(state, codeChallenge, nonce string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.config.ClientID)
+	q.Set("redirect_uri", p.config.RedirectURL)
+	q.Set("scope", strings.Join(p.config.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	if p.config.ResponseMode != "" {
+		q.Set("response_mode", p.config.ResponseMode)
+	}
+
+	sep := "?"
+	if strings.Contains(p.config.AuthURL, "?") {
+		sep = "&"
+	}
+	return p.config.AuthURL + sep + q.Encode()
+}
+
+func (p *OIDCProviderImpl) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("exchange code: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || out.Error != "" {
+		return "", fmt.Errorf("exchange code: %s %s", out.Error, out.ErrorDescription)
+	}
+	if out.IDToken == "" {
+		return "", errors.New("exchange code: no id_token in response")
+	}
+
+	return out.IDToken, nil
+}
+
+func (p *OIDCProviderImpl) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDTokenClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.jwks.Key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	iss, _ := claims["iss"].(string)
+	if !containsString(p.config.Issuers, iss) {
+		return nil, fmt.Errorf("invalid issuer %q", iss)
+	}
+
+	if got, _ := claims["nonce"].(string); nonce == "" || got != nonce {
+		return nil, errors.New("invalid nonce")
+	}
+
+	out := &IDTokenClaims{}
+	out.Subject, _ = claims["sub"].(string)
+	out.Email, _ = claims["email"].(string)
+	out.Name, _ = claims["name"].(string)
+
+	// Apple sends email_verified as a string
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		out.EmailVerified = v
+	case string:
+		out.EmailVerified = v == "true"
+	}
+
+	if out.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+
+	return out, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package service_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIssuer is a local stand-in for an OpenID Connect provider
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(service.JSONWebKeySet{Keys: []service.JSONWebKey{{
+			Kty: "RSA",
+			Kid: "test-key",
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("code") != "good-code" || r.Form.Get("code_verifier") != "verifier" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": issuer.sign(t, issuer.claims)})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *testIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(i.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (i *testIssuer) config() service.OAuthProviderConfig {
+	return service.OAuthProviderConfig{
+		Name:        "test",
+		ClientID:    "client-id",
+		RedirectURL: "https://app.example.com/callback",
+		AuthURL:     i.server.URL + "/authorize",
+		TokenURL:    i.server.URL + "/token",
+		JWKSURL:     i.server.URL + "/jwks",
+		Issuers:     []string{i.server.URL},
+		Scopes:      []string{"openid", "email"},
+	}
+}
+
+func (i *testIssuer) validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            i.server.URL,
+		"aud":            "client-id",
+		"sub":            "user-123",
+		"email":          "user@example.com",
+		"email_verified": "true",
+		"nonce":          "nonce",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestOIDCProviderImpl(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := service.NewOIDCProviderImpl(issuer.config(), nil)
+	ctx := context.Background()
+
+	t.Run("Auth Code URL", func(t *testing.T) {
+		authURL, err := url.Parse(provider.AuthCodeURL("state", service.CodeChallengeS256("verifier"), "nonce"))
+		require.NoError(t, err)
+
+		q := authURL.Query()
+		assert.Equal(t, "code", q.Get("response_type"))
+		assert.Equal(t, "client-id", q.Get("client_id"))
+		assert.Equal(t, "state", q.Get("state"))
+		assert.Equal(t, "S256", q.Get("code_challenge_method"))
+		assert.Equal(t, service.CodeChallengeS256("verifier"), q.Get("code_challenge"))
+	})
+
+	t.Run("Exchange And Verify", func(t *testing.T) {
+		issuer.claims = issuer.validClaims()
+
+		idToken, err := provider.Exchange(ctx, "good-code", "verifier")
+		require.NoError(t, err)
+
+		claims, err := provider.VerifyIDToken(ctx, idToken, "nonce")
+		require.NoError(t, err)
+		assert.Equal(t, "user-123", claims.Subject)
+		assert.Equal(t, "user@example.com", claims.Email)
+		assert.True(t, claims.EmailVerified)
+	})
+
+	t.Run("Exchange Wrong Verifier", func(t *testing.T) {
+		_, err := provider.Exchange(ctx, "good-code", "other")
+		assert.Error(t, err)
+	})
+
+	t.Run("Reject Invalid Tokens", func(t *testing.T) {
+		cases := map[string]func(jwt.MapClaims){
+			"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other-client" },
+			"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+			"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+			"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "other" },
+		}
+		for name, mutate := range cases {
+			claims := issuer.validClaims()
+			mutate(claims)
+
+			_, err := provider.VerifyIDToken(ctx, issuer.sign(t, claims), "nonce")
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("Reject Missing Nonce", func(t *testing.T) {
+		claims := issuer.validClaims()
+		delete(claims, "nonce")
+
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, claims), "")
+		assert.Error(t, err)
+	})
+
+	t.Run("Reject Foreign Signature", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, issuer.validClaims())
+		token.Header["kid"] = "test-key"
+		signed, err := token.SignedString(other)
+		require.NoError(t, err)
+
+		_, err = provider.VerifyIDToken(ctx, signed, "nonce")
+		assert.Error(t, err)
+	})
+}
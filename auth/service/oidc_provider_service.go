@@ -0,0 +1,77 @@
+package service
+
+import "context"
+
+// OAuthProviderConfig configures an OpenID Connect provider using the authorization code flow with PKCE.
+type OAuthProviderConfig struct {
+	// Name identifies the provider in routes and linked identities, e.g. "google"
+	Name         string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+	JWKSURL      string
+
+	// Issuers are the accepted values of the ID token "iss" claim
+	Issuers []string
+	Scopes  []string
+
+	// ResponseMode is sent as response_mode when set, Apple requires "form_post" when asking for email
+	ResponseMode string
+}
+
+// GoogleProviderConfig returns the config of Google's OpenID Connect provider.
+func GoogleProviderConfig(clientID, clientSecret, redirectURL string) OAuthProviderConfig {
+	return OAuthProviderConfig{
+		Name:         "google",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:     "https://oauth2.googleapis.com/token",
+		JWKSURL:      "https://www.googleapis.com/oauth2/v3/certs",
+		Issuers:      []string{"https://accounts.google.com", "accounts.google.com"},
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+// AppleProviderConfig returns the config of Sign in with Apple.
+// clientSecret is the ES256 client secret JWT generated from the Apple private key.
+func AppleProviderConfig(clientID, clientSecret, redirectURL string) OAuthProviderConfig {
+	return OAuthProviderConfig{
+		Name:         "apple",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		AuthURL:      "https://appleid.apple.com/auth/authorize",
+		TokenURL:     "https://appleid.apple.com/auth/token",
+		JWKSURL:      "https://appleid.apple.com/auth/keys",
+		Issuers:      []string{"https://appleid.apple.com"},
+		Scopes:       []string{"openid", "email", "name"},
+		ResponseMode: "form_post",
+	}
+}
+
+// IDTokenClaims are the verified claims of an ID token identifying the external user.
+type IDTokenClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type OIDCProvider interface {
+	// Name returns the provider name
+	Name() string
+
+	// AuthCodeURL returns the URL redirecting the user to the provider's consent page
+	AuthCodeURL(state, codeChallenge, nonce string) string
+
+	// Exchange exchanges the authorization code for the ID token
+	Exchange(ctx context.Context, code, codeVerifier string) (string, error)
+
+	// VerifyIDToken verifies the ID token signature against the provider's JWKS, its issuer, audience, expiry and nonce.
+	// The nonce is required, it is the one the server issued for the login.
+	VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDTokenClaims, error)
+}
@@ -35,3 +35,30 @@ func (s *OtpSenderServiceImpl) Send(channel, to, pin_code string) error {
 
 	return nil
 }
+
+// SendMagicLink sends the passwordless login link by email with the magic_link_notification template.
+func (s *OtpSenderServiceImpl) SendMagicLink(to, link string) error {
+	if s.EmailService == nil {
+		return nil
+	}
+
+	return s.EmailService.SendWithTemplate(to, "magic_link_notification", map[string]interface{}{
+		"link": link,
+	})
+}
+
+// NotifyIdentifierChanged notifies the old email or phone number with the identifier_changed_notification template.
+func (s *OtpSenderServiceImpl) NotifyIdentifierChanged(channel, to, newIdentifier string) error {
+	data := map[string]interface{}{
+		"channel":        channel,
+		"new_identifier": newIdentifier,
+	}
+
+	if channel == "email" && s.EmailService != nil {
+		return s.EmailService.SendWithTemplate(to, "identifier_changed_notification", data)
+	} else if channel == "phone" && s.PhoneService != nil {
+		return s.PhoneService.SendWithTemplate(to, "identifier_changed_notification", data)
+	}
+
+	return nil
+}
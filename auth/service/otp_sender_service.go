@@ -4,3 +4,13 @@ type OtpSenderService interface {
 	// Send sends an OTP message to the specified recipient.
 	Send(channel, to, pin_code string) error
 }
+
+type IdentifierChangeNotifier interface {
+	// NotifyIdentifierChanged tells the old email or phone number that it was replaced on the account.
+	NotifyIdentifierChanged(channel, to, newIdentifier string) error
+}
+
+type MagicLinkSenderService interface {
+	// SendMagicLink sends a one-time login link to the specified email address.
+	SendMagicLink(to, link string) error
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+)
+
+type memorySessionItem struct {
+	session   entity.UserSession
+	expiresAt time.Time
+}
+
+// SessionStoreMemoryImpl is an in-process SessionStore, suitable for
+// single instance deployments where Redis is not available.
+type SessionStoreMemoryImpl struct {
+	mu     sync.RWMutex
+	ttl    time.Duration
+	tokens map[string]memorySessionItem
+	users  map[uint]map[string]struct{}
+
+	// revoked tokens and users are not cached again for a ttl, so a lookup that read the session before
+	// the revocation cannot put it back
+	revokedTokens map[string]time.Time
+	revokedUsers  map[uint]time.Time
+
+	lastSweep time.Time
+}
+
+func NewSessionStoreMemoryImpl(ttl time.Duration) SessionStore {
+	return &SessionStoreMemoryImpl{
+		ttl:           ttl,
+		tokens:        make(map[string]memorySessionItem),
+		users:         make(map[uint]map[string]struct{}),
+		revokedTokens: make(map[string]time.Time),
+		revokedUsers:  make(map[uint]time.Time),
+	}
+}
+
+// Get returns the cached session for the given token, or nil on cache miss.
+func (s *SessionStoreMemoryImpl) Get(ctx context.Context, token string) (*entity.UserSession, error) {
+	s.mu.RLock()
+	item, ok := s.tokens[token]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+	if time.Now().After(item.expiresAt) {
+		s.mu.Lock()
+		s.remove(token)
+		s.mu.Unlock()
+		return nil, nil
+	}
+
+	session := item.session
+	return &session, nil
+}
+
+// Set caches the session and indexes its token by user ID. A session revoked within the last ttl is not cached.
+func (s *SessionStoreMemoryImpl) Set(ctx context.Context, session *entity.UserSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if now.Before(s.revokedTokens[session.Tokens]) || now.Before(s.revokedUsers[session.UserID]) {
+		return nil
+	}
+
+	s.tokens[session.Tokens] = memorySessionItem{
+		session:   *session,
+		expiresAt: now.Add(s.ttl),
+	}
+	if s.users[session.UserID] == nil {
+		s.users[session.UserID] = make(map[string]struct{})
+	}
+	s.users[session.UserID][session.Tokens] = struct{}{}
+	return nil
+}
+
+// Delete removes the cached session for the given token and keeps it from being cached for the ttl.
+func (s *SessionStoreMemoryImpl) Delete(ctx context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(token)
+	s.revokedTokens[token] = time.Now().Add(s.ttl)
+	return nil
+}
+
+// DeleteByUserID removes every cached session belonging to the given user and keeps them from being cached for the ttl.
+func (s *SessionStoreMemoryImpl) DeleteByUserID(ctx context.Context, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for token := range s.users[userID] {
+		delete(s.tokens, token)
+	}
+	delete(s.users, userID)
+	s.revokedUsers[userID] = time.Now().Add(s.ttl)
+	return nil
+}
+
+// Len returns the number of cached sessions, expired ones included until they are swept
+func (s *SessionStoreMemoryImpl) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.tokens)
+}
+
+// remove drops the token and its user index, the caller holds the lock
+func (s *SessionStoreMemoryImpl) remove(token string) {
+	if item, ok := s.tokens[token]; ok {
+		delete(s.users[item.session.UserID], token)
+		if len(s.users[item.session.UserID]) == 0 {
+			delete(s.users, item.session.UserID)
+		}
+	}
+	delete(s.tokens, token)
+}
+
+// sweep drops the expired sessions and revocations at most once per ttl, so tokens that are never read
+// again do not pile up. The caller holds the lock.
+func (s *SessionStoreMemoryImpl) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+	s.lastSweep = now
+
+	for token, item := range s.tokens {
+		if now.After(item.expiresAt) {
+			s.remove(token)
+		}
+	}
+	for token, until := range s.revokedTokens {
+		if !now.Before(until) {
+			delete(s.revokedTokens, token)
+		}
+	}
+	for userID, until := range s.revokedUsers {
+		if !now.Before(until) {
+			delete(s.revokedUsers, userID)
+		}
+	}
+}
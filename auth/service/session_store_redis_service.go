@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+type SessionStoreRedisImpl struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewSessionStoreRedisImpl(rdb *redis.Client, ttl time.Duration) SessionStore {
+	return &SessionStoreRedisImpl{
+		rdb: rdb,
+		ttl: ttl,
+	}
+}
+
+func (s *SessionStoreRedisImpl) tokenKey(token string) string {
+	return fmt.Sprintf("auth:session:token:%s", token)
+}
+
+func (s *SessionStoreRedisImpl) userKey(userID uint) string {
+	return fmt.Sprintf("auth:session:user:%d", userID)
+}
+
+func (s *SessionStoreRedisImpl) revokedTokenKey(token string) string {
+	return fmt.Sprintf("auth:session:revoked:token:%s", token)
+}
+
+func (s *SessionStoreRedisImpl) revokedUserKey(userID uint) string {
+	return fmt.Sprintf("auth:session:revoked:user:%d", userID)
+}
+
+// setSessionScript caches the session unless its token or user was revoked, in one step so a concurrent
+// Delete cannot land between the check and the write
+var setSessionScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[3]) == 1 or redis.call("EXISTS", KEYS[4]) == 1 then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[3])
+redis.call("SADD", KEYS[2], ARGV[2])
+redis.call("PEXPIRE", KEYS[2], ARGV[3])
+return 1
+`)
+
+// Get returns the cached session for the given token, or nil on cache miss.
+func (s *SessionStoreRedisImpl) Get(ctx context.Context, token string) (*entity.UserSession, error) {
+	val, err := s.rdb.Get(ctx, s.tokenKey(token)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var session entity.UserSession
+	if err := json.Unmarshal([]byte(val), &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Set caches the session and indexes its token by user ID,
+// so all sessions of a user can be invalidated at once. A session revoked within the last ttl is not cached.
+func (s *SessionStoreRedisImpl) Set(ctx context.Context, session *entity.UserSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{
+		s.tokenKey(session.Tokens),
+		s.userKey(session.UserID),
+		s.revokedTokenKey(session.Tokens),
+		s.revokedUserKey(session.UserID),
+	}
+	return setSessionScript.Run(ctx, s.rdb, keys, data, session.Tokens, s.ttl.Milliseconds()).Err()
+}
+
+// Delete removes the cached session for the given token and keeps it from being cached for the ttl.
+func (s *SessionStoreRedisImpl) Delete(ctx context.Context, token string) error {
+	session, err := s.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	pipe.Del(ctx, s.tokenKey(token))
+	pipe.Set(ctx, s.revokedTokenKey(token), 1, s.ttl)
+	if session != nil {
+		pipe.SRem(ctx, s.userKey(session.UserID), token)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// DeleteByUserID removes every cached session belonging to the given user and keeps them from being cached for the ttl.
+func (s *SessionStoreRedisImpl) DeleteByUserID(ctx context.Context, userID uint) error {
+	userKey := s.userKey(userID)
+	tokens, err := s.rdb.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(tokens)+1)
+	for _, token := range tokens {
+		keys = append(keys, s.tokenKey(token))
+	}
+	keys = append(keys, userKey)
+
+	pipe := s.rdb.TxPipeline()
+	pipe.Set(ctx, s.revokedUserKey(userID), 1, s.ttl)
+	pipe.Del(ctx, keys...)
+	_, err = pipe.Exec(ctx)
+	return err
+}
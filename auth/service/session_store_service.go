@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+)
+
+type SessionStore interface {
+	// Get returns the cached session for the given token, or nil if it is not cached.
+	Get(ctx context.Context, token string) (*entity.UserSession, error)
+
+	// Set caches the session under its token, unless the token or its user was deleted within the store ttl.
+	// A lookup that read the session before a revocation cannot cache it again.
+	Set(ctx context.Context, session *entity.UserSession) error
+
+	// Delete removes the cached session for the given token and keeps it from being cached for the store ttl.
+	Delete(ctx context.Context, token string) error
+
+	// DeleteByUserID removes every cached session belonging to the given user and keeps them from being
+	// cached for the store ttl.
+	DeleteByUserID(ctx context.Context, userID uint) error
+}
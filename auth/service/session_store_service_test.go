@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSession(userID uint, token string) *entity.UserSession {
+	return &entity.UserSession{UserID: userID, Tokens: token}
+}
+
+// testSessionStore covers the behaviour every SessionStore shares
+func testSessionStore(t *testing.T, store service.SessionStore, ttl time.Duration) {
+	ctx := context.Background()
+
+	t.Run("expires after ttl", func(t *testing.T) {
+		require.NoError(t, store.Set(ctx, newSession(1, "ttl-token")))
+
+		cached, err := store.Get(ctx, "ttl-token")
+		require.NoError(t, err)
+		require.NotNil(t, cached)
+		assert.Equal(t, uint(1), cached.UserID)
+
+		time.Sleep(ttl + 50*time.Millisecond)
+		cached, err = store.Get(ctx, "ttl-token")
+		require.NoError(t, err)
+		assert.Nil(t, cached)
+	})
+
+	t.Run("delete invalidates the token", func(t *testing.T) {
+		require.NoError(t, store.Set(ctx, newSession(2, "deleted-token")))
+		require.NoError(t, store.Set(ctx, newSession(2, "kept-token")))
+		require.NoError(t, store.Delete(ctx, "deleted-token"))
+
+		cached, err := store.Get(ctx, "deleted-token")
+		require.NoError(t, err)
+		assert.Nil(t, cached)
+
+		cached, err = store.Get(ctx, "kept-token")
+		require.NoError(t, err)
+		assert.NotNil(t, cached)
+	})
+
+	t.Run("delete by user invalidates every token", func(t *testing.T) {
+		require.NoError(t, store.Set(ctx, newSession(3, "user-token-a")))
+		require.NoError(t, store.Set(ctx, newSession(3, "user-token-b")))
+		require.NoError(t, store.Set(ctx, newSession(4, "other-user-token")))
+		require.NoError(t, store.DeleteByUserID(ctx, 3))
+
+		for _, token := range []string{"user-token-a", "user-token-b"} {
+			cached, err := store.Get(ctx, token)
+			require.NoError(t, err)
+			assert.Nil(t, cached, token)
+		}
+		cached, err := store.Get(ctx, "other-user-token")
+		require.NoError(t, err)
+		assert.NotNil(t, cached)
+	})
+
+	t.Run("revoked session is not cached again", func(t *testing.T) {
+		// a lookup read the session before the revocation and caches it afterwards
+		require.NoError(t, store.Delete(ctx, "stale-token"))
+		require.NoError(t, store.Set(ctx, newSession(5, "stale-token")))
+
+		cached, err := store.Get(ctx, "stale-token")
+		require.NoError(t, err)
+		assert.Nil(t, cached)
+
+		require.NoError(t, store.DeleteByUserID(ctx, 6))
+		require.NoError(t, store.Set(ctx, newSession(6, "stale-user-token")))
+
+		cached, err = store.Get(ctx, "stale-user-token")
+		require.NoError(t, err)
+		assert.Nil(t, cached)
+	})
+}
+
+func TestSessionStoreMemoryImpl(t *testing.T) {
+	ttl := 100 * time.Millisecond
+	testSessionStore(t, service.NewSessionStoreMemoryImpl(ttl), ttl)
+
+	t.Run("sweeps tokens that are never read again", func(t *testing.T) {
+		ctx := context.Background()
+		store := service.NewSessionStoreMemoryImpl(ttl).(*service.SessionStoreMemoryImpl)
+
+		require.NoError(t, store.Set(ctx, newSession(1, "abandoned-a")))
+		require.NoError(t, store.Set(ctx, newSession(1, "abandoned-b")))
+		assert.Equal(t, 2, store.Len())
+
+		time.Sleep(ttl + 50*time.Millisecond)
+		require.NoError(t, store.Set(ctx, newSession(2, "fresh")))
+		assert.Equal(t, 1, store.Len())
+	})
+
+	t.Run("revocation ends after ttl", func(t *testing.T) {
+		ctx := context.Background()
+		store := service.NewSessionStoreMemoryImpl(ttl)
+
+		require.NoError(t, store.DeleteByUserID(ctx, 1))
+		time.Sleep(ttl + 50*time.Millisecond)
+		require.NoError(t, store.Set(ctx, newSession(1, "new-login")))
+
+		cached, err := store.Get(ctx, "new-login")
+		require.NoError(t, err)
+		assert.NotNil(t, cached)
+	})
+}
+
+// TestSessionStoreRedisImpl runs against a real server, set REDIS_ADDR to enable it
+func TestSessionStoreRedisImpl(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { rdb.Close() })
+	require.NoError(t, rdb.Ping(context.Background()).Err())
+
+	ttl := time.Second
+	testSessionStore(t, service.NewSessionStoreRedisImpl(rdb, ttl), ttl)
+}
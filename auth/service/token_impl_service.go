@@ -0,0 +1,81 @@
+package service
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	pkg_auth "github.com/budimanlai/go-pkg/middleware/auth"
+)
+
+// reservedClaims cannot be overridden by extra claims
+var reservedClaims = []string{"ses", "iss", "iat", "exp"}
+
+type TokenServiceImpl struct {
+	config pkg_auth.JWTConfig
+}
+
+// NewTokenServiceImpl creates a TokenService signing with the same config as the JWT middleware,
+// so the issued tokens are accepted by the private middleware
+func NewTokenServiceImpl(config pkg_auth.JWTConfig) TokenService {
+	return &TokenServiceImpl{
+		config: config,
+	}
+}
+
+// buildClaims merges the extra claims with the reserved ones
+func buildClaims(sessionToken, issuer string, expiration time.Duration, claims map[string]interface{}) jwt.MapClaims {
+	now := time.Now()
+
+	mapClaims := jwt.MapClaims{}
+	for key, value := range claims {
+		mapClaims[key] = value
+	}
+	for _, key := range reservedClaims {
+		delete(mapClaims, key)
+	}
+
+	mapClaims["ses"] = sessionToken
+	mapClaims["iat"] = now.Unix()
+	if issuer != "" {
+		mapClaims["iss"] = issuer
+	}
+	if expiration > 0 {
+		mapClaims["exp"] = now.Add(expiration).Unix()
+	}
+	return mapClaims
+}
+
+func parseToken(tokenString, issuer string, methods []string, keyFunc jwt.Keyfunc) (jwt.MapClaims, error) {
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods)}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, keyFunc, opts...); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// GenerateToken signs a JWT carrying the session token in the "ses" claim, plus the given extra claims.
+func (s *TokenServiceImpl) GenerateToken(sessionToken string, claims map[string]interface{}) (string, error) {
+	mapClaims := buildClaims(sessionToken, s.config.Issuer, s.config.ExpirationTime, claims)
+	return jwt.NewWithClaims(s.method(), mapClaims).SignedString([]byte(s.config.SecretKey))
+}
+
+// ParseToken verifies the token signature, issuer and expiry and returns its claims.
+func (s *TokenServiceImpl) ParseToken(tokenString string) (jwt.MapClaims, error) {
+	return parseToken(tokenString, s.config.Issuer, []string{s.method().Alg()}, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.config.SecretKey), nil
+	})
+}
+
+func (s *TokenServiceImpl) method() jwt.SigningMethod {
+	method := jwt.GetSigningMethod(s.config.SigningMethod)
+	if method == nil {
+		return jwt.SigningMethodHS256
+	}
+	return method
+}
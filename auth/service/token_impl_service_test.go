@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	pkg_auth "github.com/budimanlai/go-pkg/middleware/auth"
+)
+
+func TestTokenServiceImpl_GenerateToken(t *testing.T) {
+	config := pkg_auth.JWTConfig{
+		SecretKey:      "test-secret",
+		Issuer:         "go-core",
+		ExpirationTime: time.Hour,
+		SigningMethod:  "HS256",
+	}
+	tokenService := service.NewTokenServiceImpl(config)
+
+	parse := func(t *testing.T, tokenString string) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(config.SecretKey), nil
+		})
+		assert.NoError(t, err)
+		return claims
+	}
+
+	t.Run("Session And Extra Claims", func(t *testing.T) {
+		tokenString, err := tokenService.GenerateToken("session-token", map[string]interface{}{
+			"app":   2,
+			"roles": []string{"admin"},
+		})
+		assert.NoError(t, err)
+
+		claims := parse(t, tokenString)
+		assert.Equal(t, "session-token", claims["ses"])
+		assert.Equal(t, "go-core", claims["iss"])
+		assert.EqualValues(t, 2, claims["app"])
+		assert.Equal(t, []interface{}{"admin"}, claims["roles"])
+		assert.NotNil(t, claims["exp"])
+	})
+
+	t.Run("Reserved Claims Not Overridden", func(t *testing.T) {
+		tokenString, err := tokenService.GenerateToken("session-token", map[string]interface{}{
+			"ses": "forged",
+			"iss": "forged",
+		})
+		assert.NoError(t, err)
+
+		claims := parse(t, tokenString)
+		assert.Equal(t, "session-token", claims["ses"])
+		assert.Equal(t, "go-core", claims["iss"])
+	})
+
+	t.Run("Parse Token", func(t *testing.T) {
+		tokenString, err := tokenService.GenerateToken("session-token", nil)
+		assert.NoError(t, err)
+
+		claims, err := tokenService.ParseToken(tokenString)
+		assert.NoError(t, err)
+		assert.Equal(t, "session-token", claims["ses"])
+
+		_, err = service.NewTokenServiceImpl(pkg_auth.JWTConfig{SecretKey: "other", Issuer: "go-core"}).ParseToken(tokenString)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type KeySetTokenServiceImpl struct {
+	keySet     KeySet
+	issuer     string
+	expiration time.Duration
+}
+
+// NewKeySetTokenServiceImpl creates a TokenService signing with the current key of the KeySet,
+// so other services verify the tokens with the public keys of the JWKS endpoint instead of a shared secret
+func NewKeySetTokenServiceImpl(keySet KeySet, issuer string, expiration time.Duration) TokenService {
+	return &KeySetTokenServiceImpl{
+		keySet:     keySet,
+		issuer:     issuer,
+		expiration: expiration,
+	}
+}
+
+// GenerateToken signs a JWT with the current key, its ID in the "kid" header.
+func (s *KeySetTokenServiceImpl) GenerateToken(sessionToken string, claims map[string]interface{}) (string, error) {
+	key := s.keySet.Current()
+	if key == nil {
+		return "", errors.New("no signing key")
+	}
+
+	method := jwt.GetSigningMethod(key.Method)
+	if method == nil {
+		return "", errors.New("unsupported signing method")
+	}
+
+	token := jwt.NewWithClaims(method, buildClaims(sessionToken, s.issuer, s.expiration, claims))
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// ParseToken verifies the token with the key of its "kid" header, rotated out keys are accepted during their overlap window.
+func (s *KeySetTokenServiceImpl) ParseToken(tokenString string) (jwt.MapClaims, error) {
+	methods := []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}
+	return parseToken(tokenString, s.issuer, methods, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key := s.keySet.PublicKey(kid)
+		if key == nil {
+			return nil, errors.New("unknown signing key")
+		}
+		return key, nil
+	})
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type TokenService interface {
+	// GenerateToken signs a JWT carrying the session token in the "ses" claim, plus the given extra claims.
+	GenerateToken(sessionToken string, claims map[string]interface{}) (string, error)
+
+	// ParseToken verifies the token signature, issuer and expiry and returns its claims.
+	ParseToken(tokenString string) (jwt.MapClaims, error)
+}
+
+// ClaimsProvider returns the extra claims to put in the JWT issued for the given session.
+type ClaimsProvider func(ctx context.Context, session *entity.UserSession) (map[string]interface{}, error)
+
+// AuthContextResolver enriches the AuthContext of a request, e.g. loading roles and scopes.
+type AuthContextResolver func(ctx context.Context, auth *entity.AuthContext) error
@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/domain/repository"
+	"github.com/budimanlai/go-core/auth/domain/usecase"
+	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-core/base"
+	"gorm.io/gorm"
+
+	pkg_helpers "github.com/budimanlai/go-pkg/helpers"
+)
+
+// nonceKeyPrefix keeps the nonces of IssueNonce apart from the states of AuthURL in the state store
+const nonceKeyPrefix = "nonce:"
+
+type ExternalAuthConfig struct {
+	// StateTTL is how long a started authorization stays valid, defaults to 10 minutes
+	StateTTL time.Duration
+}
+
+type ExternalAuthUsecaseImpl struct {
+	base.BaseUsecase[entity.UserIdentity]
+
+	config        ExternalAuthConfig
+	providers     map[string]service.OIDCProvider
+	stateStore    service.OAuthStateStore
+	userRepo      repository.UserRepository
+	UserSessionUC usecase.UserSessionUsecase
+}
+
+func NewExternalAuthUsecaseImpl(db *gorm.DB, repo repository.UserIdentityRepository, userRepo repository.UserRepository,
+	userSessionUC usecase.UserSessionUsecase, stateStore service.OAuthStateStore, config ExternalAuthConfig) usecase.ExternalAuthUsecase {
+	if config.StateTTL <= 0 {
+		config.StateTTL = 10 * time.Minute
+	}
+	if stateStore == nil {
+		stateStore = service.NewOAuthStateStoreMemoryImpl()
+	}
+	return &ExternalAuthUsecaseImpl{
+		BaseUsecase:   base.NewBaseUsecase(repo, db),
+		config:        config,
+		providers:     make(map[string]service.OIDCProvider),
+		stateStore:    stateStore,
+		userRepo:      userRepo,
+		UserSessionUC: userSessionUC,
+	}
+}
+
+func (u *ExternalAuthUsecaseImpl) RegisterProvider(provider service.OIDCProvider) {
+	u.providers[provider.Name()] = provider
+}
+
+func (u *ExternalAuthUsecaseImpl) HasProvider(name string) bool {
+	_, ok := u.providers[name]
+	return ok
+}
+
+func (u *ExternalAuthUsecaseImpl) provider(name string) (service.OIDCProvider, error) {
+	p, ok := u.providers[name]
+	if !ok {
+		return nil, errors.New("unknown identity provider")
+	}
+	return p, nil
+}
+
+// AuthURL generates the state, nonce and PKCE code verifier, keeps them server side
+// and returns the provider's consent URL carrying the state and code challenge.
+func (u *ExternalAuthUsecaseImpl) AuthURL(ctx context.Context, providerName string, appID int) (string, error) {
+	p, err := u.provider(providerName)
+	if err != nil {
+		return "", err
+	}
+
+	state := pkg_helpers.GenerateRandomString(32)
+	data := service.OAuthState{
+		Provider:     providerName,
+		CodeVerifier: pkg_helpers.GenerateRandomString(64),
+		Nonce:        pkg_helpers.GenerateRandomString(32),
+		AppID:        appID,
+		ExpiresAt:    time.Now().Add(u.config.StateTTL),
+	}
+	if err := u.stateStore.Save(ctx, state, data); err != nil {
+		return "", err
+	}
+
+	return p.AuthCodeURL(state, service.CodeChallengeS256(data.CodeVerifier), data.Nonce), nil
+}
+
+// Callback validates the state, exchanges the code with the PKCE code verifier,
+// verifies the ID token and logs the linked user in.
+func (u *ExternalAuthUsecaseImpl) Callback(ctx context.Context, providerName, state, code, fromIP, userAgent string) (*dto.LoginResponse, error) {
+	p, err := u.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. the state is single use and bound to the provider it was issued for
+	data, err := u.stateStore.Take(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil || data.Provider != providerName {
+		return nil, errors.New("invalid or expired state")
+	}
+
+	// 2. exchange code for ID token
+	idToken, err := p.Exchange(ctx, code, data.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. verify ID token
+	claims, err := p.VerifyIDToken(ctx, idToken, data.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.login(ctx, providerName, claims, data.AppID, fromIP, userAgent)
+}
+
+// IssueNonce keeps a nonce server side like AuthURL does, the native SDK puts it in the ID token
+// and LoginWithIDToken or Link takes it back once.
+func (u *ExternalAuthUsecaseImpl) IssueNonce(ctx context.Context, providerName string, appID int) (string, error) {
+	if _, err := u.provider(providerName); err != nil {
+		return "", err
+	}
+
+	nonce := pkg_helpers.GenerateRandomString(32)
+	data := service.OAuthState{
+		Provider:  providerName,
+		Nonce:     nonce,
+		AppID:     appID,
+		ExpiresAt: time.Now().Add(u.config.StateTTL),
+	}
+	if err := u.stateStore.Save(ctx, nonceKeyPrefix+nonce, data); err != nil {
+		return "", err
+	}
+	return nonce, nil
+}
+
+// verifyWithIssuedNonce takes the nonce issued by IssueNonce and verifies the ID token against it
+func (u *ExternalAuthUsecaseImpl) verifyWithIssuedNonce(ctx context.Context, p service.OIDCProvider, idToken, nonce string) (*service.IDTokenClaims, *service.OAuthState, error) {
+	if nonce == "" {
+		return nil, nil, errors.New("nonce is required")
+	}
+
+	data, err := u.stateStore.Take(ctx, nonceKeyPrefix+nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data == nil || data.Provider != p.Name() {
+		return nil, nil, errors.New("invalid or expired nonce")
+	}
+
+	claims, err := p.VerifyIDToken(ctx, idToken, data.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	return claims, data, nil
+}
+
+func (u *ExternalAuthUsecaseImpl) LoginWithIDToken(ctx context.Context, providerName, idToken, nonce string, appID int, fromIP, userAgent string) (*dto.LoginResponse, error) {
+	p, err := u.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, data, err := u.verifyWithIssuedNonce(ctx, p, idToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if data.AppID != appID {
+		return nil, errors.New("invalid or expired nonce")
+	}
+
+	return u.login(ctx, providerName, claims, appID, fromIP, userAgent)
+}
+
+// login finds the user linked to the external identity, linking it by verified email on first login,
+// and issues a session token for the user.
+func (u *ExternalAuthUsecaseImpl) login(ctx context.Context, providerName string, claims *service.IDTokenClaims, appID int, fromIP, userAgent string) (*dto.LoginResponse, error) {
+	var out dto.LoginResponse
+	err := u.WithTransaction(ctx, func(ctx context.Context) error {
+		// 1. find user by linked identity
+		user, err := u.findLinkedUser(ctx, providerName, claims.Subject)
+		if err != nil {
+			return err
+		}
+
+		// 2. link to an existing user by verified email
+		if user == nil {
+			if claims.Email == "" || !claims.EmailVerified {
+				return errors.New("email is not verified by the identity provider")
+			}
+
+			user, err = u.userRepo.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
+				return d.Where("email = ?", claims.Email)
+			})
+			if err != nil {
+				return err
+			}
+			if user == nil {
+				return errors.New("no account registered with this email")
+			}
+
+			err = u.Create(ctx, &entity.UserIdentity{
+				UserID:    user.ID,
+				Provider:  providerName,
+				Subject:   claims.Subject,
+				Email:     claims.Email,
+				CreatedAt: time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// 3. check if user.status is active
+		if !user.IsActive() {
+			return errors.New("user is not active")
+		}
+
+		// 4. generate user session and token
+		accessToken, err := u.UserSessionUC.GenerateToken(ctx, user.ID, appID, fromIP, userAgent)
+		if err != nil {
+			return err
+		}
+
+		out = dto.LoginResponse{
+			UserID:    user.ID,
+			Email:     user.Email,
+			Handphone: user.Handphone,
+			Fullname:  user.Fullname,
+			Token: dto.Token{
+				AccessToken: accessToken,
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (u *ExternalAuthUsecaseImpl) findLinkedUser(ctx context.Context, providerName, subject string) (*entity.User, error) {
+	identity, err := u.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
+		return d.Where("provider = ? and subject = ?", providerName, subject)
+	})
+	if err != nil || identity == nil {
+		return nil, err
+	}
+
+	return u.userRepo.FindByID(ctx, identity.UserID)
+}
+
+// Link links the identity of the ID token to a logged in user, whatever the email of the identity.
+func (u *ExternalAuthUsecaseImpl) Link(ctx context.Context, userID uint, providerName, idToken, nonce string) error {
+	p, err := u.provider(providerName)
+	if err != nil {
+		return err
+	}
+
+	claims, _, err := u.verifyWithIssuedNonce(ctx, p, idToken, nonce)
+	if err != nil {
+		return err
+	}
+
+	existing, err := u.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
+		return d.Where("(provider = ? and subject = ?) or (provider = ? and user_id = ?)",
+			providerName, claims.Subject, providerName, userID)
+	})
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.UserID == userID && existing.Subject == claims.Subject {
+			return nil
+		}
+		return errors.New("identity is already linked")
+	}
+
+	return u.Create(ctx, &entity.UserIdentity{
+		UserID:    userID,
+		Provider:  providerName,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (u *ExternalAuthUsecaseImpl) Unlink(ctx context.Context, userID uint, providerName string) error {
+	identity, err := u.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
+		return d.Where("user_id = ? and provider = ?", userID, providerName)
+	})
+	if err != nil {
+		return err
+	}
+	if identity == nil {
+		return errors.New("identity not found")
+	}
+
+	return u.Delete(ctx, identity.ID)
+}
+
+func (u *ExternalAuthUsecaseImpl) GetIdentities(ctx context.Context, userID uint) ([]entity.UserIdentity, error) {
+	result, err := u.FindAll(ctx, 1, 100, func(d *gorm.DB) *gorm.DB {
+		return d.Where("user_id = ?", userID).Order("id ASC")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
@@ -17,24 +17,35 @@ import (
 	pkg_helpers "github.com/budimanlai/go-pkg/helpers"
 )
 
+// DefaultOtpMaxVerifyAttempts is the number of wrong pin codes after which an OTP is locked
+const DefaultOtpMaxVerifyAttempts = 5
+
 type OtpConfig struct {
 	UserInitiated      bool
 	BotPhoneNumber     string
 	CommandPrefix      string
 	MaxPendingRequests int
 	ExpiredDuration    time.Duration
+
+	// MaxVerifyAttempts locks the OTP after that many wrong pin codes, defaults to DefaultOtpMaxVerifyAttempts
+	MaxVerifyAttempts int
 }
 
 type OtpUsecaseImpl struct {
 	base.BaseUsecase[entity.Otp]
 
+	repo   repository.OtpRepository
 	config OtpConfig
 	sender service.OtpSenderService
 }
 
 func NewOtpUsecaseImpl(db *gorm.DB, repo repository.OtpRepository, config OtpConfig) usecase.OtpUsecase {
+	if config.MaxVerifyAttempts <= 0 {
+		config.MaxVerifyAttempts = DefaultOtpMaxVerifyAttempts
+	}
 	return &OtpUsecaseImpl{
 		BaseUsecase: base.NewBaseUsecase(repo, db),
+		repo:        repo,
 		config:      config,
 	}
 }
@@ -162,7 +173,7 @@ func (uc *OtpUsecaseImpl) GenerateOTP(ctx context.Context, request dto.OtpReques
 	} else {
 		out.WaUrl = ""
 
-		if uc.sender != nil {
+		if uc.sender != nil && !request.SkipSend {
 			// send OTP in background job
 			err = uc.sender.Send(request.Channel, request.Identifier, pin_code)
 			if err != nil {
@@ -208,6 +219,7 @@ func (uc *OtpUsecaseImpl) Status(ctx context.Context, identifier string, trx_id
 
 // VerifyOtp verifies the OTP for the given phone number, transaction ID, and pin code.
 // It updates the status of the OTP to "verified" if the provided details are valid.
+// Wrong pin codes are counted and the OTP is locked after MaxVerifyAttempts.
 //
 // Parameters:
 //   - ctx: Context for managing request-scoped values and deadlines
@@ -218,10 +230,10 @@ func (uc *OtpUsecaseImpl) Status(ctx context.Context, identifier string, trx_id
 // Returns:
 //   - error: An error object if the operation fails, otherwise nil
 func (uc *OtpUsecaseImpl) VerifyOtp(ctx context.Context, identifier, trx_id, pin_code string) error {
-	// 1. find OTP by phone number, trx_id, pin_code, status = pending and not expired
+	// 1. find OTP by phone number, trx_id and not expired
 	otp, err := uc.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
-		return d.Where("handphone = ? and trx_id = ? and pin_code = ? and created_at >= ?",
-			identifier, trx_id, pin_code, time.Now().Add(-uc.config.ExpiredDuration))
+		return d.Where("handphone = ? and trx_id = ? and created_at >= ?",
+			identifier, trx_id, time.Now().Add(-uc.config.ExpiredDuration))
 	})
 	if err != nil {
 		return err
@@ -232,17 +244,53 @@ func (uc *OtpUsecaseImpl) VerifyOtp(ctx context.Context, identifier, trx_id, pin
 		return errors.New("Invalid OTP or expired")
 	}
 
+	// 2. reject locked OTP, then count wrong pin codes. The attempt is taken by a conditional update,
+	// so parallel guesses cannot go past MaxVerifyAttempts.
+	if otp.Attempts >= uc.config.MaxVerifyAttempts {
+		return errors.New("Too many attempts")
+	}
+	if otp.PinCode != pin_code {
+		affected, err := uc.repo.UpdateWhere(ctx, map[string]interface{}{"attempts": gorm.Expr("attempts + 1")}, func(d *gorm.DB) *gorm.DB {
+			return d.Where("id = ? and attempts < ?", otp.ID, uc.config.MaxVerifyAttempts)
+		})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return errors.New("Too many attempts")
+		}
+		return errors.New("Invalid OTP or expired")
+	}
+
 	if otp.Status == "verified" {
 		return nil
 	}
 
-	// update status to verified
-	otp.Status = "verified"
-	err = uc.Update(ctx, otp)
+	// 3. update status to verified, unless it was locked or used meanwhile
+	affected, err := uc.repo.UpdateWhere(ctx, map[string]interface{}{"status": "verified"}, func(d *gorm.DB) *gorm.DB {
+		return d.Where("id = ? and status = ? and attempts < ?", otp.ID, "pending", uc.config.MaxVerifyAttempts)
+	})
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return errors.New("Invalid OTP or expired")
+	}
+
+	return nil
+}
 
+// Consume marks the OTP with the given status as used, only the first concurrent caller succeeds
+func (uc *OtpUsecaseImpl) Consume(ctx context.Context, identifier, trx_id, status string) error {
+	affected, err := uc.repo.UpdateWhere(ctx, map[string]interface{}{"status": "used"}, func(d *gorm.DB) *gorm.DB {
+		return d.Where("handphone = ? and trx_id = ? and status = ?", identifier, trx_id, status)
+	})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Invalid OTP or expired")
+	}
 	return nil
 }
 
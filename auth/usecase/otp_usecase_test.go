@@ -0,0 +1,147 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/domain/repository"
+	"github.com/budimanlai/go-core/auth/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/utils/tests"
+)
+
+func openDryRun(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	return db
+}
+
+// matches evaluates the conditions of the scopes, written as "column op ?" joined by "and",
+// against the value of each column
+func matches(scopes []func(*gorm.DB) *gorm.DB, column func(name string) interface{}) bool {
+	db, _ := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	stmt := db.Table("t").Scopes(scopes...).Find(&[]map[string]interface{}{}).Statement
+	where, _ := stmt.Clauses["WHERE"].Expression.(clause.Where)
+
+	for _, expr := range where.Exprs {
+		expr := expr.(clause.Expr)
+		for i, cond := range strings.Split(expr.SQL, " and ") {
+			parts := strings.Fields(cond)
+			if !compare(column(parts[0]), parts[1], expr.Vars[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func compare(value interface{}, op string, arg interface{}) bool {
+	var c int
+	switch v := value.(type) {
+	case int:
+		c = v - arg.(int)
+	case string:
+		c = strings.Compare(v, arg.(string))
+	case time.Time:
+		c = v.Compare(arg.(time.Time))
+	}
+
+	switch op {
+	case "=":
+		return c == 0
+	case "<":
+		return c < 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// fakeOtpRepo keeps the OTPs in memory, the conditional updates behave like the database ones
+type fakeOtpRepo struct {
+	repository.OtpRepository
+	otps []*entity.Otp
+}
+
+func (r *fakeOtpRepo) column(otp *entity.Otp) func(string) interface{} {
+	return func(name string) interface{} {
+		return map[string]interface{}{
+			"id":         otp.ID,
+			"handphone":  otp.Handphone,
+			"trx_id":     otp.TrxID,
+			"status":     otp.Status,
+			"attempts":   otp.Attempts,
+			"created_at": otp.CreatedAt,
+		}[name]
+	}
+}
+
+func (r *fakeOtpRepo) FindOne(ctx context.Context, scopes ...func(*gorm.DB) *gorm.DB) (*entity.Otp, error) {
+	for _, otp := range r.otps {
+		if matches(scopes, r.column(otp)) {
+			out := *otp
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeOtpRepo) UpdateWhere(ctx context.Context, fields map[string]interface{}, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	var affected int64
+	for _, otp := range r.otps {
+		if !matches(scopes, r.column(otp)) {
+			continue
+		}
+		if status, ok := fields["status"]; ok {
+			otp.Status = status.(string)
+		}
+		if _, ok := fields["attempts"]; ok {
+			otp.Attempts++
+		}
+		affected++
+	}
+	return affected, nil
+}
+
+func TestOtpUsecase_VerifyOtpLockout(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeOtpRepo{otps: []*entity.Otp{
+		{ID: 1, Handphone: "628123456789", TrxID: "trx1", PinCode: "123456", Status: "pending", CreatedAt: time.Now()},
+	}}
+	uc := usecase.NewOtpUsecaseImpl(openDryRun(t), repo, usecase.OtpConfig{ExpiredDuration: time.Minute, MaxVerifyAttempts: 3})
+
+	for range 3 {
+		assert.EqualError(t, uc.VerifyOtp(ctx, "628123456789", "trx1", "000000"), "Invalid OTP or expired")
+	}
+	assert.Equal(t, 3, repo.otps[0].Attempts)
+
+	// locked, even the right pin code is rejected
+	assert.EqualError(t, uc.VerifyOtp(ctx, "628123456789", "trx1", "000000"), "Too many attempts")
+	assert.EqualError(t, uc.VerifyOtp(ctx, "628123456789", "trx1", "123456"), "Too many attempts")
+	assert.Equal(t, 3, repo.otps[0].Attempts)
+	assert.Equal(t, "pending", repo.otps[0].Status)
+
+	valid, err := uc.Status(ctx, "628123456789", "trx1")
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestOtpUsecase_VerifyOtpBeforeLockout(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeOtpRepo{otps: []*entity.Otp{
+		{ID: 1, Handphone: "628123456789", TrxID: "trx1", PinCode: "123456", Status: "pending", CreatedAt: time.Now()},
+	}}
+	uc := usecase.NewOtpUsecaseImpl(openDryRun(t), repo, usecase.OtpConfig{ExpiredDuration: time.Minute, MaxVerifyAttempts: 3})
+
+	for range 2 {
+		assert.Error(t, uc.VerifyOtp(ctx, "628123456789", "trx1", "000000"))
+	}
+	require.NoError(t, uc.VerifyOtp(ctx, "628123456789", "trx1", "123456"))
+	assert.Equal(t, "verified", repo.otps[0].Status)
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/domain/repository"
+	"github.com/budimanlai/go-core/auth/domain/usecase"
+	"github.com/budimanlai/go-core/auth/models"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/security"
+	"gorm.io/gorm"
+)
+
+type PasswordPolicyUsecaseImpl struct {
+	base.BaseUsecase[entity.PasswordHistory]
+
+	policy  entity.PasswordPolicy
+	checker service.BreachedPasswordChecker
+	hasher  security.PasswordHasher
+}
+
+func NewPasswordPolicyUsecaseImpl(db *gorm.DB, repo repository.PasswordHistoryRepository, policy entity.PasswordPolicy) usecase.PasswordPolicyUsecase {
+	return &PasswordPolicyUsecaseImpl{
+		BaseUsecase: base.NewBaseUsecase(repo, db),
+		policy:      policy,
+		hasher:      security.NewBcryptHasher(),
+	}
+}
+
+func (u *PasswordPolicyUsecaseImpl) SetPasswordHasher(hasher security.PasswordHasher) {
+	u.hasher = hasher
+}
+
+func (u *PasswordPolicyUsecaseImpl) GetPolicy() entity.PasswordPolicy {
+	return u.policy
+}
+
+func (u *PasswordPolicyUsecaseImpl) SetPolicy(policy entity.PasswordPolicy) {
+	u.policy = policy
+}
+
+func (u *PasswordPolicyUsecaseImpl) SetBreachedChecker(checker service.BreachedPasswordChecker) {
+	u.checker = checker
+}
+
+// Validate checks the password rules, then the breached list, then the user's password history.
+func (u *PasswordPolicyUsecaseImpl) Validate(ctx context.Context, user *entity.User, password string) error {
+	// 1. length, character classes and identifiers
+	if err := u.policy.Check(password, user.Username, user.Email, user.Handphone); err != nil {
+		return err
+	}
+
+	// 2. breached passwords, a failing list does not block the user
+	if u.policy.CheckBreached && u.checker != nil {
+		if breached, err := u.checker.IsBreached(ctx, password); err == nil && breached {
+			return errors.New("password has appeared in a data breach, choose another one")
+		}
+	}
+
+	// 3. current and previous passwords
+	if u.policy.HistorySize <= 0 || user.ID == 0 {
+		return nil
+	}
+
+	hashes := []string{user.PasswordHash}
+	result, err := u.FindAll(ctx, 1, u.policy.HistorySize, func(d *gorm.DB) *gorm.DB {
+		return d.Where("user_id = ?", user.ID).Order("id DESC")
+	})
+	if err != nil {
+		return err
+	}
+	for _, history := range result.Data {
+		hashes = append(hashes, history.PasswordHash)
+	}
+
+	for _, hash := range hashes {
+		if hash == "" {
+			continue
+		}
+		if u.hasher.CheckPasswordHash(password, hash) {
+			return errors.New("password was used recently, choose another one")
+		}
+	}
+
+	return nil
+}
+
+func (u *PasswordPolicyUsecaseImpl) Remember(ctx context.Context, userID uint, passwordHash string) error {
+	if u.policy.HistorySize <= 0 {
+		return nil
+	}
+
+	err := u.Create(ctx, &entity.PasswordHistory{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// drop the entries beyond the history size
+	var keep []int
+	err = u.historyDB(ctx).Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(u.policy.HistorySize).
+		Pluck("id", &keep).Error
+	if err != nil || len(keep) < u.policy.HistorySize {
+		return err
+	}
+
+	return u.historyDB(ctx).
+		Where("user_id = ? AND id < ?", userID, keep[len(keep)-1]).
+		Delete(&models.PasswordHistory{}).Error
+}
+
+// historyDB returns the transaction injected in ctx if any, otherwise the default DB
+func (u *PasswordPolicyUsecaseImpl) historyDB(ctx context.Context) *gorm.DB {
+	if tx := base.ExtractTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return u.GetDB().WithContext(ctx)
+}
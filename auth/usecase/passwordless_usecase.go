@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/domain/repository"
+	"github.com/budimanlai/go-core/auth/domain/usecase"
+	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-core/base"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+
+	pkg_helpers "github.com/budimanlai/go-pkg/helpers"
+)
+
+const magicLinkTokenType = "magic_link"
+
+type PasswordlessConfig struct {
+	// MagicLinkURL is the page receiving the magic link, the token is added as the "token" query parameter
+	MagicLinkURL string
+
+	// MagicLinkSecret signs the magic link tokens, magic links are disabled when empty
+	MagicLinkSecret string
+
+	// MagicLinkTTL is how long a magic link stays valid, defaults to 15 minutes
+	MagicLinkTTL time.Duration
+}
+
+type PasswordlessUsecaseImpl struct {
+	base.BaseUsecase[entity.User]
+
+	config          PasswordlessConfig
+	magicLinkSender service.MagicLinkSenderService
+
+	OtpUC         usecase.OtpUsecase
+	UserSessionUC usecase.UserSessionUsecase
+}
+
+func NewPasswordlessUsecaseImpl(db *gorm.DB, userRepo repository.UserRepository, otpUC usecase.OtpUsecase,
+	userSessionUC usecase.UserSessionUsecase, config PasswordlessConfig) usecase.PasswordlessUsecase {
+	if config.MagicLinkTTL <= 0 {
+		config.MagicLinkTTL = 15 * time.Minute
+	}
+	return &PasswordlessUsecaseImpl{
+		BaseUsecase:   base.NewBaseUsecase(userRepo, db),
+		config:        config,
+		OtpUC:         otpUC,
+		UserSessionUC: userSessionUC,
+	}
+}
+
+func (u *PasswordlessUsecaseImpl) SetMagicLinkSender(sender service.MagicLinkSenderService) {
+	u.magicLinkSender = sender
+}
+
+// RequestLogin generates an OTP for the user and sends either the pin code or a magic link.
+// Unknown or inactive users get the same response without anything being sent, so the endpoint
+// does not reveal which emails and phone numbers are registered.
+func (u *PasswordlessUsecaseImpl) RequestLogin(ctx context.Context, request dto.PasswordlessRequest, appID int) (*dto.PasswordlessResponse, error) {
+	magicLink := request.Method == magicLinkTokenType
+	if magicLink && (request.Channel != "email" || u.magicLinkSender == nil || u.config.MagicLinkSecret == "") {
+		return nil, errors.New("magic link is not available")
+	}
+
+	out := dto.PasswordlessResponse{
+		Identifier: request.Identifier,
+		TrxID:      pkg_helpers.GenerateRandomString(16),
+	}
+
+	// 1. find active user by identifier
+	user, err := u.findUser(ctx, request.Channel, request.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive() {
+		return &out, nil
+	}
+
+	// 2. generate OTP, pending request limits apply
+	otpResponse, err := u.OtpUC.GenerateOTP(ctx, dto.OtpRequest{
+		Channel:    request.Channel,
+		Identifier: request.Identifier,
+		TrxID:      out.TrxID,
+		SkipSend:   magicLink,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.WaUrl = otpResponse.WaUrl
+
+	// 3. send magic link bound to the OTP
+	if magicLink {
+		link, err := u.magicLink(request.Identifier, out.TrxID, appID)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.magicLinkSender.SendMagicLink(request.Identifier, link); err != nil {
+			return nil, err
+		}
+	}
+
+	return &out, nil
+}
+
+// VerifyOtp verifies the pin code, with the OTP attempt limit, and consumes the OTP so it logs in only once.
+func (u *PasswordlessUsecaseImpl) VerifyOtp(ctx context.Context, request dto.PasswordlessVerifyRequest, appID int, fromIP, userAgent string) (*dto.LoginResponse, error) {
+	if err := u.OtpUC.VerifyOtp(ctx, request.Identifier, request.TrxID, request.PinCode); err != nil {
+		return nil, err
+	}
+
+	if err := u.OtpUC.Consume(ctx, request.Identifier, request.TrxID, "verified"); err != nil {
+		return nil, err
+	}
+
+	return u.login(ctx, request.Identifier, appID, fromIP, userAgent)
+}
+
+// ConsumeMagicLink verifies the signed token and consumes its pending OTP, so the link logs in only once.
+func (u *PasswordlessUsecaseImpl) ConsumeMagicLink(ctx context.Context, token, fromIP, userAgent string) (*dto.LoginResponse, error) {
+	if u.config.MagicLinkSecret == "" {
+		return nil, errors.New("magic link is not available")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(u.config.MagicLinkSecret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, errors.New("invalid or expired link")
+	}
+
+	typ, _ := claims["typ"].(string)
+	identifier, _ := claims["sub"].(string)
+	trxID, _ := claims["trx"].(string)
+	appID, _ := claims["app"].(float64)
+	if typ != magicLinkTokenType || identifier == "" || trxID == "" {
+		return nil, errors.New("invalid or expired link")
+	}
+
+	if err := u.OtpUC.Consume(ctx, identifier, trxID, "pending"); err != nil {
+		return nil, err
+	}
+
+	return u.login(ctx, identifier, int(appID), fromIP, userAgent)
+}
+
+func (u *PasswordlessUsecaseImpl) magicLink(identifier, trxID string, appID int) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"typ": magicLinkTokenType,
+		"sub": identifier,
+		"trx": trxID,
+		"app": appID,
+		"exp": time.Now().Add(u.config.MagicLinkTTL).Unix(),
+	}).SignedString([]byte(u.config.MagicLinkSecret))
+	if err != nil {
+		return "", err
+	}
+
+	link, err := url.Parse(u.config.MagicLinkURL)
+	if err != nil {
+		return "", err
+	}
+	q := link.Query()
+	q.Set("token", token)
+	link.RawQuery = q.Encode()
+	return link.String(), nil
+}
+
+func (u *PasswordlessUsecaseImpl) findUser(ctx context.Context, channel, identifier string) (*entity.User, error) {
+	return u.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
+		if channel == "email" {
+			return d.Where("email = ?", identifier)
+		}
+		return d.Where("handphone = ?", identifier)
+	})
+}
+
+func (u *PasswordlessUsecaseImpl) login(ctx context.Context, identifier string, appID int, fromIP, userAgent string) (*dto.LoginResponse, error) {
+	channel := "phone"
+	if pkg_helpers.IsValidEmail(identifier) {
+		channel = "email"
+	}
+
+	user, err := u.findUser(ctx, channel, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive() {
+		return nil, errors.New("user is not active")
+	}
+
+	accessToken, err := u.UserSessionUC.GenerateToken(ctx, user.ID, appID, fromIP, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Handphone: user.Handphone,
+		Fullname:  user.Fullname,
+		Token: dto.Token{
+			AccessToken: accessToken,
+		},
+	}, nil
+}
@@ -0,0 +1,108 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/domain/repository"
+	dom_usecase "github.com/budimanlai/go-core/auth/domain/usecase"
+	"github.com/budimanlai/go-core/auth/usecase"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users []*entity.User
+
+	// updates keeps the fields of every UpdateFields
+	updates []map[string]interface{}
+}
+
+func (r *fakeUserRepo) FindOne(ctx context.Context, scopes ...func(*gorm.DB) *gorm.DB) (*entity.User, error) {
+	for _, user := range r.users {
+		column := func(name string) interface{} {
+			return map[string]interface{}{"email": user.Email, "handphone": user.Handphone}[name]
+		}
+		if matches(scopes, column) {
+			out := *user
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id any, scopes ...func(*gorm.DB) *gorm.DB) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.ID == id.(uint) {
+			out := *user
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) UpdateFields(ctx context.Context, id any, fields map[string]interface{}) error {
+	r.updates = append(r.updates, fields)
+	return nil
+}
+
+// fakeSessionUsecase counts the issued tokens
+type fakeSessionUsecase struct {
+	dom_usecase.UserSessionUsecase
+	issued int
+}
+
+func (u *fakeSessionUsecase) GenerateToken(ctx context.Context, userID uint, appID int, fromIP, userAgent string) (string, error) {
+	u.issued++
+	return "access-token", nil
+}
+
+func TestPasswordlessUsecase_ConsumeMagicLink(t *testing.T) {
+	ctx := context.Background()
+	const secret = "magic-secret"
+
+	db := openDryRun(t)
+	otps := &fakeOtpRepo{otps: []*entity.Otp{
+		{ID: 1, Handphone: "john@example.com", TrxID: "trx1", PinCode: "123456", Status: "pending", CreatedAt: time.Now()},
+	}}
+	users := &fakeUserRepo{users: []*entity.User{{ID: 7, Email: "john@example.com", Status: "active"}}}
+	sessions := &fakeSessionUsecase{}
+	otpUC := usecase.NewOtpUsecaseImpl(db, otps, usecase.OtpConfig{ExpiredDuration: time.Minute})
+	uc := usecase.NewPasswordlessUsecaseImpl(db, users, otpUC, sessions, usecase.PasswordlessConfig{MagicLinkSecret: secret})
+
+	sign := func(trxID string, exp time.Time) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"typ": "magic_link",
+			"sub": "john@example.com",
+			"trx": trxID,
+			"app": 1,
+			"exp": exp.Unix(),
+		}).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("Expired link", func(t *testing.T) {
+		_, err := uc.ConsumeMagicLink(ctx, sign("trx1", time.Now().Add(-time.Minute)), "", "")
+		assert.EqualError(t, err, "invalid or expired link")
+		assert.Equal(t, "pending", otps.otps[0].Status)
+	})
+
+	t.Run("Single use", func(t *testing.T) {
+		link := sign("trx1", time.Now().Add(time.Minute))
+
+		out, err := uc.ConsumeMagicLink(ctx, link, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), out.UserID)
+		assert.Equal(t, "used", otps.otps[0].Status)
+
+		_, err = uc.ConsumeMagicLink(ctx, link, "", "")
+		assert.Error(t, err)
+		assert.Equal(t, 1, sessions.issued)
+	})
+}
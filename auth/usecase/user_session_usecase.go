@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/budimanlai/go-core/auth/domain/entity"
@@ -11,15 +13,17 @@ import (
 	"github.com/budimanlai/go-core/auth/domain/usecase"
 	"github.com/budimanlai/go-core/auth/dto"
 	"github.com/budimanlai/go-core/auth/models"
+	"github.com/budimanlai/go-core/auth/service"
 	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/security"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	pkg_helpers "github.com/budimanlai/go-pkg/helpers"
 	pkg_auth "github.com/budimanlai/go-pkg/middleware/auth"
-	pkg_security "github.com/budimanlai/go-pkg/security"
 )
 
 type UserSessionUsecaseImpl struct {
@@ -27,57 +31,169 @@ type UserSessionUsecaseImpl struct {
 
 	UserRepository repository.UserRepository
 
-	// MultipleLoginAllowed indicates whether multiple logins are allowed for a user
-	MultipleLoginAllowed bool
+	// SessionPolicy limits the number of active sessions per user and per app
+	SessionPolicy entity.SessionPolicy
 
 	JWTService *pkg_auth.JWTAuth
+
+	// TokenService signs tokens with extra claims, falls back to JWTService if nil
+	TokenService service.TokenService
+
+	// ClaimsProvider returns the extra claims put in issued tokens, optional
+	ClaimsProvider service.ClaimsProvider
+
+	// AuthContextResolvers enrich the AuthContext of each authenticated request, optional
+	AuthContextResolvers []service.AuthContextResolver
+
+	// SessionStore caches active sessions so token lookups skip the database, optional
+	SessionStore service.SessionStore
+
+	// AppRegistry provides per app session policy and audience, optional
+	AppRegistry service.AppRegistry
+
+	// PasswordHasher checks passwords on login and upgrades legacy hashes
+	PasswordHasher security.PasswordHasher
+
+	// RequireVerified rejects the logins of users who have not verified their email
+	RequireVerified bool
 }
 
 func NewUserSessionUsecaseImpl(db *gorm.DB, repo repository.UserSessionRepository,
 	userRepo repository.UserRepository, jwtService *pkg_auth.JWTAuth) usecase.UserSessionUsecase {
 	return &UserSessionUsecaseImpl{
-		BaseUsecase:          base.NewBaseUsecase(repo, db),
-		UserRepository:       userRepo,
-		MultipleLoginAllowed: false,
-		JWTService:           jwtService,
+		BaseUsecase:    base.NewBaseUsecase(repo, db),
+		UserRepository: userRepo,
+		SessionPolicy:  entity.DefaultSessionPolicy(),
+		JWTService:     jwtService,
+		PasswordHasher: security.NewBcryptHasher(),
 	}
 }
 
+// SetPasswordHasher sets the hasher checking passwords on login
+func (u *UserSessionUsecaseImpl) SetPasswordHasher(hasher security.PasswordHasher) {
+	u.PasswordHasher = hasher
+}
+
 // IsMultipleLoginAllowed returns whether multiple logins are allowed for a user
 func (u *UserSessionUsecaseImpl) IsMultipleLoginAllowed() bool {
-	return u.MultipleLoginAllowed
+	return u.SessionPolicy.MaxSessions != 1
 }
 
-// SetMultipleLoginAllowed sets whether multiple logins are allowed for a user
+// SetMultipleLoginAllowed sets whether multiple logins are allowed for a user.
+// It is a shortcut for SetSessionPolicy with unlimited or a single session.
 func (u *UserSessionUsecaseImpl) SetMultipleLoginAllowed(allowed bool) {
-	u.MultipleLoginAllowed = allowed
+	if allowed {
+		u.SessionPolicy.MaxSessions = 0
+	} else {
+		u.SessionPolicy.MaxSessions = 1
+		u.SessionPolicy.OnLimit = entity.SessionLimitEvictOldest
+	}
+}
+
+// GetSessionPolicy returns the active session policy
+func (u *UserSessionUsecaseImpl) GetSessionPolicy() entity.SessionPolicy {
+	return u.SessionPolicy
+}
+
+// SetSessionPolicy sets the session policy honored by GenerateSession
+func (u *UserSessionUsecaseImpl) SetSessionPolicy(policy entity.SessionPolicy) {
+	u.SessionPolicy = policy
+}
+
+// SetSessionStore sets the store used to cache active sessions
+func (u *UserSessionUsecaseImpl) SetSessionStore(store service.SessionStore) {
+	u.SessionStore = store
+}
+
+// SetTokenService sets the service signing tokens with extra claims
+func (u *UserSessionUsecaseImpl) SetTokenService(tokenService service.TokenService) {
+	u.TokenService = tokenService
+}
+
+// SetClaimsProvider sets the provider of extra claims put in issued tokens
+func (u *UserSessionUsecaseImpl) SetClaimsProvider(provider service.ClaimsProvider) {
+	u.ClaimsProvider = provider
+}
+
+// AddAuthContextResolver adds a resolver enriching the AuthContext of each authenticated request
+func (u *UserSessionUsecaseImpl) AddAuthContextResolver(resolver service.AuthContextResolver) {
+	u.AuthContextResolvers = append(u.AuthContextResolvers, resolver)
+}
+
+// SetAppRegistry sets the registry of apps sharing this user base
+func (u *UserSessionUsecaseImpl) SetAppRegistry(registry service.AppRegistry) {
+	u.AppRegistry = registry
+}
+
+// SetRequireVerified rejects the logins of users who have not verified their email
+func (u *UserSessionUsecaseImpl) SetRequireVerified(required bool) {
+	u.RequireVerified = required
+}
+
+// findApp returns the registered app with the given ID, or nil if none
+func (u *UserSessionUsecaseImpl) findApp(appID int) *entity.App {
+	if u.AppRegistry == nil {
+		return nil
+	}
+	return u.AppRegistry.FindByID(appID)
 }
 
 // RevokeSessionsByUserID revokes all sessions for the given user ID
-func (u *UserSessionUsecaseImpl) RevokeSessionsByUserID(ctx context.Context, userID uint) {
+func (u *UserSessionUsecaseImpl) RevokeSessionsByUserID(ctx context.Context, userID uint) error {
 	// Revoke all sessions for the given user ID
-	u.GetDB().Model(&models.UserSession{}).Where("user_id = ? AND remove_on IS NULL", userID).
-		Update("remove_on", time.Now())
+	err := u.sessionDB(ctx).Model(&models.UserSession{}).Where("user_id = ? AND remove_on IS NULL", userID).
+		Update("remove_on", time.Now()).Error
+	if err != nil {
+		return err
+	}
+
+	u.InvalidateSessionsByUserID(ctx, userID)
+	return nil
 }
 
-// GenerateSession creates a new user session for the given user ID
-func (u *UserSessionUsecaseImpl) GenerateSession(ctx context.Context, userID uint, fromIP, userAgent string) (*entity.UserSession, error) {
-	var out *entity.UserSession
-	err := u.GetDB().Transaction(func(tx *gorm.DB) error {
-		// check if multiple login is allowed
-		if !u.IsMultipleLoginAllowed() {
-			// revoke other sessions
-			u.RevokeSessionsByUserID(ctx, userID)
-		}
+// RevokeOtherSessions revokes all sessions for the given user ID but the current one,
+// e.g. after a password change the other devices must log in again
+func (u *UserSessionUsecaseImpl) RevokeOtherSessions(ctx context.Context, userID uint, currentToken string) error {
+	err := u.sessionDB(ctx).Model(&models.UserSession{}).Where("user_id = ? AND tokens <> ? AND remove_on IS NULL", userID, currentToken).
+		Update("remove_on", time.Now()).Error
+	if err != nil {
+		return err
+	}
 
-		// create new session
-		sessionEntity := &entity.UserSession{
-			UserID:       userID,
-			AppID:        1,
-			Tokens:       pkg_helpers.GenerateRandomString(32),
-			FromIP:       fromIP,
-			UserAgent:    userAgent,
-			LastAccessOn: pkg_helpers.Pointer(time.Now()),
+	// drop all cached sessions, the current one is cached again on its next request
+	u.InvalidateSessionsByUserID(ctx, userID)
+	return nil
+}
+
+// InvalidateSessionsByUserID drops the cached sessions of the given user ID without revoking them,
+// so the next request re-checks the user against the database (e.g. after a status change)
+func (u *UserSessionUsecaseImpl) InvalidateSessionsByUserID(ctx context.Context, userID uint) {
+	if u.SessionStore != nil {
+		u.SessionStore.DeleteByUserID(ctx, userID)
+	}
+}
+
+// GenerateSession creates a new user session for the given user ID and app ID
+func (u *UserSessionUsecaseImpl) GenerateSession(ctx context.Context, userID uint, appID int, fromIP, userAgent string, opts ...entity.SessionOption) (*entity.UserSession, error) {
+	sessionEntity := &entity.UserSession{
+		UserID:       userID,
+		AppID:        appID,
+		Tokens:       pkg_helpers.GenerateRandomString(32),
+		FromIP:       fromIP,
+		UserAgent:    userAgent,
+		LastAccessOn: pkg_helpers.Pointer(time.Now()),
+	}
+	for _, opt := range opts {
+		opt(sessionEntity)
+	}
+
+	var out *entity.UserSession
+	err := u.WithTransaction(ctx, func(ctx context.Context) error {
+		// enforce max active sessions per user and per app, an impersonation must not log the user out
+		if !sessionEntity.IsImpersonated() {
+			if err := u.enforceSessionPolicy(ctx, userID, appID); err != nil {
+				return err
+			}
 		}
 
 		// save to db
@@ -93,8 +209,115 @@ func (u *UserSessionUsecaseImpl) GenerateSession(ctx context.Context, userID uin
 	return out, err
 }
 
+// enforceSessionPolicy makes room for a new session of the given user and app,
+// by evicting the oldest sessions or rejecting the login depending on the policy
+func (u *UserSessionUsecaseImpl) enforceSessionPolicy(ctx context.Context, userID uint, appID int) error {
+	// the app own policy wins over the default one
+	appLimit, appRejectNew := u.SessionPolicy.AppLimit(appID), u.SessionPolicy.IsRejectNew()
+	if app := u.findApp(appID); app != nil && app.SessionPolicy != nil {
+		appLimit, appRejectNew = app.SessionPolicy.MaxSessions, app.SessionPolicy.IsRejectNew()
+	}
+	if u.SessionPolicy.MaxSessions <= 0 && appLimit <= 0 {
+		return nil
+	}
+
+	// concurrent logins of the same user must count and evict one after another,
+	// otherwise both see room for one more session and the limit is exceeded
+	if err := u.lockUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	// 1. limit across all apps
+	err := u.enforceSessionLimit(ctx, u.SessionPolicy.MaxSessions, u.SessionPolicy.IsRejectNew(), func(d *gorm.DB) *gorm.DB {
+		return d.Where("user_id = ? AND impersonator_id = 0 AND remove_on IS NULL", userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 2. limit for the given app
+	return u.enforceSessionLimit(ctx, appLimit, appRejectNew, func(d *gorm.DB) *gorm.DB {
+		return d.Where("user_id = ? AND app_id = ? AND impersonator_id = 0 AND remove_on IS NULL", userID, appID)
+	})
+}
+
+// lockUserSessions locks the user row and its active session rows until the transaction ends.
+// The user row serializes the first login too, when there is no session row to lock yet.
+func (u *UserSessionUsecaseImpl) lockUserSessions(ctx context.Context, userID uint) error {
+	var ids []uint
+	err := u.sessionDB(ctx).Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", userID).Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	return u.sessionDB(ctx).Model(&models.UserSession{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND impersonator_id = 0 AND remove_on IS NULL", userID).Pluck("id", &ids).Error
+}
+
+// enforceSessionLimit keeps the active sessions matched by scope below limit, 0 means unlimited
+func (u *UserSessionUsecaseImpl) enforceSessionLimit(ctx context.Context, limit int, rejectNew bool, scope func(*gorm.DB) *gorm.DB) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	active, err := u.Count(ctx, scope)
+	if err != nil {
+		return err
+	}
+	if active < int64(limit) {
+		return nil
+	}
+
+	if rejectNew {
+		return errors.New("maximum active sessions reached")
+	}
+
+	var rows []models.UserSession
+	if err := u.sessionDB(ctx).Model(&models.UserSession{}).Scopes(scope).Find(&rows).Error; err != nil {
+		return err
+	}
+	sessions := make([]entity.UserSession, len(rows))
+	for i, row := range rows {
+		sessions[i] = entity.UserSession{ID: row.ID, Tokens: row.Tokens, CreateOn: row.CreateOn}
+	}
+
+	// evict the oldest sessions, keep limit-1 so the new session fits
+	oldest := entity.SessionsToEvict(sessions, len(sessions)-limit+1)
+	if len(oldest) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(oldest))
+	for i, session := range oldest {
+		ids[i] = session.ID
+	}
+	err = u.sessionDB(ctx).Model(&models.UserSession{}).Where("id IN ?", ids).
+		Update("remove_on", time.Now()).Error
+	if err != nil {
+		return err
+	}
+
+	// drop evicted sessions from cache
+	if u.SessionStore != nil {
+		for _, session := range oldest {
+			u.SessionStore.Delete(ctx, session.Tokens)
+		}
+	}
+
+	return nil
+}
+
+// sessionDB returns the transaction injected in ctx if any, otherwise the default DB
+func (u *UserSessionUsecaseImpl) sessionDB(ctx context.Context) *gorm.DB {
+	if tx := base.ExtractTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return u.GetDB().WithContext(ctx)
+}
+
 // Login authenticates a user and returns a dto.LoginResponse if successful
-func (u *UserSessionUsecaseImpl) Login(ctx context.Context, username, password, fromIP, userAgent string) (*dto.LoginResponse, error) {
+func (u *UserSessionUsecaseImpl) Login(ctx context.Context, appID int, username, password, fromIP, userAgent string) (*dto.LoginResponse, error) {
 	// 1. check if username or password is not empty
 	if username == "" || password == "" {
 		return nil, errors.New("username or password can't blank")
@@ -117,14 +340,21 @@ func (u *UserSessionUsecaseImpl) Login(ctx context.Context, username, password,
 	}
 
 	// 4. verify password
-	if ok, err := pkg_security.CheckPasswordHash(password, user.PasswordHash); err != nil {
-		return nil, err
-	} else if !ok {
+	if !u.PasswordHasher.CheckPasswordHash(password, user.PasswordHash) {
 		return nil, errors.New("invalid password")
 	}
 
+	// upgrade legacy or weaker hash while the plain password is known
+	if u.PasswordHasher.NeedsRehash(user.PasswordHash) {
+		if hash, err := u.PasswordHasher.HashPassword(password); err == nil && hash != "" {
+			u.UserRepository.UpdateFields(ctx, user.ID, map[string]interface{}{
+				"password_hash": hash,
+			})
+		}
+	}
+
 	// 5. generate user session and token
-	accessToken, err := u.GenerateToken(ctx, user.ID, fromIP, userAgent)
+	accessToken, err := u.GenerateToken(ctx, user.ID, appID, fromIP, userAgent)
 	if err != nil {
 		return nil, err
 	}
@@ -143,16 +373,30 @@ func (u *UserSessionUsecaseImpl) Login(ctx context.Context, username, password,
 	return &out, nil
 }
 
-// GenerateToken creates a new user session and generates a JWT token for the given user ID
-func (u *UserSessionUsecaseImpl) GenerateToken(ctx context.Context, user_id uint, fromIP, userAgent string) (string, error) {
+// GenerateToken creates a new user session and generates a JWT token for the given user ID and app ID
+func (u *UserSessionUsecaseImpl) GenerateToken(ctx context.Context, user_id uint, appID int, fromIP, userAgent string) (string, error) {
+	// every login method ends here, an impersonation uses GenerateSession and is not checked
+	if u.RequireVerified {
+		user, err := u.UserRepository.FindByID(ctx, user_id)
+		if err != nil {
+			return "", err
+		}
+		if user == nil {
+			return "", errors.New("user not found")
+		}
+		if !user.IsVerified() {
+			return "", errors.New("email is not verified")
+		}
+	}
+
 	// 1. Generate user session and save to user_sessions table
-	sessionEntity, err := u.GenerateSession(ctx, user_id, fromIP, userAgent)
+	sessionEntity, err := u.GenerateSession(ctx, user_id, appID, fromIP, userAgent)
 	if err != nil {
 		return "", err
 	}
 
 	// 2. Generate JWT token with session token as claim
-	accessToken, err := u.JWTService.GenerateToken(sessionEntity.Tokens)
+	accessToken, err := u.SignToken(ctx, sessionEntity)
 	if err != nil {
 		return "", err
 	}
@@ -160,19 +404,75 @@ func (u *UserSessionUsecaseImpl) GenerateToken(ctx context.Context, user_id uint
 	return accessToken, nil
 }
 
+// SignToken generates the JWT token for the given session, with the app, audience and extra claims.
+// An impersonation session carries the impersonator in the "imp" claim, it needs a TokenService.
+func (u *UserSessionUsecaseImpl) SignToken(ctx context.Context, session *entity.UserSession) (string, error) {
+	if u.TokenService == nil {
+		return u.JWTService.GenerateToken(session.Tokens)
+	}
+
+	claims := map[string]interface{}{
+		"app": session.AppID,
+	}
+	if app := u.findApp(session.AppID); app != nil && app.Audience != "" {
+		claims["aud"] = app.Audience
+	}
+
+	if u.ClaimsProvider != nil {
+		extra, err := u.ClaimsProvider(ctx, session)
+		if err != nil {
+			return "", err
+		}
+		for key, value := range extra {
+			claims[key] = value
+		}
+	}
+
+	// set last so the claims provider cannot change it
+	delete(claims, "imp")
+	if session.IsImpersonated() {
+		claims["imp"] = session.ImpersonatorID
+	}
+
+	return u.TokenService.GenerateToken(session.Tokens, claims)
+}
+
 // Logout revokes the user session associated with the given token string
 func (u *UserSessionUsecaseImpl) Logout(ctx context.Context, tokenString string) error {
 	// revoke session
-	return u.GetDB().Model(&models.UserSession{}).
+	err := u.GetDB().Model(&models.UserSession{}).
 		Where("tokens = ? AND remove_on IS NULL", tokenString).
 		Update("remove_on", time.Now()).Error
+	if err != nil {
+		return err
+	}
+
+	// drop cached session
+	if u.SessionStore != nil {
+		u.SessionStore.Delete(ctx, tokenString)
+	}
+
+	return nil
+}
+
+// RevokeSession revokes the session with the given ID, e.g. to end an impersonation
+func (u *UserSessionUsecaseImpl) RevokeSession(ctx context.Context, sessionID int) error {
+	session, err := u.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return errors.New("session not found")
+	}
+
+	return u.Logout(ctx, session.Tokens)
 }
 
 // check if token is valid and return user session
 func (u *UserSessionUsecaseImpl) VerifyToken(ctx context.Context, tokenString string) (*dto.LoginResponse, error) {
 	// check if token isExists in user sessions
 	result, err := u.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
-		return d.Where("tokens = ? AND remove_on IS NULL", tokenString)
+		return d.Where("tokens = ? AND remove_on IS NULL AND (expire_on IS NULL OR expire_on > ?)", tokenString, time.Now())
 	})
 	if err != nil {
 		return nil, err
@@ -200,7 +500,7 @@ func (u *UserSessionUsecaseImpl) VerifyToken(ctx context.Context, tokenString st
 		return nil, errors.New("user not found")
 	}
 
-	accessToken, err := u.JWTService.GenerateToken(tokenString)
+	accessToken, err := u.SignToken(ctx, result)
 	if err != nil {
 		return nil, err
 	}
@@ -219,27 +519,104 @@ func (u *UserSessionUsecaseImpl) VerifyToken(ctx context.Context, tokenString st
 	return &out, nil
 }
 
-// SuccessHandler is called when JWT authentication is successful
+// SuccessHandler is called when JWT authentication is successful.
+// It resolves the AuthContext once per request and stores it in c.Locals and c.UserContext().
 func (u *UserSessionUsecaseImpl) SuccessHandler(c *fiber.Ctx, claims jwt.MapClaims) error {
-	session := claims["ses"]
+	session, ok := claims["ses"].(string)
+	if !ok || session == "" {
+		return fiber.ErrUnauthorized
+	}
 
 	// 1. get user_id by session token
-	userSession, err := u.GetUserIDByToken(c.Context(), session.(string))
+	userSession, err := u.GetUserIDByToken(c.Context(), session)
 	if err != nil {
 		return fiber.ErrUnauthorized
 	}
+
+	// 2. reject token issued for another app, a request not identifying its app is for the default app
+	appID, ok := c.Locals("app_id").(int)
+	if !ok {
+		appID = entity.DefaultAppID
+	}
+	if appID != userSession.AppID {
+		return fiber.ErrUnauthorized
+	}
+	if u.AppRegistry != nil && u.AppRegistry.HasApps() {
+		app := u.findApp(userSession.AppID)
+		if app == nil {
+			return fiber.ErrUnauthorized
+		}
+		if app.Audience != "" {
+			if aud, err := claims.GetAudience(); err != nil || !slices.Contains(aud, app.Audience) {
+				return fiber.ErrUnauthorized
+			}
+		}
+	}
+
+	// 3. build auth context, roles and scopes may come from the token claims
+	authCtx := &entity.AuthContext{
+		UserID:         userSession.UserID,
+		SessionID:      userSession.ID,
+		AppID:          userSession.AppID,
+		Token:          session,
+		ImpersonatorID: userSession.ImpersonatorID,
+		Roles:          claimStrings(claims, "roles"),
+		Scopes:         claimStrings(claims, "scopes"),
+		Claims:         claims,
+	}
+	for _, resolver := range u.AuthContextResolvers {
+		if err := resolver(c.Context(), authCtx); err != nil {
+			return fiber.ErrUnauthorized
+		}
+	}
+
+	c.Locals(entity.AuthContextKey{}, authCtx)
+	c.SetUserContext(entity.InjectAuthContext(c.UserContext(), authCtx))
+	c.Locals("user_token", session)
 	c.Locals("user_id", fmt.Sprintf("%v", userSession.UserID))
+	c.Locals("app_id", userSession.AppID)
+	return nil
+}
+
+// claimStrings reads a claim as a list of strings, from a JSON array or a space separated string
+func claimStrings(claims jwt.MapClaims, key string) []string {
+	switch v := claims[key].(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
 	return nil
 }
 
 // GetUserIDByToken retrieves the user session associated with the given token string
 func (u *UserSessionUsecaseImpl) GetUserIDByToken(ctx context.Context, tokenString string) (*entity.UserSession, error) {
+	// check session store first, fallback to database on miss or store error
+	if u.SessionStore != nil {
+		if cached, err := u.SessionStore.Get(ctx, tokenString); err == nil && cached != nil {
+			if !cached.IsExpired(time.Now()) {
+				return cached, nil
+			}
+			u.SessionStore.Delete(ctx, tokenString)
+		}
+	}
+
 	// check if token isExists in user sessions
 	result, err := u.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
 		// join with users table to ensure user is active
 		return d.Joins("JOIN users ON users.id = user_sessions.user_id AND users.status = ?", "active").
-			Select("user_sessions.user_id").
-			Where("tokens = ? AND remove_on IS NULL", tokenString)
+			Select("user_sessions.id, user_sessions.app_id, user_sessions.user_id, user_sessions.tokens, "+
+				"user_sessions.impersonator_id, user_sessions.expire_on").
+			Where("tokens = ? AND remove_on IS NULL", tokenString).
+			Where("expire_on IS NULL OR expire_on > ?", time.Now())
 	})
 	if err != nil {
 		return nil, err
@@ -250,5 +627,10 @@ func (u *UserSessionUsecaseImpl) GetUserIDByToken(ctx context.Context, tokenStri
 		return nil, errors.New("invalid token")
 	}
 
+	// cache session for next requests
+	if u.SessionStore != nil {
+		u.SessionStore.Set(ctx, result)
+	}
+
 	return result, nil
 }
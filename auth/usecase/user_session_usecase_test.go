@@ -0,0 +1,106 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/auth/domain/repository"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-core/auth/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+// txPool lets WithTransaction begin and commit without a database
+type txPool struct{ gorm.ConnPool }
+
+func (p *txPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &txConn{}, nil
+}
+
+type txConn struct{ gorm.ConnPool }
+
+func (*txConn) Commit() error   { return nil }
+func (*txConn) Rollback() error { return nil }
+
+func openTxDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true, ConnPool: &txPool{}})
+	require.NoError(t, err)
+	return db
+}
+
+// fakeSessionRepo keeps the created sessions, there is never an active one to count
+type fakeSessionRepo struct {
+	repository.UserSessionRepository
+	sessions []entity.UserSession
+}
+
+func (r *fakeSessionRepo) Count(ctx context.Context, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session *entity.UserSession) error {
+	session.ID = len(r.sessions) + 1
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+type fakeTokenService struct{ service.TokenService }
+
+func (fakeTokenService) GenerateToken(sessionToken string, claims map[string]interface{}) (string, error) {
+	return "access-token", nil
+}
+
+func TestUserSessionUsecase_RequireVerified(t *testing.T) {
+	ctx := context.Background()
+	token := "pending"
+	users := &fakeUserRepo{users: []*entity.User{
+		{ID: 1, Email: "john@example.com", Status: "active"},
+		{ID: 2, Email: "jane@example.com", Status: "active", VerificationToken: &token},
+	}}
+	sessions := &fakeSessionRepo{}
+	uc := usecase.NewUserSessionUsecaseImpl(openTxDB(t), sessions, users, nil)
+	uc.SetTokenService(fakeTokenService{})
+
+	_, err := uc.GenerateToken(ctx, 2, 1, "", "")
+	require.NoError(t, err, "verification not required")
+
+	uc.SetRequireVerified(true)
+	_, err = uc.GenerateToken(ctx, 2, 1, "", "")
+	assert.EqualError(t, err, "email is not verified")
+	_, err = uc.GenerateToken(ctx, 1, 1, "", "")
+	assert.NoError(t, err)
+	assert.Len(t, sessions.sessions, 2)
+}
+
+func TestUserSessionUsecase_GenerateSessionLocksUserSessions(t *testing.T) {
+	db := openTxDB(t)
+	var queries []string
+	err := db.Callback().Query().After("gorm:query").Register("test:capture", func(d *gorm.DB) {
+		queries = append(queries, d.Statement.SQL.String())
+	})
+	require.NoError(t, err)
+
+	sessions := &fakeSessionRepo{}
+	uc := usecase.NewUserSessionUsecaseImpl(db, sessions, &fakeUserRepo{}, nil)
+
+	_, err = uc.GenerateSession(context.Background(), 1, 1, "", "")
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+	assert.Contains(t, queries[0], "FROM `users`")
+	assert.Contains(t, queries[0], "FOR UPDATE")
+	assert.Contains(t, queries[1], "FROM `user_sessions`")
+	assert.Contains(t, queries[1], "FOR UPDATE")
+
+	// without a limit there is nothing to count, so nothing is locked
+	queries = nil
+	uc.SetSessionPolicy(entity.SessionPolicy{})
+	_, err = uc.GenerateSession(context.Background(), 1, 1, "", "")
+	require.NoError(t, err)
+	assert.Empty(t, queries)
+	assert.Len(t, sessions.sessions, 2)
+}
@@ -3,18 +3,21 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/budimanlai/go-core/auth/domain/entity"
 	"github.com/budimanlai/go-core/auth/domain/repository"
 	"github.com/budimanlai/go-core/auth/domain/usecase"
 	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-core/auth/service"
 	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/security"
 	"github.com/jinzhu/copier"
 	"gorm.io/gorm"
 
 	pkg_helpers "github.com/budimanlai/go-pkg/helpers"
-	pkg_security "github.com/budimanlai/go-pkg/security"
 )
 
 type UserUsecaseImpl struct {
@@ -22,16 +25,30 @@ type UserUsecaseImpl struct {
 
 	OtpUC         usecase.OtpUsecase
 	UserSessionUC usecase.UserSessionUsecase
+
+	// PasswordPolicyUC validates new passwords and keeps the password history, optional
+	PasswordPolicyUC usecase.PasswordPolicyUsecase
+
+	// PasswordHasher hashes new passwords and checks the current one
+	PasswordHasher security.PasswordHasher
+
+	// Notifier tells the old email or handphone about a change, optional
+	Notifier service.IdentifierChangeNotifier
 }
 
 func NewUserUsecaseImpl(db *gorm.DB, repo repository.UserRepository, otpUC usecase.OtpUsecase, userSessionUC usecase.UserSessionUsecase) usecase.UserUsecase {
 	return &UserUsecaseImpl{
-		BaseUsecase:   base.NewBaseUsecase(repo, db),
-		OtpUC:         otpUC,
-		UserSessionUC: userSessionUC,
+		BaseUsecase:    base.NewBaseUsecase(repo, db),
+		OtpUC:          otpUC,
+		UserSessionUC:  userSessionUC,
+		PasswordHasher: security.NewBcryptHasher(),
 	}
 }
 
+func (u *UserUsecaseImpl) SetPasswordHasher(hasher security.PasswordHasher) {
+	u.PasswordHasher = hasher
+}
+
 // ResetPassword resets the user's password after verifying the OTP.
 func (u *UserUsecaseImpl) ResetPassword(ctx context.Context, request dto.ResetPasswordRequest) error {
 	// 1. Check if otp is valid
@@ -55,14 +72,27 @@ func (u *UserUsecaseImpl) ResetPassword(ctx context.Context, request dto.ResetPa
 		return errors.New("User not found")
 	}
 
-	// 3. Update user password
-	user.PasswordHash = pkg_security.HashPassword(request.Password)
-	err = u.Update(ctx, user)
+	// 3. Check password policy
+	if err := u.validatePassword(ctx, user, request.Password); err != nil {
+		return err
+	}
+
+	// 4. Update user password
+	user.PasswordHash, err = u.hashPassword(request.Password)
+	if err != nil {
+		return err
+	}
+	err = u.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.Update(ctx, user); err != nil {
+			return err
+		}
+		return u.rememberPassword(ctx, user.ID, user.PasswordHash)
+	})
 	if err != nil {
 		return err
 	}
 
-	// 4. Revoke OTP
+	// 5. Revoke OTP
 	u.OtpUC.Revoke(ctx, request.Identifier, request.TrxID)
 
 	return nil
@@ -101,7 +131,17 @@ func (u *UserUsecaseImpl) Register(ctx context.Context, req dto.RegisterRequest)
 		return nil, errors.New("Handphone is already registered")
 	}
 
-	// 4. Create new user
+	// 4. Check password policy
+	if err := u.validatePassword(ctx, &entity.User{Username: req.Email, Email: req.Email, Handphone: req.Handphone}, req.Password); err != nil {
+		return nil, err
+	}
+
+	// 5. Create new user
+	passwordHash, err := u.hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	var out dto.LoginResponse
 	err = u.WithTransaction(ctx, func(ctx context.Context) error {
 		// 1. create user entity
@@ -110,7 +150,7 @@ func (u *UserUsecaseImpl) Register(ctx context.Context, req dto.RegisterRequest)
 			Email:        req.Email,
 			Handphone:    req.Handphone,
 			AuthKey:      pkg_helpers.GenerateRandomString(32),
-			PasswordHash: pkg_security.HashPassword(req.Password),
+			PasswordHash: passwordHash,
 			Fullname:     req.Fullname,
 			Status:       "active",
 			CreatedBy:    1,
@@ -122,9 +162,12 @@ func (u *UserUsecaseImpl) Register(ctx context.Context, req dto.RegisterRequest)
 		if err != nil {
 			return err
 		}
+		if err := u.rememberPassword(ctx, newUser.ID, newUser.PasswordHash); err != nil {
+			return err
+		}
 
 		// 2. generate jwt token
-		accessToken, err := u.UserSessionUC.GenerateToken(ctx, newUser.ID, req.FromIP, req.UserAgent)
+		accessToken, err := u.UserSessionUC.GenerateToken(ctx, newUser.ID, req.AppID, req.FromIP, req.UserAgent)
 		if err != nil {
 			return err
 		}
@@ -148,3 +191,205 @@ func (u *UserUsecaseImpl) Register(ctx context.Context, req dto.RegisterRequest)
 
 	return &out, err
 }
+
+// UpdateStatus changes the user's status and drops the user's cached sessions,
+// so a deactivated user is rejected on the next request.
+func (u *UserUsecaseImpl) UpdateStatus(ctx context.Context, userID uint, status string) error {
+	err := u.UpdateFields(ctx, userID, map[string]interface{}{
+		"status": status,
+	})
+	if err != nil {
+		return err
+	}
+
+	u.UserSessionUC.InvalidateSessionsByUserID(ctx, userID)
+	return nil
+}
+
+func (u *UserUsecaseImpl) SetPasswordPolicy(passwordPolicyUC usecase.PasswordPolicyUsecase) {
+	u.PasswordPolicyUC = passwordPolicyUC
+}
+
+func (u *UserUsecaseImpl) validatePassword(ctx context.Context, user *entity.User, password string) error {
+	if u.PasswordPolicyUC == nil {
+		return nil
+	}
+	return u.PasswordPolicyUC.Validate(ctx, user, password)
+}
+
+// hashPassword hashes the password, a hasher returning an empty hash fails too so it is never stored
+func (u *UserUsecaseImpl) hashPassword(password string) (string, error) {
+	hash, err := u.PasswordHasher.HashPassword(password)
+	if err != nil {
+		return "", err
+	}
+	if hash == "" {
+		return "", errors.New("failed to hash password")
+	}
+	return hash, nil
+}
+
+func (u *UserUsecaseImpl) rememberPassword(ctx context.Context, userID uint, passwordHash string) error {
+	if u.PasswordPolicyUC == nil {
+		return nil
+	}
+	return u.PasswordPolicyUC.Remember(ctx, userID, passwordHash)
+}
+
+func (u *UserUsecaseImpl) SetNotifier(notifier service.IdentifierChangeNotifier) {
+	u.Notifier = notifier
+}
+
+// ChangePassword checks the current password, sets the new one and revokes the other sessions,
+// so a stolen session does not survive the password change.
+func (u *UserUsecaseImpl) ChangePassword(ctx context.Context, userID uint, currentToken string, req dto.ChangePasswordRequest) error {
+	// 1. find user
+	user, err := u.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("User not found")
+	}
+
+	// 2. verify current password
+	if !u.PasswordHasher.CheckPasswordHash(req.CurrentPassword, user.PasswordHash) {
+		return errors.New("Invalid current password")
+	}
+
+	// 3. check password policy
+	if err := u.validatePassword(ctx, user, req.Password); err != nil {
+		return err
+	}
+
+	// 4. update password
+	passwordHash, err := u.hashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+	return u.WithTransaction(ctx, func(ctx context.Context) error {
+		err := u.UpdateFields(ctx, userID, map[string]interface{}{
+			"password_hash": passwordHash,
+			"updated_by":    userID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := u.rememberPassword(ctx, userID, passwordHash); err != nil {
+			return err
+		}
+
+		// 5. revoke other sessions
+		return u.UserSessionUC.RevokeOtherSessions(ctx, userID, currentToken)
+	})
+}
+
+// changeIdentifierTrxPrefix binds the OTP of an identifier change to the requesting user
+func changeIdentifierTrxPrefix(userID uint) string {
+	return fmt.Sprintf("chg%d-", userID)
+}
+
+// identifierColumn returns the users column holding the identifier of the channel
+func identifierColumn(channel string) string {
+	if channel == "email" {
+		return "email"
+	}
+	return "handphone"
+}
+
+// RequestChangeIdentifier checks the new email or handphone is free and sends it an OTP.
+func (u *UserUsecaseImpl) RequestChangeIdentifier(ctx context.Context, userID uint, req dto.ChangeIdentifierRequest) (*dto.ChangeIdentifierResponse, error) {
+	// 1. check if identifier is already registered
+	if err := u.checkIdentifierFree(ctx, req.Channel, req.Identifier); err != nil {
+		return nil, err
+	}
+
+	// 2. send OTP to the new identifier
+	otpResponse, err := u.OtpUC.GenerateOTP(ctx, dto.OtpRequest{
+		Channel:    req.Channel,
+		Identifier: req.Identifier,
+		TrxID:      changeIdentifierTrxPrefix(userID) + pkg_helpers.GenerateRandomString(16),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ChangeIdentifierResponse{
+		Identifier: otpResponse.Identifier,
+		TrxID:      otpResponse.TrxID,
+		WaUrl:      otpResponse.WaUrl,
+	}, nil
+}
+
+// ConfirmChangeIdentifier verifies the OTP of the new identifier, replaces the old one and notifies it.
+func (u *UserUsecaseImpl) ConfirmChangeIdentifier(ctx context.Context, userID uint, req dto.ChangeIdentifierConfirmRequest) error {
+	// 1. the OTP must have been requested by this user
+	if !strings.HasPrefix(req.TrxID, changeIdentifierTrxPrefix(userID)) {
+		return errors.New("Invalid OTP or expired")
+	}
+
+	// 2. verify OTP of the new identifier
+	if err := u.OtpUC.VerifyOtp(ctx, req.Identifier, req.TrxID, req.PinCode); err != nil {
+		return err
+	}
+
+	// 3. find user and check again the identifier is still free
+	user, err := u.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("User not found")
+	}
+	if err := u.checkIdentifierFree(ctx, req.Channel, req.Identifier); err != nil {
+		return err
+	}
+
+	oldIdentifier := user.Handphone
+	if req.Channel == "email" {
+		oldIdentifier = user.Email
+	}
+
+	// 4. update identifier, the username follows the email
+	fields := map[string]interface{}{
+		identifierColumn(req.Channel): req.Identifier,
+		"updated_by":                  userID,
+	}
+	if req.Channel == "email" && user.Username == user.Email {
+		fields["username"] = req.Identifier
+	}
+	if err := u.UpdateFields(ctx, userID, fields); err != nil {
+		return err
+	}
+
+	// 5. revoke otp and notify the old identifier
+	u.OtpUC.Revoke(ctx, req.Identifier, req.TrxID)
+	if u.Notifier != nil && oldIdentifier != "" {
+		u.Notifier.NotifyIdentifierChanged(req.Channel, oldIdentifier, req.Identifier)
+	}
+
+	return nil
+}
+
+func (u *UserUsecaseImpl) checkIdentifierFree(ctx context.Context, channel, identifier string) error {
+	if channel == "email" && !pkg_helpers.IsValidEmail(identifier) {
+		return errors.New("invalid email format")
+	}
+	if channel != "email" && !pkg_helpers.IsValidPhoneNumber(identifier) {
+		return errors.New("invalid phone number format")
+	}
+
+	existingUser, err := u.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
+		return d.Where(identifierColumn(channel)+" = ?", identifier)
+	})
+	if err != nil {
+		return err
+	}
+	if existingUser != nil {
+		if channel == "email" {
+			return errors.New("Email is already registered")
+		}
+		return errors.New("Handphone is already registered")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/auth/domain/entity"
+	dom_usecase "github.com/budimanlai/go-core/auth/domain/usecase"
+	"github.com/budimanlai/go-core/auth/dto"
+	"github.com/budimanlai/go-core/auth/service"
+	"github.com/budimanlai/go-core/auth/usecase"
+	"github.com/budimanlai/go-core/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeOtpUsecase accepts every pin code and counts the verifications
+type fakeOtpUsecase struct {
+	dom_usecase.OtpUsecase
+	verified int
+}
+
+func (u *fakeOtpUsecase) VerifyOtp(ctx context.Context, phoneNumber, trxID, pinCode string) error {
+	u.verified++
+	return nil
+}
+
+func (u *fakeOtpUsecase) Revoke(ctx context.Context, identifier string, trxID string) {}
+
+func TestUserUsecase_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	passwordHash, err := hasher.HashPassword("secret")
+	require.NoError(t, err)
+
+	// the session revocation runs for real, its UPDATE is captured from the dry run
+	db := openTxDB(t)
+	var revocations []*gorm.Statement
+	err = db.Callback().Update().After("gorm:update").Register("test:capture", func(d *gorm.DB) {
+		if d.Statement.Table == "user_sessions" {
+			revocations = append(revocations, d.Statement)
+		}
+	})
+	require.NoError(t, err)
+
+	users := &fakeUserRepo{users: []*entity.User{{ID: 1, Email: "john@example.com", PasswordHash: passwordHash}}}
+	sessionUC := usecase.NewUserSessionUsecaseImpl(db, &fakeSessionRepo{}, users, nil)
+	store := service.NewSessionStoreMemoryImpl(time.Minute)
+	sessionUC.SetSessionStore(store)
+	uc := usecase.NewUserUsecaseImpl(db, users, nil, sessionUC)
+	uc.SetPasswordHasher(hasher)
+
+	t.Run("wrong current password", func(t *testing.T) {
+		err := uc.ChangePassword(ctx, 1, "current-token", dto.ChangePasswordRequest{CurrentPassword: "wrong", Password: "new-secret"})
+		assert.EqualError(t, err, "Invalid current password")
+		assert.Empty(t, users.updates)
+		assert.Empty(t, revocations, "sessions are kept")
+	})
+
+	t.Run("revokes the other sessions and keeps the current one", func(t *testing.T) {
+		require.NoError(t, store.Set(ctx, &entity.UserSession{UserID: 1, Tokens: "other-token"}))
+
+		err := uc.ChangePassword(ctx, 1, "current-token", dto.ChangePasswordRequest{CurrentPassword: "secret", Password: "new-secret"})
+		require.NoError(t, err)
+
+		require.Len(t, users.updates, 1)
+		assert.True(t, hasher.CheckPasswordHash("new-secret", users.updates[0]["password_hash"].(string)))
+
+		require.Len(t, revocations, 1)
+		assert.Contains(t, revocations[0].SQL.String(), "tokens <> ?")
+		assert.Equal(t, uint(1), revocations[0].Vars[1])
+		assert.Equal(t, "current-token", revocations[0].Vars[2])
+
+		cached, err := store.Get(ctx, "other-token")
+		require.NoError(t, err)
+		assert.Nil(t, cached, "cached sessions are dropped")
+	})
+}
+
+func TestUserUsecase_ConfirmChangeIdentifier(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeUserRepo{users: []*entity.User{
+		{ID: 1, Email: "john@example.com", Username: "john@example.com"},
+		{ID: 12, Email: "jane@example.com"},
+	}}
+	otpUC := &fakeOtpUsecase{}
+	uc := usecase.NewUserUsecaseImpl(openTxDB(t), users, otpUC, nil)
+
+	// the OTP of another user is refused before its pin code is checked, "chg1-" does not match "chg12-"
+	for _, trxID := range []string{"chg12-abc", "chg2-abc", "abc"} {
+		err := uc.ConfirmChangeIdentifier(ctx, 1, dto.ChangeIdentifierConfirmRequest{
+			Channel: "email", Identifier: "john@new.example.com", TrxID: trxID, PinCode: "123456",
+		})
+		assert.EqualError(t, err, "Invalid OTP or expired", trxID)
+	}
+	assert.Zero(t, otpUC.verified)
+	assert.Empty(t, users.updates)
+
+	err := uc.ConfirmChangeIdentifier(ctx, 1, dto.ChangeIdentifierConfirmRequest{
+		Channel: "email", Identifier: "john@new.example.com", TrxID: "chg1-abc", PinCode: "123456",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, otpUC.verified)
+	require.Len(t, users.updates, 1)
+	assert.Equal(t, "john@new.example.com", users.updates[0]["email"])
+	assert.Equal(t, "john@new.example.com", users.updates[0]["username"], "the username follows the email")
+}
@@ -2,6 +2,7 @@ package base
 
 import (
 	"context"
+	"iter"
 
 	"gorm.io/gorm"
 )
@@ -19,6 +20,9 @@ type PaginationResult[T any] struct {
 
 type BaseRepository[E any, M any] interface {
 	GetDB(ctx context.Context) *gorm.DB
+	// GetReadDB returns a replica for reads, or the primary inside a transaction, right after a write
+	// of the request, or when no replica is configured
+	GetReadDB(ctx context.Context) *gorm.DB
 
 	Create(ctx context.Context, entity *E) error
 	Update(ctx context.Context, entity *E) error
@@ -37,4 +41,15 @@ type BaseRepository[E any, M any] interface {
 	// Batch operations
 	CreateBatch(ctx context.Context, entities []*E) error
 	DeleteBatch(ctx context.Context, ids []any) error
+
+	// Upsert inserts the entity, or updates updateColumns (all columns when empty) of the row conflicting on
+	// conflictColumns. MySQL uses the unique keys of the table instead of conflictColumns.
+	Upsert(ctx context.Context, entity *E, conflictColumns, updateColumns []string) (int64, error)
+	UpsertBatch(ctx context.Context, entities []*E, conflictColumns, updateColumns []string) (int64, error)
+	// UpdateWhere updates the fields of every row matching the scopes, at least one scope is required
+	UpdateWhere(ctx context.Context, fields map[string]interface{}, scopes ...func(*gorm.DB) *gorm.DB) (int64, error)
+
+	// Large result sets, read in primary key order one batch at a time
+	Each(ctx context.Context, batchSize int, fn func(batch []E) error, scopes ...func(*gorm.DB) *gorm.DB) error
+	Stream(ctx context.Context, batchSize int, scopes ...func(*gorm.DB) *gorm.DB) iter.Seq2[*E, error]
 }
@@ -5,13 +5,20 @@ import (
 	"errors"
 	"math"
 
-	"github.com/jinzhu/copier"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrConflictColumnsRequired = errors.New("conflict columns are required")
+	ErrScopeRequired           = errors.New("at least one scope is required")
 )
 
 // Implementasi Struct
 type BaseRepositoryImpl[E any, M any] struct {
-	db *gorm.DB
+	db       *gorm.DB
+	resolver *dbResolver
+	mapper   Mapper[E, M]
 }
 
 // InjectTx: Memasukkan object Transaction ke dalam Context
@@ -30,12 +37,16 @@ func ExtractTx(ctx context.Context) *gorm.DB {
 // Constructor Public
 func NewGormRepository[E any, M any](db *gorm.DB) BaseRepository[E, M] {
 	return &BaseRepositoryImpl[E, M]{
-		db: db,
+		db:     db,
+		mapper: MapperFor[E, M](),
 	}
 }
 
-// Helper internal untuk memilih DB mana yang dipakai
+// Helper internal untuk memilih DB mana yang dipakai.
+// Selalu primary, dan dicatat sebagai write supaya read berikutnya di request ini tidak ke replica.
 func (r *BaseRepositoryImpl[E, M]) GetDB(ctx context.Context) *gorm.DB {
+	markWrite(ctx)
+
 	// 1. Cek apakah ada Transaksi "titipan" di context?
 	tx := ExtractTx(ctx)
 	if tx != nil {
@@ -47,9 +58,32 @@ func (r *BaseRepositoryImpl[E, M]) GetDB(ctx context.Context) *gorm.DB {
 	return r.db.WithContext(ctx)
 }
 
+func (r *BaseRepositoryImpl[E, M]) GetReadDB(ctx context.Context) *gorm.DB {
+	if tx := ExtractTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	if r.resolver == nil {
+		return r.db.WithContext(ctx)
+	}
+	return r.resolver.reader(ctx).WithContext(ctx)
+}
+
+// scopedReadDB: GetReadDB yang dibatasi ke tenant di context
+func (r *BaseRepositoryImpl[E, M]) scopedReadDB(ctx context.Context) (*gorm.DB, error) {
+	return tenantScope[M](ctx, r.GetReadDB(ctx))
+}
+
+// scopedDB: GetDB yang dibatasi ke tenant di context, untuk model yang punya TenantID
+func (r *BaseRepositoryImpl[E, M]) scopedDB(ctx context.Context) (*gorm.DB, error) {
+	return tenantScope[M](ctx, r.GetDB(ctx))
+}
+
 func (r *BaseRepositoryImpl[E, M]) Create(ctx context.Context, entity *E) error {
 	var model M
-	if err := copier.Copy(&model, entity); err != nil {
+	if err := r.mapper.ToModel(entity, &model); err != nil {
+		return err
+	}
+	if err := stampTenant(ctx, &model); err != nil {
 		return err
 	}
 
@@ -57,7 +91,7 @@ func (r *BaseRepositoryImpl[E, M]) Create(ctx context.Context, entity *E) error
 		return err
 	}
 
-	if err := copier.Copy(entity, &model); err != nil {
+	if err := r.mapper.ToEntity(&model, entity); err != nil {
 		return err
 	}
 
@@ -68,7 +102,10 @@ func (r *BaseRepositoryImpl[E, M]) FindByID(ctx context.Context, id any, scopes
 	var model M
 
 	// 1. Ambil DB dasar
-	db := r.GetDB(ctx)
+	db, err := r.scopedReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// 2. Apply Scopes (misal: Preload("Profile"))
 	for _, scope := range scopes {
@@ -76,7 +113,7 @@ func (r *BaseRepositoryImpl[E, M]) FindByID(ctx context.Context, id any, scopes
 	}
 
 	// 3. Eksekusi
-	err := db.First(&model, id).Error
+	err = db.First(&model, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -85,7 +122,7 @@ func (r *BaseRepositoryImpl[E, M]) FindByID(ctx context.Context, id any, scopes
 	}
 
 	var entity E
-	if err := copier.Copy(&entity, &model); err != nil {
+	if err := r.mapper.ToEntity(&model, &entity); err != nil {
 		return nil, err
 	}
 
@@ -93,20 +130,44 @@ func (r *BaseRepositoryImpl[E, M]) FindByID(ctx context.Context, id any, scopes
 }
 
 func (r *BaseRepositoryImpl[E, M]) UpdateFields(ctx context.Context, id any, fields map[string]interface{}) error {
-	return r.GetDB(ctx).Model(new(M)).Where("id = ?", id).Updates(fields).Error
+	if err := checkTenantFields[M](ctx, fields); err != nil {
+		return err
+	}
+	db, err := r.scopedDB(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(new(M)).Where("id = ?", id).Updates(fields).Error
 }
 
 func (r *BaseRepositoryImpl[E, M]) Update(ctx context.Context, entity *E) error {
 	var model M
-	if err := copier.Copy(&model, entity); err != nil {
+	if err := r.mapper.ToModel(entity, &model); err != nil {
+		return err
+	}
+	if !isTenantScoped[M]() {
+		return r.GetDB(ctx).Save(&model).Error
+	}
+
+	// Save akan INSERT kalau UPDATE tidak kena row, jadi row tenant lain bisa tertimpa.
+	// Untuk model tenant, update semua field dengan filter tenant saja.
+	if err := stampTenant(ctx, &model); err != nil {
 		return err
 	}
-	return r.GetDB(ctx).Save(&model).Error
+	db, err := r.scopedDB(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(&model).Select("*").Updates(&model).Error
 }
 
 func (r *BaseRepositoryImpl[E, M]) Delete(ctx context.Context, id any) error {
 	var model M
-	return r.GetDB(ctx).Delete(&model, id).Error
+	db, err := r.scopedDB(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Delete(&model, id).Error
 }
 
 // 5. LIST with Pagination
@@ -126,7 +187,11 @@ func (r *BaseRepositoryImpl[E, M]) FindAll(ctx context.Context, page, limit int,
 	}
 
 	// Mulai build query
-	db := r.GetDB(ctx).Model(new(M))
+	db, err := r.scopedReadDB(ctx)
+	if err != nil {
+		return PaginationResult[E]{}, err
+	}
+	db = db.Model(new(M))
 
 	// Apply Filter Dinamis (Scopes) SEBELUM count
 	for _, scope := range scopes {
@@ -148,9 +213,8 @@ func (r *BaseRepositoryImpl[E, M]) FindAll(ctx context.Context, page, limit int,
 		return PaginationResult[E]{}, err
 	}
 
-	var entities []E
-	// Copier pintar, dia bisa copy slice ke slice otomatis
-	if err := copier.Copy(&entities, &models); err != nil {
+	entities, err := r.toEntities(models)
+	if err != nil {
 		return PaginationResult[E]{}, err
 	}
 
@@ -168,13 +232,21 @@ func (r *BaseRepositoryImpl[E, M]) FindAll(ctx context.Context, page, limit int,
 
 func (r *BaseRepositoryImpl[E, M]) Restore(ctx context.Context, id any) error {
 	var models M
-	return r.GetDB(ctx).Unscoped().Model(&models).
+	db, err := r.scopedDB(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Model(&models).
 		Where("id = ?", id).Update("deleted_at", nil).Error
 }
 
 func (r *BaseRepositoryImpl[E, M]) ForceDelete(ctx context.Context, id any) error {
 	var models M
-	return r.GetDB(ctx).Unscoped().Delete(&models, id).Error
+	db, err := r.scopedDB(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Delete(&models, id).Error
 }
 
 // FindOne: Find single entity by any condition using scopes
@@ -182,7 +254,10 @@ func (r *BaseRepositoryImpl[E, M]) FindOne(ctx context.Context, scopes ...func(*
 	var models M
 
 	// Build query
-	db := r.GetDB(ctx)
+	db, err := r.scopedReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Apply scopes (e.g., Where conditions, Preload, etc.)
 	for _, scope := range scopes {
@@ -190,7 +265,7 @@ func (r *BaseRepositoryImpl[E, M]) FindOne(ctx context.Context, scopes ...func(*
 	}
 
 	// Execute
-	err := db.First(&models).Error
+	err = db.First(&models).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -199,8 +274,7 @@ func (r *BaseRepositoryImpl[E, M]) FindOne(ctx context.Context, scopes ...func(*
 	}
 
 	var entities E
-	// Copier pintar, dia bisa copy slice ke slice otomatis
-	if err := copier.Copy(&entities, &models); err != nil {
+	if err := r.mapper.ToEntity(&models, &entities); err != nil {
 		return nil, err
 	}
 	return &entities, nil
@@ -213,8 +287,8 @@ func (r *BaseRepositoryImpl[E, M]) CreateBatch(ctx context.Context, entities []*
 	}
 
 	// Convert entities to models
-	var models []M
-	if err := copier.Copy(&models, &entities); err != nil {
+	models, err := r.toModels(ctx, entities)
+	if err != nil {
 		return err
 	}
 
@@ -225,7 +299,7 @@ func (r *BaseRepositoryImpl[E, M]) CreateBatch(ctx context.Context, entities []*
 	}
 
 	// Copy back IDs to entities
-	if err := copier.Copy(&entities, &models); err != nil {
+	if err := r.copyBack(models, entities); err != nil {
 		return err
 	}
 
@@ -239,8 +313,12 @@ func (r *BaseRepositoryImpl[E, M]) DeleteBatch(ctx context.Context, ids []any) e
 	}
 
 	var model M
+	db, err := r.scopedDB(ctx)
+	if err != nil {
+		return err
+	}
 	// DELETE FROM table WHERE id IN (?, ?, ?)
-	return r.GetDB(ctx).Delete(&model, ids).Error
+	return db.Delete(&model, ids).Error
 }
 
 // Count: Count entities with optional filters
@@ -248,7 +326,11 @@ func (r *BaseRepositoryImpl[E, M]) Count(ctx context.Context, scopes ...func(*go
 	var count int64
 
 	// Build query
-	db := r.GetDB(ctx).Model(new(M))
+	db, err := r.scopedReadDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+	db = db.Model(new(M))
 
 	// Apply scopes (filters)
 	for _, scope := range scopes {
@@ -256,6 +338,120 @@ func (r *BaseRepositoryImpl[E, M]) Count(ctx context.Context, scopes ...func(*go
 	}
 
 	// Execute count
-	err := db.Session(&gorm.Session{}).Limit(-1).Offset(-1).Count(&count).Error
+	err = db.Session(&gorm.Session{}).Limit(-1).Offset(-1).Count(&count).Error
 	return count, err
 }
+
+// Upsert: INSERT ... ON CONFLICT DO UPDATE (PostgreSQL, SQLite) atau ON DUPLICATE KEY UPDATE (MySQL)
+func (r *BaseRepositoryImpl[E, M]) Upsert(ctx context.Context, entity *E, conflictColumns, updateColumns []string) (int64, error) {
+	return r.UpsertBatch(ctx, []*E{entity}, conflictColumns, updateColumns)
+}
+
+// UpsertBatch: Upsert banyak entity, per 100 row seperti CreateBatch.
+// ID yang dikembalikan untuk row yang di-update hanya bisa diandalkan di PostgreSQL dan SQLite.
+func (r *BaseRepositoryImpl[E, M]) UpsertBatch(ctx context.Context, entities []*E, conflictColumns, updateColumns []string) (int64, error) {
+	if len(entities) == 0 {
+		return 0, nil
+	}
+
+	onConflict, err := upsertClause[M](conflictColumns, updateColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	models, err := r.toModels(ctx, entities)
+	if err != nil {
+		return 0, err
+	}
+
+	result := r.GetDB(ctx).Clauses(onConflict).CreateInBatches(&models, 100)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	// Copy back IDs to entities
+	if err := r.copyBack(models, entities); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected, nil
+}
+
+// upsertClause builds the conflict clause. A tenant row only updates a row of its own tenant, MySQL has no
+// WHERE on ON DUPLICATE KEY UPDATE so its unique keys must include tenant_id.
+func upsertClause[M any](conflictColumns, updateColumns []string) (clause.OnConflict, error) {
+	if len(conflictColumns) == 0 {
+		return clause.OnConflict{}, ErrConflictColumnsRequired
+	}
+
+	onConflict := clause.OnConflict{UpdateAll: len(updateColumns) == 0}
+	for _, name := range conflictColumns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: name})
+	}
+	if len(updateColumns) > 0 {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
+	}
+
+	if isTenantScoped[M]() {
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: TenantColumn},
+			Value:  clause.Column{Table: "excluded", Name: TenantColumn},
+		}}}
+	}
+	return onConflict, nil
+}
+
+// UpdateWhere: UPDATE table SET ... WHERE <scopes>, tanpa scope ditolak supaya tidak meng-update semua row
+func (r *BaseRepositoryImpl[E, M]) UpdateWhere(ctx context.Context, fields map[string]interface{}, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	if len(scopes) == 0 {
+		return 0, ErrScopeRequired
+	}
+	if err := checkTenantFields[M](ctx, fields); err != nil {
+		return 0, err
+	}
+
+	db, err := r.scopedDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+	db = db.Model(new(M))
+	for _, scope := range scopes {
+		db = scope(db)
+	}
+
+	result := db.Updates(fields)
+	return result.RowsAffected, result.Error
+}
+
+// toModels: Konversi entity ke model untuk insert, sekaligus stamp tenant
+func (r *BaseRepositoryImpl[E, M]) toModels(ctx context.Context, entities []*E) ([]M, error) {
+	models := make([]M, len(entities))
+	for i, entity := range entities {
+		if err := r.mapper.ToModel(entity, &models[i]); err != nil {
+			return nil, err
+		}
+		if err := stampTenant(ctx, &models[i]); err != nil {
+			return nil, err
+		}
+	}
+	return models, nil
+}
+
+func (r *BaseRepositoryImpl[E, M]) toEntities(models []M) ([]E, error) {
+	entities := make([]E, len(models))
+	for i := range models {
+		if err := r.mapper.ToEntity(&models[i], &entities[i]); err != nil {
+			return nil, err
+		}
+	}
+	return entities, nil
+}
+
+// copyBack: Salin ID dan default dari database ke entity asal setelah insert
+func (r *BaseRepositoryImpl[E, M]) copyBack(models []M, entities []*E) error {
+	for i := range models {
+		if err := r.mapper.ToEntity(&models[i], entities[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBaseRepository_Upsert(t *testing.T) {
+	db, sqls := dryRunDB(t)
+	repo := base.NewGormRepository[country, countryModel](db)
+	ctx := context.Background()
+
+	_, err := repo.Upsert(ctx, &country{Name: "Indonesia"}, nil, nil)
+	assert.ErrorIs(t, err, base.ErrConflictColumnsRequired)
+
+	*sqls = nil
+	_, err = repo.UpsertBatch(ctx, []*country{{ID: 1, Name: "Indonesia"}, {ID: 2, Name: "Malaysia"}},
+		[]string{"id"}, []string{"name"})
+	require.NoError(t, err)
+	require.Len(t, *sqls, 1)
+	assert.Contains(t, (*sqls)[0], "ON CONFLICT (`id`) DO UPDATE SET `name`=`excluded`.`name`")
+
+	t.Run("Tenant rows only update their tenant", func(t *testing.T) {
+		repo := base.NewGormRepository[order, orderModel](db)
+		entity := order{ID: 1, Total: 10}
+
+		*sqls = nil
+		_, err := repo.Upsert(base.WithTenant(ctx, 3), &entity, []string{"id"}, nil)
+		require.NoError(t, err)
+		require.Len(t, *sqls, 1)
+		assert.Contains(t, (*sqls)[0], "WHERE `orders`.`tenant_id` = `excluded`.`tenant_id`")
+		assert.Equal(t, uint(3), entity.TenantID)
+	})
+}
+
+func TestBaseRepository_UpdateWhere(t *testing.T) {
+	db, sqls := dryRunDB(t)
+	repo := base.NewGormRepository[country, countryModel](db)
+	ctx := context.Background()
+
+	_, err := repo.UpdateWhere(ctx, map[string]interface{}{"name": "x"})
+	assert.ErrorIs(t, err, base.ErrScopeRequired)
+
+	*sqls = nil
+	_, err = repo.UpdateWhere(ctx, map[string]interface{}{"name": "Indonesia"}, func(db *gorm.DB) *gorm.DB {
+		return db.Where("name = ?", "indonesia")
+	})
+	require.NoError(t, err)
+	require.Len(t, *sqls, 1)
+	assert.Contains(t, (*sqls)[0], "UPDATE `countries` SET `name`=? WHERE name = ?")
+
+	t.Run("Tenant rows stay in their tenant", func(t *testing.T) {
+		repo := base.NewGormRepository[order, orderModel](db)
+		byTotal := func(db *gorm.DB) *gorm.DB { return db.Where("total = ?", 0) }
+
+		*sqls = nil
+		_, err := repo.UpdateWhere(base.WithTenant(ctx, 3), map[string]interface{}{"tenant_id": 4}, byTotal)
+		assert.ErrorIs(t, err, base.ErrTenantMismatch)
+		assert.Empty(t, *sqls)
+
+		_, err = repo.UpdateWhere(base.WithoutTenant(base.WithTenant(ctx, 3)), map[string]interface{}{"tenant_id": 4}, byTotal)
+		require.NoError(t, err)
+		require.Len(t, *sqls, 1)
+	})
+}
+
+type currency struct {
+	Code string
+	Name string
+}
+
+type currencyModel struct {
+	Code string `gorm:"primaryKey"`
+	Name string
+}
+
+func (currencyModel) TableName() string { return "currencies" }
+
+func TestCachedRepository_UpdateWhere(t *testing.T) {
+	db, sqls := dryRunDB(t)
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer rdb.Close()
+	repo := base.NewRepository[currency, currencyModel](base.NewFactory(db, base.RepoConfig{EnableCache: true, RedisClient: rdb}))
+
+	_, err := repo.UpdateWhere(context.Background(), map[string]interface{}{"name": "Rupiah"}, func(db *gorm.DB) *gorm.DB {
+		return db.Where("name = ?", "rupiah")
+	})
+	require.NoError(t, err)
+	require.Len(t, *sqls, 2)
+
+	// the changed rows are found by their primary key to drop their cache
+	assert.Contains(t, (*sqls)[0], "SELECT `code` FROM `currencies` WHERE name = ?")
+	assert.Contains(t, (*sqls)[1], "UPDATE `currencies` SET `name`=? WHERE name = ?")
+}
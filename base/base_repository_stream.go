@@ -0,0 +1,106 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultStreamBatchSize adalah jumlah row per query untuk Each dan Stream
+const DefaultStreamBatchSize = 500
+
+// errStopStream ends Each when the consumer of Stream breaks out of the loop
+var errStopStream = errors.New("stream stopped")
+
+// primaryField: Primary key model M dari schema GORM
+func primaryField[M any](db *gorm.DB) (*schema.Field, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(M)); err != nil {
+		return nil, err
+	}
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, errors.New("model has no primary key")
+	}
+	return stmt.Schema.PrioritizedPrimaryField, nil
+}
+
+// Each: Baca semua row yang cocok dengan scopes per batch, memakai keyset (WHERE pk > last ORDER BY pk)
+// bukan OFFSET, jadi setiap batch sama cepatnya. Scopes tidak boleh menambah Order, Limit atau Offset.
+func (r *BaseRepositoryImpl[E, M]) Each(ctx context.Context, batchSize int, fn func(batch []E) error, scopes ...func(*gorm.DB) *gorm.DB) error {
+	if batchSize <= 0 {
+		batchSize = DefaultStreamBatchSize
+	}
+
+	db, err := r.scopedReadDB(ctx)
+	if err != nil {
+		return err
+	}
+	db = db.Model(new(M))
+	for _, scope := range scopes {
+		db = scope(db)
+	}
+
+	pk, err := primaryField[M](db)
+	if err != nil {
+		return err
+	}
+	pkColumn := clause.Column{Table: clause.CurrentTable, Name: pk.DBName}
+
+	var last interface{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		query := db.Session(&gorm.Session{})
+		if last != nil {
+			query = query.Where(clause.Gt{Column: pkColumn, Value: last})
+		}
+
+		var models []M
+		if err := query.Order(clause.OrderByColumn{Column: pkColumn}).Limit(batchSize).Find(&models).Error; err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+
+		entities, err := r.toEntities(models)
+		if err != nil {
+			return err
+		}
+		if err := fn(entities); err != nil {
+			return err
+		}
+		if len(models) < batchSize {
+			return nil
+		}
+
+		last, _ = pk.ValueOf(ctx, reflect.ValueOf(&models[len(models)-1]).Elem())
+	}
+}
+
+// Stream: Iterator per entity di atas Each, memori tetap satu batch.
+//
+//	for user, err := range repo.Stream(ctx, 1000) { ... }
+func (r *BaseRepositoryImpl[E, M]) Stream(ctx context.Context, batchSize int, scopes ...func(*gorm.DB) *gorm.DB) iter.Seq2[*E, error] {
+	return func(yield func(*E, error) bool) {
+		err := r.Each(ctx, batchSize, func(batch []E) error {
+			for i := range batch {
+				if !yield(&batch[i], nil) {
+					return errStopStream
+				}
+			}
+			return nil
+		}, scopes...)
+
+		if err != nil && !errors.Is(err, errStopStream) {
+			yield(nil, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package base_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeCountries answers the keyset queries of Each from ids 1 to total, the vars are [last id,] limit
+func fakeCountries(t *testing.T, db *gorm.DB, total int) {
+	err := db.Callback().Query().After("gorm:query").Register("fake", func(db *gorm.DB) {
+		dest, ok := db.Statement.Dest.(*[]countryModel)
+		if !ok {
+			return
+		}
+
+		vars := db.Statement.Vars
+		limit := vars[len(vars)-1].(int)
+		last := uint(0)
+		if len(vars) == 2 {
+			last = vars[0].(uint)
+		}
+		for id := last + 1; id <= uint(total) && len(*dest) < limit; id++ {
+			*dest = append(*dest, countryModel{ID: id})
+		}
+	})
+	require.NoError(t, err)
+}
+
+func TestBaseRepository_Each(t *testing.T) {
+	db, sqls := dryRunDB(t)
+	fakeCountries(t, db, 7)
+	repo := base.NewGormRepository[country, countryModel](db)
+
+	var sizes []int
+	var ids []uint
+	err := repo.Each(context.Background(), 3, func(batch []country) error {
+		sizes = append(sizes, len(batch))
+		for _, c := range batch {
+			ids = append(ids, c.ID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 3, 1}, sizes)
+	assert.Equal(t, []uint{1, 2, 3, 4, 5, 6, 7}, ids)
+	assert.Contains(t, (*sqls)[1], "WHERE `countries`.`id` > ? ORDER BY `countries`.`id` LIMIT ?")
+
+	t.Run("Callback error", func(t *testing.T) {
+		stop := errors.New("stop")
+		err := repo.Each(context.Background(), 3, func([]country) error { return stop })
+		assert.ErrorIs(t, err, stop)
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := repo.Each(ctx, 3, func([]country) error {
+			calls++
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestBaseRepository_Stream(t *testing.T) {
+	db, _ := dryRunDB(t)
+	fakeCountries(t, db, 5)
+	repo := base.NewGormRepository[country, countryModel](db)
+
+	var ids []uint
+	for c, err := range repo.Stream(context.Background(), 2) {
+		require.NoError(t, err)
+		ids = append(ids, c.ID)
+		if len(ids) == 4 {
+			break
+		}
+	}
+	assert.Equal(t, []uint{1, 2, 3, 4}, ids)
+
+	t.Run("Errors are yielded", func(t *testing.T) {
+		repo := base.NewGormRepository[order, orderModel](db)
+		yields := 0
+		for o, err := range repo.Stream(context.Background(), 2) {
+			yields++
+			assert.Nil(t, o)
+			assert.ErrorIs(t, err, base.ErrTenantRequired)
+		}
+		assert.Equal(t, 1, yields)
+	})
+}
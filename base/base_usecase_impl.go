@@ -26,8 +26,13 @@ func (s *baseUseaseImpl[E]) GetDB() *gorm.DB {
 }
 
 // --- Transaction Helper ---
+// Transaction yang sudah ada di context dipakai ulang (savepoint), supaya usecase bisa saling memanggil
 func (s *baseUseaseImpl[E]) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	db := s.db
+	if tx := ExtractTx(ctx); tx != nil {
+		db = tx
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
 		txCtx := InjectTx(ctx, tx)
 		return fn(txCtx)
 	})
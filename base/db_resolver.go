@@ -0,0 +1,149 @@
+package base
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type primaryKey struct{}
+type writeTrackerKey struct{}
+
+const (
+	defaultStickyWindow        = 5 * time.Second
+	defaultHealthCheckInterval = 10 * time.Second
+	healthCheckTimeout         = 2 * time.Second
+)
+
+// writeTracker remembers the last write of a request, so its reads can stay on the primary
+type writeTracker struct {
+	last atomic.Int64
+}
+
+// UsePrimary: Paksa semua query dengan context ini ke primary
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// TrackWrites starts a request scope, reads after a write in this scope go to the primary for the sticky window
+func TrackWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writeTrackerKey{}, &writeTracker{})
+}
+
+// ReadYourWritesMiddleware tracks the writes of each request, set it before the handlers on apps with replicas
+func ReadYourWritesMiddleware(c *fiber.Ctx) error {
+	tracker := &writeTracker{}
+	c.Context().SetUserValue(writeTrackerKey{}, tracker)
+	c.SetUserContext(context.WithValue(c.UserContext(), writeTrackerKey{}, tracker))
+	return c.Next()
+}
+
+func markWrite(ctx context.Context) {
+	if tracker, ok := ctx.Value(writeTrackerKey{}).(*writeTracker); ok {
+		tracker.last.Store(time.Now().UnixNano())
+	}
+}
+
+// readsPrimary checks if the reads of ctx must see the primary
+func readsPrimary(ctx context.Context, window time.Duration) bool {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return true
+	}
+	tracker, ok := ctx.Value(writeTrackerKey{}).(*writeTracker)
+	if !ok {
+		return false
+	}
+	last := tracker.last.Load()
+	return last != 0 && time.Since(time.Unix(0, last)) < window
+}
+
+type replica struct {
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// dbResolver picks the connection of a read: a healthy replica in turn, or the primary when none is healthy
+type dbResolver struct {
+	primary  *gorm.DB
+	replicas []*replica
+	window   time.Duration
+	next     atomic.Uint64
+	stop     chan struct{}
+}
+
+func newDBResolver(primary *gorm.DB, replicas []*gorm.DB, window, interval time.Duration) *dbResolver {
+	if window <= 0 {
+		window = defaultStickyWindow
+	}
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+
+	r := &dbResolver{
+		primary: primary,
+		window:  window,
+		stop:    make(chan struct{}),
+	}
+	for _, db := range replicas {
+		rep := &replica{db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+
+	go r.run(interval)
+	return r
+}
+
+func (r *dbResolver) reader(ctx context.Context) *gorm.DB {
+	if readsPrimary(ctx, r.window) {
+		return r.primary
+	}
+
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep.db
+		}
+	}
+	return r.primary
+}
+
+func (r *dbResolver) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.checkHealth()
+		}
+	}
+}
+
+// checkHealth pings every replica, a failed ping takes it out of rotation until the next successful one
+func (r *dbResolver) checkHealth() {
+	for _, rep := range r.replicas {
+		rep.healthy.Store(ping(rep.db) == nil)
+	}
+}
+
+func ping(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+func (r *dbResolver) close() {
+	close(r.stop)
+}
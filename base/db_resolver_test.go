@@ -0,0 +1,78 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/tests"
+)
+
+// openDryRun opens a DB without a connection, its table prefix tells which DB ran a query
+func openDryRun(t *testing.T, name string) *gorm.DB {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{
+		DryRun:         true,
+		NamingStrategy: schema.NamingStrategy{TablePrefix: name},
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func nameOf(db *gorm.DB) string {
+	return db.NamingStrategy.(schema.NamingStrategy).TablePrefix
+}
+
+func TestFactory_ReadRouting(t *testing.T) {
+	primary, replica := openDryRun(t, "primary"), openDryRun(t, "replica")
+	factory := base.NewFactory(primary, base.RepoConfig{
+		Replicas:                   []*gorm.DB{replica},
+		ReplicaHealthCheckInterval: time.Hour,
+	})
+	defer factory.Close()
+	repo := base.NewRepository[country, countryModel](factory)
+
+	t.Run("Reads go to the replica", func(t *testing.T) {
+		assert.Equal(t, "replica", nameOf(repo.GetReadDB(context.Background())))
+		assert.Equal(t, "primary", nameOf(repo.GetDB(context.Background())))
+	})
+
+	t.Run("Forced primary", func(t *testing.T) {
+		ctx := base.UsePrimary(context.Background())
+		assert.Equal(t, "primary", nameOf(repo.GetReadDB(ctx)))
+	})
+
+	t.Run("Transactions stay on their connection", func(t *testing.T) {
+		ctx := base.InjectTx(context.Background(), openDryRun(t, "tx"))
+		assert.Equal(t, "tx", nameOf(repo.GetReadDB(ctx)))
+	})
+
+	t.Run("Reads after a write of the request", func(t *testing.T) {
+		ctx := base.TrackWrites(context.Background())
+		assert.Equal(t, "replica", nameOf(repo.GetReadDB(ctx)))
+
+		require.NoError(t, repo.Create(ctx, &country{Name: "Indonesia"}))
+		assert.Equal(t, "primary", nameOf(repo.GetReadDB(ctx)))
+
+		// writes of other requests do not matter
+		assert.Equal(t, "replica", nameOf(repo.GetReadDB(base.TrackWrites(context.Background()))))
+	})
+}
+
+func TestFactory_ReplicaFallback(t *testing.T) {
+	// a dry run DB has no connection, so its health check fails
+	primary, replica := openDryRun(t, "primary"), openDryRun(t, "replica")
+	factory := base.NewFactory(primary, base.RepoConfig{
+		Replicas:                   []*gorm.DB{replica},
+		ReplicaHealthCheckInterval: 10 * time.Millisecond,
+	})
+	defer factory.Close()
+
+	assert.Eventually(t, func() bool {
+		return nameOf(factory.ReadDB(context.Background())) == "primary"
+	}, time.Second, 10*time.Millisecond)
+}
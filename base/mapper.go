@@ -0,0 +1,54 @@
+package base
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/jinzhu/copier"
+)
+
+// Mapper converts between an entity and its model without reflection. Register one with RegisterMapper,
+// the repositories of the pair use it instead of copier. cmd/mappergen generates them.
+type Mapper[E any, M any] interface {
+	ToModel(entity *E, model *M) error
+	ToEntity(model *M, entity *E) error
+}
+
+type mapperKey struct {
+	entity reflect.Type
+	model  reflect.Type
+}
+
+var mappers sync.Map
+
+// RegisterMapper: Daftarkan mapper untuk pasangan E dan M, biasanya dari init() file hasil generate.
+// Repository yang sudah dibuat sebelumnya tetap memakai mapper lamanya.
+func RegisterMapper[E any, M any](mapper Mapper[E, M]) {
+	mappers.Store(mapperKeyOf[E, M](), mapper)
+}
+
+// MapperFor returns the registered mapper of the pair, or one based on copier
+func MapperFor[E any, M any]() Mapper[E, M] {
+	if mapper, ok := mappers.Load(mapperKeyOf[E, M]()); ok {
+		return mapper.(Mapper[E, M])
+	}
+	return copierMapper[E, M]{}
+}
+
+func mapperKeyOf[E any, M any]() mapperKey {
+	return mapperKey{
+		entity: reflect.TypeOf((*E)(nil)).Elem(),
+		model:  reflect.TypeOf((*M)(nil)).Elem(),
+	}
+}
+
+// copierMapper is the fallback, fields are matched by name at runtime
+type copierMapper[E any, M any] struct{}
+
+func (copierMapper[E, M]) ToModel(entity *E, model *M) error {
+	return copier.Copy(model, entity)
+}
+
+func (copierMapper[E, M]) ToEntity(model *M, entity *E) error {
+	return copier.Copy(entity, model)
+}
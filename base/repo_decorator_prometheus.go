@@ -2,6 +2,7 @@ package base
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -51,6 +52,10 @@ func (r *prometheusRepository[E, M]) GetDB(ctx context.Context) *gorm.DB {
 	return r.next.GetDB(ctx)
 }
 
+func (r *prometheusRepository[E, M]) GetReadDB(ctx context.Context) *gorm.DB {
+	return r.next.GetReadDB(ctx)
+}
+
 func (r *prometheusRepository[E, M]) Create(ctx context.Context, entity *E) error {
 	start := time.Now()
 	err := r.next.Create(ctx, entity)
@@ -152,3 +157,36 @@ func (r *prometheusRepository[E, M]) record(operation string, duration time.Dura
 		status,    // status: "success"/"error"
 	).Observe(duration.Seconds())
 }
+
+func (r *prometheusRepository[E, M]) Upsert(ctx context.Context, entity *E, conflictColumns, updateColumns []string) (int64, error) {
+	start := time.Now()
+	rows, err := r.next.Upsert(ctx, entity, conflictColumns, updateColumns)
+	r.record("Upsert", time.Since(start), err)
+	return rows, err
+}
+
+func (r *prometheusRepository[E, M]) UpsertBatch(ctx context.Context, entities []*E, conflictColumns, updateColumns []string) (int64, error) {
+	start := time.Now()
+	rows, err := r.next.UpsertBatch(ctx, entities, conflictColumns, updateColumns)
+	r.record("UpsertBatch", time.Since(start), err)
+	return rows, err
+}
+
+func (r *prometheusRepository[E, M]) UpdateWhere(ctx context.Context, fields map[string]interface{}, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	start := time.Now()
+	rows, err := r.next.UpdateWhere(ctx, fields, scopes...)
+	r.record("UpdateWhere", time.Since(start), err)
+	return rows, err
+}
+
+func (r *prometheusRepository[E, M]) Each(ctx context.Context, batchSize int, fn func(batch []E) error, scopes ...func(*gorm.DB) *gorm.DB) error {
+	start := time.Now()
+	err := r.next.Each(ctx, batchSize, fn, scopes...)
+	r.record("Each", time.Since(start), err)
+	return err
+}
+
+// Stream is not timed, the duration would mostly be the time spent by the caller in the loop
+func (r *prometheusRepository[E, M]) Stream(ctx context.Context, batchSize int, scopes ...func(*gorm.DB) *gorm.DB) iter.Seq2[*E, error] {
+	return r.next.Stream(ctx, batchSize, scopes...)
+}
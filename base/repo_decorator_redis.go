@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"reflect"
 	"time"
 
@@ -21,6 +22,45 @@ func (r *cachedRepository[E, M]) getKey(id any) string {
 	return fmt.Sprintf("cache:entity:%T:%v", *new(E), id)
 }
 
+// cacheKey: Key untuk model tenant menyertakan tenant ID, supaya tenant lain tidak bisa membaca cache-nya.
+// false kalau query tidak boleh pakai cache (lintas tenant atau tanpa tenant).
+func (r *cachedRepository[E, M]) cacheKey(ctx context.Context, id any) (string, bool) {
+	if !isTenantScoped[M]() {
+		return r.getKey(id), true
+	}
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok || IsCrossTenant(ctx) {
+		return "", false
+	}
+	return fmt.Sprintf("cache:entity:%T:tenant:%d:%v", *new(E), tenantID, id), true
+}
+
+// invalidate: Hapus cache untuk ID yang berubah. Write lintas tenant tidak tahu tenant row-nya,
+// jadi key semua tenant dicari dengan SCAN.
+func (r *cachedRepository[E, M]) invalidate(ctx context.Context, ids ...any) {
+	if len(ids) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if key, ok := r.cacheKey(ctx, id); ok {
+			keys = append(keys, key)
+			continue
+		}
+
+		pattern := fmt.Sprintf("cache:entity:%T:tenant:*:%v", *new(E), id)
+		iter := r.rdb.Scan(context.Background(), 0, pattern, 100).Iterator()
+		for iter.Next(context.Background()) {
+			keys = append(keys, iter.Val())
+		}
+	}
+
+	if len(keys) > 0 {
+		r.rdb.Del(context.Background(), keys...)
+	}
+}
+
 // Helper untuk mengambil ID dari Generic Struct menggunakan Reflection
 func (r *cachedRepository[E, M]) getIDFromEntity(entity any) (any, bool) {
 	val := reflect.ValueOf(entity)
@@ -46,6 +86,10 @@ func (r *cachedRepository[E, M]) GetDB(ctx context.Context) *gorm.DB {
 	return r.next.GetDB(ctx)
 }
 
+func (r *cachedRepository[E, M]) GetReadDB(ctx context.Context) *gorm.DB {
+	return r.next.GetReadDB(ctx)
+}
+
 // PERBAIKAN: Tambahkan parameter scopes ...func
 func (r *cachedRepository[E, M]) FindByID(ctx context.Context, id any, scopes ...func(*gorm.DB) *gorm.DB) (*E, error) {
 	// 1. SAFETY CHECK: Jika ada scopes (filter/preload), JANGAN pakai cache.
@@ -56,7 +100,10 @@ func (r *cachedRepository[E, M]) FindByID(ctx context.Context, id any, scopes ..
 	}
 
 	// 2. Logic Cache Standar (Hanya jalan kalau query polos by ID)
-	key := r.getKey(id)
+	key, ok := r.cacheKey(ctx, id)
+	if !ok {
+		return r.next.FindByID(ctx, id)
+	}
 	val, err := r.rdb.Get(ctx, key).Result()
 
 	if err == nil {
@@ -67,7 +114,8 @@ func (r *cachedRepository[E, M]) FindByID(ctx context.Context, id any, scopes ..
 	}
 
 	// 3. Cache MISS -> Panggil Repo Asli
-	entity, err := r.next.FindByID(ctx, id) // scopes kosong
+	// Baca dari primary, replica yang tertinggal bisa mengisi cache dengan data lama sampai TTL habis
+	entity, err := r.next.FindByID(UsePrimary(ctx), id) // scopes kosong
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +140,7 @@ func (r *cachedRepository[E, M]) UpdateFields(ctx context.Context, id any, field
 	if err := r.next.UpdateFields(ctx, id, fields); err != nil {
 		return err
 	}
-	r.rdb.Del(context.Background(), r.getKey(id))
+	r.invalidate(ctx, id)
 	return nil
 }
 
@@ -103,7 +151,7 @@ func (r *cachedRepository[E, M]) Update(ctx context.Context, entity *E) error {
 
 	if id, ok := r.getIDFromEntity(entity); ok {
 		// Jika ketemu ID-nya, hapus cache!
-		r.rdb.Del(context.Background(), r.getKey(id))
+		r.invalidate(ctx, id)
 	}
 	return nil
 }
@@ -112,7 +160,7 @@ func (r *cachedRepository[E, M]) Delete(ctx context.Context, id any) error {
 	if err := r.next.Delete(ctx, id); err != nil {
 		return err
 	}
-	r.rdb.Del(context.Background(), r.getKey(id))
+	r.invalidate(ctx, id)
 	return nil
 }
 
@@ -124,7 +172,7 @@ func (r *cachedRepository[E, M]) Restore(ctx context.Context, id any) error {
 	if err := r.next.Restore(ctx, id); err != nil {
 		return err
 	}
-	r.rdb.Del(context.Background(), r.getKey(id)) // Invalidate
+	r.invalidate(ctx, id) // Invalidate
 	return nil
 }
 
@@ -132,7 +180,7 @@ func (r *cachedRepository[E, M]) ForceDelete(ctx context.Context, id any) error
 	if err := r.next.ForceDelete(ctx, id); err != nil {
 		return err
 	}
-	r.rdb.Del(context.Background(), r.getKey(id)) // Invalidate
+	r.invalidate(ctx, id) // Invalidate
 	return nil
 }
 
@@ -149,21 +197,8 @@ func (r *cachedRepository[E, M]) DeleteBatch(ctx context.Context, ids []any) err
 	if err := r.next.DeleteBatch(ctx, ids); err != nil {
 		return err
 	}
-	// Invalidate cache for all deleted IDs
-	go func() {
-		if len(ids) == 0 {
-			return
-		}
-
-		// Kumpulkan semua keys dulu
-		keys := make([]string, len(ids))
-		for i, id := range ids {
-			keys[i] = r.getKey(id)
-		}
-
-		// Hapus sekaligus (pipeline/variadic)
-		r.rdb.Del(context.Background(), keys...)
-	}()
+	// Invalidate cache for all deleted IDs, tidak di goroutine karena ctx bisa berupa request yang sudah selesai
+	r.invalidate(ctx, ids...)
 	return nil
 }
 
@@ -171,3 +206,62 @@ func (r *cachedRepository[E, M]) Count(ctx context.Context, scopes ...func(*gorm
 	// No caching for count (filters may vary)
 	return r.next.Count(ctx, scopes...)
 }
+
+func (r *cachedRepository[E, M]) Upsert(ctx context.Context, entity *E, conflictColumns, updateColumns []string) (int64, error) {
+	return r.UpsertBatch(ctx, []*E{entity}, conflictColumns, updateColumns)
+}
+
+func (r *cachedRepository[E, M]) UpsertBatch(ctx context.Context, entities []*E, conflictColumns, updateColumns []string) (int64, error) {
+	rows, err := r.next.UpsertBatch(ctx, entities, conflictColumns, updateColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	// ID row yang di-update tidak selalu kembali (MySQL), row tersebut tetap di cache sampai TTL habis
+	ids := make([]any, 0, len(entities))
+	for _, entity := range entities {
+		if id, ok := r.getIDFromEntity(entity); ok {
+			ids = append(ids, id)
+		}
+	}
+	r.invalidate(ctx, ids...)
+	return rows, nil
+}
+
+func (r *cachedRepository[E, M]) UpdateWhere(ctx context.Context, fields map[string]interface{}, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	// Ambil ID yang akan berubah dulu, supaya cache-nya bisa dihapus
+	var ids []any
+	if len(scopes) > 0 {
+		db, err := tenantScope[M](ctx, r.next.GetDB(ctx))
+		if err != nil {
+			return 0, err
+		}
+		pk, err := primaryField[M](db)
+		if err != nil {
+			return 0, err
+		}
+		db = db.Model(new(M))
+		for _, scope := range scopes {
+			db = scope(db)
+		}
+		if err := db.Pluck(pk.DBName, &ids).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	rows, err := r.next.UpdateWhere(ctx, fields, scopes...)
+	if err != nil {
+		return 0, err
+	}
+	r.invalidate(ctx, ids...)
+	return rows, nil
+}
+
+func (r *cachedRepository[E, M]) Each(ctx context.Context, batchSize int, fn func(batch []E) error, scopes ...func(*gorm.DB) *gorm.DB) error {
+	// No caching for bulk reads
+	return r.next.Each(ctx, batchSize, fn, scopes...)
+}
+
+func (r *cachedRepository[E, M]) Stream(ctx context.Context, batchSize int, scopes ...func(*gorm.DB) *gorm.DB) iter.Seq2[*E, error] {
+	return r.next.Stream(ctx, batchSize, scopes...)
+}
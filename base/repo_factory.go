@@ -1,6 +1,7 @@
 package base
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -12,26 +13,61 @@ type RepoConfig struct {
 	EnableCache      bool
 	EnablePrometheus bool
 	RedisClient      *redis.Client
+
+	// Replicas menerima FindByID, FindAll, FindOne dan Count, write tetap ke primary
+	Replicas []*gorm.DB
+	// ReplicaStickyWindow: lama read ke primary setelah write di request yang sama, default 5 detik
+	ReplicaStickyWindow time.Duration
+	// ReplicaHealthCheckInterval: interval ping replica, default 10 detik
+	ReplicaHealthCheckInterval time.Duration
 }
 
 // Factory Struct
 type Factory struct {
-	DB     *gorm.DB
-	config RepoConfig
+	// DB adalah primary
+	DB       *gorm.DB
+	config   RepoConfig
+	resolver *dbResolver
 }
 
 func NewFactory(db *gorm.DB, cfg RepoConfig) *Factory {
-	return &Factory{
+	f := &Factory{
 		DB:     db,
 		config: cfg,
 	}
+	if len(cfg.Replicas) > 0 {
+		f.resolver = newDBResolver(db, cfg.Replicas, cfg.ReplicaStickyWindow, cfg.ReplicaHealthCheckInterval)
+	}
+	return f
+}
+
+// ReadDB returns the connection for a read outside a repository, following the same routing
+func (f *Factory) ReadDB(ctx context.Context) *gorm.DB {
+	if tx := ExtractTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	if f.resolver == nil {
+		return f.DB.WithContext(ctx)
+	}
+	return f.resolver.reader(ctx).WithContext(ctx)
+}
+
+// Close stops the replica health check
+func (f *Factory) Close() {
+	if f.resolver != nil {
+		f.resolver.close()
+	}
 }
 
 func NewRepository[E any, M any](f *Factory) BaseRepository[E, M] {
 
 	// 1. Layer Inti: Database (Gorm)
 	// Akses f.DB (karena f sekarang parameter)
-	var repo BaseRepository[E, M] = NewGormRepository[E, M](f.DB)
+	var repo BaseRepository[E, M] = &BaseRepositoryImpl[E, M]{
+		db:       f.DB,
+		resolver: f.resolver,
+		mapper:   MapperFor[E, M](),
+	}
 
 	// 2. Layer Wrapper: Redis (Jika enabled)
 	if f.config.EnableCache && f.config.RedisClient != nil {
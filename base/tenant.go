@@ -0,0 +1,152 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tenantKey struct{}
+type crossTenantKey struct{}
+
+// TenantColumn is the column holding the tenant of a row. Models with a TenantID field are tenant scoped,
+// other models are not touched.
+const TenantColumn = "tenant_id"
+
+var (
+	ErrTenantRequired = errors.New("tenant is required")
+	ErrTenantMismatch = errors.New("row belongs to another tenant")
+)
+
+// WithTenant: Memasukkan tenant ID ke dalam Context
+func WithTenant(ctx context.Context, tenantID uint) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext: Mengambil tenant ID dari Context (jika ada)
+func TenantFromContext(ctx context.Context) (uint, bool) {
+	tenantID, ok := ctx.Value(tenantKey{}).(uint)
+	return tenantID, ok
+}
+
+// WithoutTenant lifts the tenant scope, for admin queries across all tenants.
+// Create still stamps the tenant of the context when there is one.
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, crossTenantKey{}, true)
+}
+
+// IsCrossTenant checks if the tenant scope is lifted with WithoutTenant
+func IsCrossTenant(ctx context.Context) bool {
+	cross, _ := ctx.Value(crossTenantKey{}).(bool)
+	return cross
+}
+
+// TenantMiddleware stores the tenant returned by resolve in the request, the handlers pass c.Context() to the
+// usecases so it is set on both the request and the user context
+func TenantMiddleware(resolve func(c *fiber.Ctx) (uint, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenantID, err := resolve(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
+
+		c.Context().SetUserValue(tenantKey{}, tenantID)
+		c.SetUserContext(WithTenant(c.UserContext(), tenantID))
+		return c.Next()
+	}
+}
+
+// CrossTenantMiddleware lifts the tenant scope for the routes behind it, only put it behind an admin check
+func CrossTenantMiddleware(c *fiber.Ctx) error {
+	c.Context().SetUserValue(crossTenantKey{}, true)
+	c.SetUserContext(WithoutTenant(c.UserContext()))
+	return c.Next()
+}
+
+// tenantFields caches the index of the TenantID field per model type, nil when the model has none
+var tenantFields sync.Map
+
+func tenantField[M any]() []int {
+	t := reflect.TypeOf((*M)(nil)).Elem()
+	if index, ok := tenantFields.Load(t); ok {
+		return index.([]int)
+	}
+
+	var index []int
+	if t.Kind() == reflect.Struct {
+		if field, ok := t.FieldByName("TenantID"); ok {
+			switch field.Type.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				index = field.Index
+			}
+		}
+	}
+	tenantFields.Store(t, index)
+	return index
+}
+
+// isTenantScoped checks if the rows of model M belong to a tenant
+func isTenantScoped[M any]() bool {
+	return tenantField[M]() != nil
+}
+
+// tenantScope limits the query to the tenant of the context
+func tenantScope[M any](ctx context.Context, db *gorm.DB) (*gorm.DB, error) {
+	if !isTenantScoped[M]() || IsCrossTenant(ctx) {
+		return db, nil
+	}
+
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrTenantRequired
+	}
+	return db.Where(clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: TenantColumn},
+		Value:  tenantID,
+	}), nil
+}
+
+// checkTenantFields rejects a change of the tenant column in an update by fields, outside WithoutTenant
+// rows cannot be moved to another tenant
+func checkTenantFields[M any](ctx context.Context, fields map[string]interface{}) error {
+	if !isTenantScoped[M]() || IsCrossTenant(ctx) {
+		return nil
+	}
+	for name := range fields {
+		if name == TenantColumn || name == "TenantID" {
+			return fmt.Errorf("%w: %s cannot be updated", ErrTenantMismatch, name)
+		}
+	}
+	return nil
+}
+
+// stampTenant sets the tenant of the context on a new or updated model. Outside WithoutTenant the model
+// cannot be moved to another tenant.
+func stampTenant[M any](ctx context.Context, model *M) error {
+	index := tenantField[M]()
+	if index == nil {
+		return nil
+	}
+
+	field := reflect.ValueOf(model).Elem().FieldByIndex(index)
+	tenantID, ok := TenantFromContext(ctx)
+	cross := IsCrossTenant(ctx)
+
+	switch {
+	case !ok && (!cross || field.Uint() == 0):
+		return ErrTenantRequired
+	case !ok:
+		return nil
+	case field.Uint() == 0:
+		field.SetUint(uint64(tenantID))
+	case field.Uint() != uint64(tenantID) && !cross:
+		return fmt.Errorf("%w: %d", ErrTenantMismatch, field.Uint())
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type order struct {
+	ID       uint
+	TenantID uint
+	Total    int
+}
+
+type orderModel struct {
+	ID       uint `gorm:"primaryKey"`
+	TenantID uint
+	Total    int
+}
+
+func (orderModel) TableName() string { return "orders" }
+
+type country struct {
+	ID   uint
+	Name string
+}
+
+type countryModel struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+func (countryModel) TableName() string { return "countries" }
+
+// dryRunDB records the SQL of every statement without a database
+func dryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	var sqls []string
+	record := func(db *gorm.DB) { sqls = append(sqls, db.Statement.SQL.String()) }
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("record", record))
+	require.NoError(t, db.Callback().Create().After("gorm:create").Register("record", record))
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("record", record))
+	require.NoError(t, db.Callback().Delete().After("gorm:delete").Register("record", record))
+	return db, &sqls
+}
+
+func TestTenantContext(t *testing.T) {
+	ctx := context.Background()
+	_, ok := base.TenantFromContext(ctx)
+	assert.False(t, ok)
+	assert.False(t, base.IsCrossTenant(ctx))
+
+	ctx = base.WithTenant(ctx, 3)
+	tenantID, ok := base.TenantFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, uint(3), tenantID)
+	assert.True(t, base.IsCrossTenant(base.WithoutTenant(ctx)))
+}
+
+func TestBaseRepository_TenantScope(t *testing.T) {
+	db, sqls := dryRunDB(t)
+	repo := base.NewGormRepository[order, orderModel](db)
+	ctx := base.WithTenant(context.Background(), 3)
+
+	t.Run("Queries are scoped", func(t *testing.T) {
+		*sqls = nil
+		_, err := repo.FindOne(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, 5))
+		require.NoError(t, repo.UpdateFields(ctx, 5, map[string]interface{}{"total": 1}))
+
+		require.Len(t, *sqls, 3)
+		for _, sql := range *sqls {
+			assert.Contains(t, sql, "`orders`.`tenant_id` = ?")
+		}
+	})
+
+	t.Run("Create stamps the tenant", func(t *testing.T) {
+		entity := order{Total: 10}
+		require.NoError(t, repo.Create(ctx, &entity))
+		assert.Equal(t, uint(3), entity.TenantID)
+
+		assert.ErrorIs(t, repo.Create(ctx, &order{TenantID: 4}), base.ErrTenantMismatch)
+		require.NoError(t, repo.Create(base.WithoutTenant(ctx), &order{TenantID: 4}))
+	})
+
+	t.Run("Update by fields keeps the tenant", func(t *testing.T) {
+		assert.ErrorIs(t, repo.UpdateFields(ctx, 5, map[string]interface{}{"tenant_id": 4}), base.ErrTenantMismatch)
+		require.NoError(t, repo.UpdateFields(base.WithoutTenant(ctx), 5, map[string]interface{}{"tenant_id": 4}))
+	})
+
+	t.Run("Update cannot fall back to insert", func(t *testing.T) {
+		*sqls = nil
+		require.NoError(t, repo.Update(ctx, &order{ID: 5, Total: 10}))
+		require.Len(t, *sqls, 1)
+		assert.Contains(t, (*sqls)[0], "UPDATE")
+		assert.Contains(t, (*sqls)[0], "`orders`.`tenant_id` = ?")
+	})
+
+	t.Run("Tenant is required", func(t *testing.T) {
+		_, err := repo.Count(context.Background())
+		assert.ErrorIs(t, err, base.ErrTenantRequired)
+		assert.ErrorIs(t, repo.Create(context.Background(), &order{}), base.ErrTenantRequired)
+	})
+
+	t.Run("Cross tenant queries are not scoped", func(t *testing.T) {
+		*sqls = nil
+		_, err := repo.Count(base.WithoutTenant(context.Background()))
+		require.NoError(t, err)
+		require.Len(t, *sqls, 1)
+		assert.NotContains(t, (*sqls)[0], "tenant_id")
+	})
+}
+
+func TestBaseRepository_WithoutTenantColumn(t *testing.T) {
+	db, sqls := dryRunDB(t)
+	repo := base.NewGormRepository[country, countryModel](db)
+
+	_, err := repo.FindOne(context.Background())
+	require.NoError(t, err)
+	require.Len(t, *sqls, 1)
+	assert.NotContains(t, (*sqls)[0], "tenant_id")
+}
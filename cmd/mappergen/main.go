@@ -0,0 +1,313 @@
+// Command mappergen generates a typed base.Mapper for an entity and model pair, so the repository of the
+// pair does not copy with reflection.
+//
+// Run it from the package of the repository with go generate:
+//
+//	//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/auth/domain/entity.User -model github.com/budimanlai/go-core/auth/models.User
+//
+// Fields are matched by name. Fields with identical or convertible types are copied, pointers to
+// convertible types are copied when not nil, and a pointer maps to a SQL null wrapper such as gorm.DeletedAt.
+// Other fields are listed in the generated file and left to a hand-written mapper.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	entityFlag := flag.String("entity", "", "entity type as <import path>.<type>")
+	modelFlag := flag.String("model", "", "model type as <import path>.<type>")
+	pkgFlag := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file, defaults to $GOPACKAGE")
+	outFlag := flag.String("out", "", "output file, defaults to <entity>_mapper_gen.go")
+	flag.Parse()
+
+	if *entityFlag == "" || *modelFlag == "" || *pkgFlag == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	imp := importer.ForCompiler(token.NewFileSet(), "source", nil)
+	entity, err := lookupStruct(imp, *entityFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	model, err := lookupStruct(imp, *modelFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := &generator{pkg: *pkgFlag, entity: entity, model: model}
+	src, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out := *outFlag
+	if out == "" {
+		out = snakeCase(entity.name) + "_mapper_gen.go"
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	for _, skipped := range g.skipped {
+		log.Printf("%s: %s not mapped", out, skipped)
+	}
+}
+
+type namedStruct struct {
+	path   string
+	name   string
+	fields *types.Struct
+}
+
+// lookupStruct loads the struct type of "<import path>.<type>"
+func lookupStruct(imp types.Importer, ref string) (*namedStruct, error) {
+	dot := strings.LastIndex(ref, ".")
+	if dot < 0 {
+		return nil, fmt.Errorf("%s: expected <import path>.<type>", ref)
+	}
+	path, name := ref[:dot], ref[dot+1:]
+
+	pkg, err := imp.Import(path)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%s: type not found", ref)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("%s: not a named type", ref)
+	}
+	fields, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("%s: not a struct", ref)
+	}
+	return &namedStruct{path: path, name: name, fields: fields}, nil
+}
+
+type generator struct {
+	pkg     string
+	entity  *namedStruct
+	model   *namedStruct
+	imports map[string]string // path -> alias
+	skipped []string
+}
+
+func (g *generator) generate() ([]byte, error) {
+	g.imports = map[string]string{
+		"github.com/budimanlai/go-core/base": "base",
+		g.entity.path:                        "entity",
+	}
+	if g.model.path == g.entity.path {
+		return nil, fmt.Errorf("entity and model must be in different packages")
+	}
+	g.imports[g.model.path] = "model"
+
+	var body bytes.Buffer
+	mapperName := lowerFirst(g.entity.name) + "Mapper"
+	entityType := "entity." + g.entity.name
+	modelType := "model." + g.model.name
+
+	fmt.Fprintf(&body, "func init() {\n\tbase.RegisterMapper[%s, %s](%s{})\n}\n\n", entityType, modelType, mapperName)
+	fmt.Fprintf(&body, "// %s maps %s and %s without reflection\ntype %s struct{}\n\n", mapperName, entityType, modelType, mapperName)
+
+	fmt.Fprintf(&body, "func (%s) ToModel(e *%s, m *%s) error {\n", mapperName, entityType, modelType)
+	g.assignments(&body, g.entity, g.model, "e", "m")
+	body.WriteString("\treturn nil\n}\n\n")
+
+	fmt.Fprintf(&body, "func (%s) ToEntity(m *%s, e *%s) error {\n", mapperName, modelType, entityType)
+	g.assignments(&body, g.model, g.entity, "m", "e")
+	body.WriteString("\treturn nil\n}\n")
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by mappergen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", g.pkg)
+	out.WriteString("\t\"github.com/budimanlai/go-core/base\"\n\n")
+	for _, path := range []string{g.entity.path, g.model.path} {
+		fmt.Fprintf(&out, "\t%s %q\n", g.imports[path], path)
+	}
+	var others []string
+	for path, alias := range g.imports {
+		if path != g.entity.path && path != g.model.path && alias != "base" {
+			others = append(others, path)
+		}
+	}
+	sort.Strings(others)
+	for _, path := range others {
+		if alias := g.imports[path]; alias != path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(&out, "\t%s %q\n", alias, path)
+		} else {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+	}
+	out.WriteString(")\n\n")
+	out.Write(body.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+// assignments writes the copy of every field of src that dst has with the same name
+func (g *generator) assignments(w *bytes.Buffer, src, dst *namedStruct, from, to string) {
+	dstFields := map[string]*types.Var{}
+	for i := 0; i < dst.fields.NumFields(); i++ {
+		if field := dst.fields.Field(i); field.Exported() {
+			dstFields[field.Name()] = field
+		}
+	}
+
+	for i := 0; i < src.fields.NumFields(); i++ {
+		srcField := src.fields.Field(i)
+		dstField, ok := dstFields[srcField.Name()]
+		if !ok && srcField.Embedded() {
+			// promoted fields are not flattened
+			g.skipped = append(g.skipped, fmt.Sprintf("%s.%s (embedded)", src.name, srcField.Name()))
+			fmt.Fprintf(w, "\t// %s not mapped: embedded\n", srcField.Name())
+			continue
+		}
+		if !srcField.Exported() || !ok {
+			continue
+		}
+
+		name := srcField.Name()
+		st, dt := srcField.Type(), dstField.Type()
+		switch {
+		case types.Identical(st, dt):
+			fmt.Fprintf(w, "\t%s.%s = %s.%s\n", to, name, from, name)
+		case convertible(st, dt):
+			fmt.Fprintf(w, "\t%s.%s = %s(%s.%s)\n", to, name, g.typeString(dt), from, name)
+		case isConvertiblePointer(st, dt):
+			value := g.convert(st.(*types.Pointer).Elem(), dt.(*types.Pointer).Elem(), "*"+from+"."+name)
+			fmt.Fprintf(w, "\t%s.%s = nil\n\tif %s.%s != nil {\n\t\tv := %s\n\t\t%s.%s = &v\n\t}\n",
+				to, name, from, name, value, to, name)
+		case nullValue(dt) != nil && isConvertiblePointer(st, types.NewPointer(nullValue(dt).Type())):
+			field := nullValue(dt)
+			value := g.convert(st.(*types.Pointer).Elem(), field.Type(), "*"+from+"."+name)
+			fmt.Fprintf(w, "\t%s.%s = %s{}\n\tif %s.%s != nil {\n\t\t%s.%s = %s{%s: %s, Valid: true}\n\t}\n",
+				to, name, g.typeString(dt), from, name, to, name, g.typeString(dt), field.Name(), value)
+		case nullValue(st) != nil && isConvertiblePointer(types.NewPointer(nullValue(st).Type()), dt):
+			field := nullValue(st)
+			value := g.convert(field.Type(), dt.(*types.Pointer).Elem(), from+"."+name+"."+field.Name())
+			fmt.Fprintf(w, "\t%s.%s = nil\n\tif %s.%s.Valid {\n\t\tv := %s\n\t\t%s.%s = &v\n\t}\n",
+				to, name, from, name, value, to, name)
+		default:
+			g.skipped = append(g.skipped, fmt.Sprintf("%s.%s (%s to %s)", src.name, name, describe(st), describe(dt)))
+			fmt.Fprintf(w, "\t// %s not mapped: %s to %s\n", name, describe(st), describe(dt))
+		}
+	}
+}
+
+func isConvertiblePointer(src, dst types.Type) bool {
+	sp, ok := src.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	dp, ok := dst.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	return convertible(sp.Elem(), dp.Elem())
+}
+
+// nullValue returns the value field of a SQL null wrapper such as sql.NullTime or gorm.DeletedAt, a struct of
+// the value and a Valid flag, which maps to a pointer
+func nullValue(t types.Type) *types.Var {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok || st.NumFields() != 2 {
+		return nil
+	}
+	valid := st.Field(1)
+	if valid.Name() != "Valid" || !types.Identical(valid.Type(), types.Typ[types.Bool]) {
+		return nil
+	}
+	return st.Field(0)
+}
+
+// convertible allows the conversions that keep the value: the same underlying type, or between numbers.
+// types.ConvertibleTo also allows int to string, which makes a rune.
+func convertible(src, dst types.Type) bool {
+	if types.Identical(src.Underlying(), dst.Underlying()) {
+		return true
+	}
+	sb, ok := src.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	db, ok := dst.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	return sb.Info()&types.IsNumeric != 0 && db.Info()&types.IsNumeric != 0
+}
+
+// typeString writes a type with the package aliases of the generated file, adding imports as needed
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if alias, ok := g.imports[pkg.Path()]; ok {
+			return alias
+		}
+		alias := pkg.Name()
+		for taken := true; taken; {
+			taken = false
+			for _, used := range g.imports {
+				if used == alias {
+					alias += "_"
+					taken = true
+				}
+			}
+		}
+		g.imports[pkg.Path()] = alias
+		return alias
+	})
+}
+
+// convert writes expr as a dst, with a conversion only when the types differ
+func (g *generator) convert(src, dst types.Type, expr string) string {
+	if types.Identical(src, dst) {
+		return expr
+	}
+	return g.typeString(dst) + "(" + expr + ")"
+}
+
+// describe writes a type for comments and logs, without adding imports
+func describe(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string { return pkg.Name() })
+}
+
+// lowerFirst lowercases the leading word and keeps acronyms together like snakeCase, APIKey becomes apiKey
+func lowerFirst(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) || i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
+
+// snakeCase keeps acronyms together, APIKey becomes api_key
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
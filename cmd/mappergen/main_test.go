@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameCasing(t *testing.T) {
+	for _, tt := range []struct{ in, lower, snake string }{
+		{"User", "user", "user"},
+		{"UserSession", "userSession", "user_session"},
+		{"APIKey", "apiKey", "api_key"},
+		{"API", "api", "api"},
+		{"OAuthState", "oAuthState", "o_auth_state"},
+	} {
+		assert.Equal(t, tt.lower, lowerFirst(tt.in), tt.in)
+		assert.Equal(t, tt.snake, snakeCase(tt.in), tt.in)
+	}
+}
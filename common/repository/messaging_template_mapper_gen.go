@@ -0,0 +1,35 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/common/domain/entity"
+	model "github.com/budimanlai/go-core/common/models"
+)
+
+func init() {
+	base.RegisterMapper[entity.MessagingTemplate, model.MessagingTemplate](messagingTemplateMapper{})
+}
+
+// messagingTemplateMapper maps entity.MessagingTemplate and model.MessagingTemplate without reflection
+type messagingTemplateMapper struct{}
+
+func (messagingTemplateMapper) ToModel(e *entity.MessagingTemplate, m *model.MessagingTemplate) error {
+	m.TemplateName = e.TemplateName
+	m.Channel = e.Channel
+	m.Subject = e.Subject
+	m.ContentHTML = e.ContentHTML
+	m.ContentText = e.ContentText
+	return nil
+}
+
+func (messagingTemplateMapper) ToEntity(m *model.MessagingTemplate, e *entity.MessagingTemplate) error {
+	e.TemplateName = m.TemplateName
+	e.Channel = m.Channel
+	e.Subject = m.Subject
+	e.ContentHTML = m.ContentHTML
+	e.ContentText = m.ContentText
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/budimanlai/go-core/common/models"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/common/domain/entity.MessagingTemplate -model github.com/budimanlai/go-core/common/models.MessagingTemplate
+
 type MessagingTemplateRepositoryImpl struct {
 	base.BaseRepository[entity.MessagingTemplate, models.MessagingTemplate]
 }
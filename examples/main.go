@@ -9,7 +9,12 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/budimanlai/go-core/account"
+	account_usecase "github.com/budimanlai/go-core/account/domain/usecase"
+	"github.com/budimanlai/go-core/apikey"
 	auth_nmanager "github.com/budimanlai/go-core/auth"
+	auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
+	auth_http "github.com/budimanlai/go-core/auth/handler/http"
 	auth_service "github.com/budimanlai/go-core/auth/service"
 	impl_common_repository "github.com/budimanlai/go-core/common/repository"
 	impl_common_usecase "github.com/budimanlai/go-core/common/usecase"
@@ -45,8 +50,10 @@ func main() {
 		EnableCache:      false,
 		EnablePrometheus: false,
 		RedisClient:      nil,
+		// Replicas: []*gorm.DB{replicaDB}, // reads go to the replicas, writes to db
 	}
 	repoFactory := base.NewFactory(db, repoConfig)
+	defer repoFactory.Close()
 
 	messagingTemplateRepo := impl_common_repository.NewMessagingTemplateRepositoryImpl(repoFactory)
 	messagingTemplateUsecase := impl_common_usecase.NewMessagingTemplateUsecaseImpl(db, messagingTemplateRepo)
@@ -91,18 +98,71 @@ func main() {
 
 	authManager := auth_nmanager.NewAuthManagerDefaultImpl(repoFactory)
 	authManager.SetJwtConfig(jwtConfig)
+
+	// sign tokens with rotating ES256 keys, other services verify them with /api/v1/.well-known/jwks.json
+	keySet, err := auth_service.NewKeySetImpl(auth_service.KeySetConfig{
+		Method:           "ES256",
+		RotationInterval: 24 * time.Hour,
+		Overlap:          jwtConfig.ExpirationTime,
+	})
+	if err != nil {
+		panic(err)
+	}
+	authManager.SetKeySet(keySet)
 	authManager.SetOtpSenderService(otpSenderService, otpConfig)
 	authManager.SetPublicMiddleware(basicAuthMiddleware.Middleware())
+	authManager.SetAppRegistry(auth_service.NewAppRegistryImpl(
+		auth_entity.App{ID: 1, Name: "customer", LoginChannels: []string{"email", "phone"}},
+		auth_entity.App{ID: 2, Name: "dashboard", LoginChannels: []string{"email"},
+			SessionPolicy: &auth_entity.SessionPolicy{MaxSessions: 1, OnLimit: auth_entity.SessionLimitRejectNew}},
+	), "X-App-ID")
+	authManager.SetSessionStore(auth_service.NewSessionStoreMemoryImpl(5 * time.Minute))
+	authManager.SetSessionPolicy(auth_entity.SessionPolicy{
+		MaxSessions: 3,
+		OnLimit:     auth_entity.SessionLimitEvictOldest,
+	})
+	authManager.EnablePasswordless(usecase.PasswordlessConfig{
+		MagicLinkURL: "http://localhost:3000/login/magic",
+	})
+	authManager.AddOAuthProvider(auth_service.GoogleProviderConfig("google-client-id", "google-client-secret",
+		"http://localhost:8084/api/v1/auth/oauth/google/callback"))
 	authManager.InitManager()
 
 	app := fiber.New()
+	// keeps the reads of a request on the primary after it writes, only matters with replicas
+	app.Use(base.ReadYourWritesMiddleware)
 	api := app.Group("/api/v1")
 	authManager.SetRoute(api)
 
+	// profile management on the same users and sessions as the auth module
+	accountManager := account.NewAccountManagerDefaultImpl(repoFactory)
+	accountManager.SetAuthManager(authManager)
+	accountManager.SetPublicMiddleware(basicAuthMiddleware.Middleware())
+	accountManager.SetAvatarStorage(service.NewLocalStorageServiceImpl("./uploads", "http://localhost:8084/uploads"),
+		account_usecase.AvatarConfig{})
+	accountManager.InitManager()
+	accountManager.SetRoute(api)
+	app.Static("/uploads", "./uploads")
+
+	// dashboard routes need the dashboard access, and a verified email when the verification config requires it
+	api.Get("dashboard/ping", accountManager.AppMiddleware, accountManager.SelfMiddleware, accountManager.DashboardMiddleware,
+		func(c *fiber.Ctx) error {
+			return c.SendString("pong")
+		})
+
 	api.Get("ping", authManager.PublicMiddleware, func(c *fiber.Ctx) error {
 		return c.SendString("pong")
 	})
 
+	// API keys of the machine clients, the management routes stay closed until an admin middleware is set
+	apiKeyManager := apikey.NewAPIKeyManagerDefaultImpl(repoFactory)
+	apiKeyManager.InitManager()
+	apiKeyManager.SetRoute(api)
+
+	api.Get("machine/ping", apiKeyManager.Middleware, auth_http.RequireScope("ping"), func(c *fiber.Ctx) error {
+		return c.SendString("pong")
+	})
+
 	// setup routes
 	if err := app.Listen(":8084"); err != nil {
 		fmt.Println("Failed to start server:", err)
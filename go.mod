@@ -7,7 +7,7 @@ replace github.com/budimanlai/go-pkg => /Users/budiman/Documents/development/my_
 require (
 	github.com/gofiber/fiber/v2 v2.52.10
 	github.com/golang-jwt/jwt/v5 v5.3.0
-	golang.org/x/crypto v0.45.0 // indirect
+	golang.org/x/crypto v0.45.0
 	gorm.io/gorm v1.31.1
 )
 
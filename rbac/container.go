@@ -0,0 +1,69 @@
+package rbac
+
+import (
+	"time"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-core/rbac/dto"
+	"github.com/budimanlai/go-core/rbac/handler"
+	"github.com/budimanlai/go-core/rbac/repository"
+	"github.com/budimanlai/go-core/rbac/service"
+)
+
+// DefaultPermissionCacheTTL is how long the resolved permissions of a user are cached
+const DefaultPermissionCacheTTL = 5 * time.Minute
+
+type RBACContainer struct {
+	factory *base.Factory
+
+	// Repositories
+	RoleRepository           repository.RoleRepository
+	PermissionRepository     repository.PermissionRepository
+	RolePermissionRepository repository.RolePermissionRepository
+	UserRoleRepository       repository.UserRoleRepository
+
+	// Services
+	PermissionCache      *service.PermissionCache
+	RoleService          service.RoleService
+	PermissionService    service.PermissionService
+	AuthorizationService service.AuthorizationService
+
+	// Handlers
+	RoleHandler       *base.BaseHandler[entity.Role, dto.CreateRoleReq, dto.UpdateRoleReq]
+	PermissionHandler *base.BaseHandler[entity.Permission, dto.CreatePermissionReq, dto.UpdatePermissionReq]
+	AssignmentHandler *handler.AssignmentHandler
+}
+
+func NewRBACContainer(factory *base.Factory) *RBACContainer {
+	rbac := &RBACContainer{
+		factory:         factory,
+		PermissionCache: service.NewPermissionCache(DefaultPermissionCacheTTL),
+	}
+
+	rbac.initRepositories()
+	rbac.initServices()
+	rbac.initHandlers()
+
+	return rbac
+}
+
+func (c *RBACContainer) initRepositories() {
+	c.RoleRepository = repository.NewRoleRepository(c.factory)
+	c.PermissionRepository = repository.NewPermissionRepository(c.factory)
+	c.RolePermissionRepository = repository.NewRolePermissionRepository(c.factory)
+	c.UserRoleRepository = repository.NewUserRoleRepository(c.factory)
+}
+
+func (c *RBACContainer) initServices() {
+	c.RoleService = service.NewRoleService(c.RoleRepository, c.factory.DB, c.PermissionCache)
+	c.PermissionService = service.NewPermissionService(c.PermissionRepository, c.factory.DB, c.PermissionCache)
+	c.AuthorizationService = service.NewAuthorizationService(c.RoleRepository, c.PermissionRepository,
+		c.RolePermissionRepository, c.UserRoleRepository, c.PermissionCache)
+}
+
+func (c *RBACContainer) initHandlers() {
+	c.RoleHandler = base.NewBaseHandler[entity.Role, dto.CreateRoleReq, dto.UpdateRoleReq](c.RoleService)
+	c.PermissionHandler = base.NewBaseHandler[entity.Permission, dto.CreatePermissionReq, dto.UpdatePermissionReq](c.PermissionService)
+	c.AssignmentHandler = handler.NewAssignmentHandler(c.AuthorizationService)
+}
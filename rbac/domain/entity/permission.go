@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type Permission struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// MatchPermission checks if the granted permission covers the required one.
+// A granted permission ending with ".*" covers every permission under its prefix,
+// e.g. "region.*" covers "region.city.write", and "*" covers everything.
+func MatchPermission(granted, required string) bool {
+	if granted == required || granted == "*" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(granted, "*"); ok && strings.HasSuffix(prefix, ".") {
+		return strings.HasPrefix(required, prefix)
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPermission(t *testing.T) {
+	assert.True(t, entity.MatchPermission("region.city.write", "region.city.write"))
+	assert.True(t, entity.MatchPermission("region.*", "region.city.write"))
+	assert.True(t, entity.MatchPermission("*", "region.city.write"))
+
+	assert.False(t, entity.MatchPermission("region.city.read", "region.city.write"))
+	assert.False(t, entity.MatchPermission("region.*", "regionx.city.write"))
+	assert.False(t, entity.MatchPermission("region*", "region.city.write"))
+}
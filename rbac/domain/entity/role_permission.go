@@ -0,0 +1,7 @@
+package entity
+
+type RolePermission struct {
+	ID           uint `json:"id"`
+	RoleID       uint `json:"role_id"`
+	PermissionID uint `json:"permission_id"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+type UserRole struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	RoleID    uint      `json:"role_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
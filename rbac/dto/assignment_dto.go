@@ -0,0 +1,15 @@
+package dto
+
+type AssignPermissionReq struct {
+	PermissionID uint `json:"permission_id" validate:"required"`
+}
+
+type AssignRoleReq struct {
+	RoleID uint `json:"role_id" validate:"required"`
+}
+
+type UserPermissionsResp struct {
+	UserID      uint     `json:"user_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type CreatePermissionReq struct {
+	Name        string `json:"name" validate:"required,max=150"`
+	Description string `json:"description" validate:"omitempty,max=255"`
+}
+
+type UpdatePermissionReq struct {
+	Name        string `json:"name" validate:"omitempty,max=150"`
+	Description string `json:"description" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type CreateRoleReq struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"omitempty,max=255"`
+}
+
+type UpdateRoleReq struct {
+	Name        string `json:"name" validate:"omitempty,max=100"`
+	Description string `json:"description" validate:"omitempty,max=255"`
+}
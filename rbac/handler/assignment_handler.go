@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/budimanlai/go-core/rbac/dto"
+	"github.com/budimanlai/go-core/rbac/service"
+
+	"github.com/budimanlai/go-pkg/response"
+	"github.com/budimanlai/go-pkg/validator"
+)
+
+type AssignmentHandler struct {
+	service service.AuthorizationService
+}
+
+func NewAssignmentHandler(service service.AuthorizationService) *AssignmentHandler {
+	return &AssignmentHandler{
+		service: service,
+	}
+}
+
+// AssignPermission godoc
+// @Summary      Grant Permission to Role
+// @Tags         RBAC
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Role ID"
+// @Param        body body dto.AssignPermissionReq true "Assign Request"
+// @Success      200  {object}  response.APIResponse
+// @Router       /rbac/roles/{id}/permissions [post]
+func (h *AssignmentHandler) AssignPermission(c *fiber.Ctx) error {
+	roleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	var req dto.AssignPermissionReq
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+	if err := validator.ValidateStructWithContext(c, &req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+
+	if err := h.service.AssignPermission(c.Context(), uint(roleID), req.PermissionID); err != nil {
+		return response.BadRequestI18n(c, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(c, "app.success", nil)
+}
+
+// RevokePermission godoc
+// @Summary      Revoke Permission from Role
+// @Tags         RBAC
+// @Produce      json
+// @Param        id             path  int  true  "Role ID"
+// @Param        permission_id  path  int  true  "Permission ID"
+// @Success      200  {object}  response.APIResponse
+// @Router       /rbac/roles/{id}/permissions/{permission_id} [delete]
+func (h *AssignmentHandler) RevokePermission(c *fiber.Ctx) error {
+	roleID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+	permissionID, err := strconv.ParseUint(c.Params("permission_id"), 10, 32)
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	if err := h.service.RevokePermission(c.Context(), uint(roleID), uint(permissionID)); err != nil {
+		return response.ErrorI18n(c, fiber.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(c, "app.success", nil)
+}
+
+// AssignRole godoc
+// @Summary      Assign Role to User
+// @Tags         RBAC
+// @Accept       json
+// @Produce      json
+// @Param        user_id  path  int  true  "User ID"
+// @Param        body body dto.AssignRoleReq true "Assign Request"
+// @Success      200  {object}  response.APIResponse
+// @Router       /rbac/users/{user_id}/roles [post]
+func (h *AssignmentHandler) AssignRole(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	var req dto.AssignRoleReq
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+	if err := validator.ValidateStructWithContext(c, &req); err != nil {
+		return response.ValidationErrorI18n(c, err)
+	}
+
+	if err := h.service.AssignRole(c.Context(), uint(userID), req.RoleID); err != nil {
+		return response.BadRequestI18n(c, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(c, "app.success", nil)
+}
+
+// RevokeRole godoc
+// @Summary      Revoke Role from User
+// @Tags         RBAC
+// @Produce      json
+// @Param        user_id  path  int  true  "User ID"
+// @Param        role_id  path  int  true  "Role ID"
+// @Success      200  {object}  response.APIResponse
+// @Router       /rbac/users/{user_id}/roles/{role_id} [delete]
+func (h *AssignmentHandler) RevokeRole(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+	roleID, err := strconv.ParseUint(c.Params("role_id"), 10, 32)
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	if err := h.service.RevokeRole(c.Context(), uint(userID), uint(roleID)); err != nil {
+		return response.ErrorI18n(c, fiber.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(c, "app.success", nil)
+}
+
+// UserPermissions godoc
+// @Summary      List User Roles and Permissions
+// @Tags         RBAC
+// @Produce      json
+// @Param        user_id  path  int  true  "User ID"
+// @Success      200  {object}  response.APIResponse{data=dto.UserPermissionsResp}
+// @Router       /rbac/users/{user_id}/permissions [get]
+func (h *AssignmentHandler) UserPermissions(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusBadRequest, "app.error.invalid_request_body", nil)
+	}
+
+	roles, err := h.service.GetUserRoles(c.Context(), uint(userID))
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusInternalServerError, err.Error(), nil)
+	}
+	permissions, err := h.service.GetUserPermissions(c.Context(), uint(userID))
+	if err != nil {
+		return response.ErrorI18n(c, fiber.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return response.SuccessI18n(c, "app.success", dto.UserPermissionsResp{
+		UserID:      uint(userID),
+		Roles:       roles,
+		Permissions: permissions,
+	})
+}
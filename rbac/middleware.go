@@ -0,0 +1,69 @@
+package rbac
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	auth_entity "github.com/budimanlai/go-core/auth/domain/entity"
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-pkg/response"
+)
+
+// RequirePermission returns a middleware allowing only users granted the permission,
+// e.g. RequirePermission("region.city.write"). It must run after the private middleware.
+// An API key acts for its owner only within its scopes, so it also needs a scope matching the permission.
+func (c *RBACContainer) RequirePermission(permission string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID := currentUserID(ctx)
+		if userID == 0 {
+			return response.ErrorI18n(ctx, fiber.StatusUnauthorized, "auth.error.unauthorized", nil)
+		}
+
+		if auth := auth_entity.ExtractAuthContext(ctx.Context()); auth != nil && auth.APIKeyID != 0 {
+			if !slices.ContainsFunc(auth.Scopes, func(scope string) bool {
+				return entity.MatchPermission(scope, permission)
+			}) {
+				return response.ErrorI18n(ctx, fiber.StatusForbidden, "rbac.error.forbidden", nil)
+			}
+		}
+
+		allowed, err := c.AuthorizationService.HasPermission(ctx.Context(), userID, permission)
+		if err != nil {
+			return response.ErrorI18n(ctx, fiber.StatusInternalServerError, err.Error(), nil)
+		}
+		if !allowed {
+			return response.ErrorI18n(ctx, fiber.StatusForbidden, "rbac.error.forbidden", nil)
+		}
+
+		return ctx.Next()
+	}
+}
+
+// AuthContextResolver fills the roles of the AuthContext from the role assignments,
+// register it with AuthManagerDefaultImpl.AddAuthContextResolver
+func (c *RBACContainer) AuthContextResolver() func(ctx context.Context, auth *auth_entity.AuthContext) error {
+	return func(ctx context.Context, auth *auth_entity.AuthContext) error {
+		roles, err := c.AuthorizationService.GetUserRoles(ctx, auth.UserID)
+		if err != nil {
+			return err
+		}
+		auth.Roles = append(auth.Roles, roles...)
+		return nil
+	}
+}
+
+// currentUserID returns the authenticated user ID from the AuthContext, or from c.Locals("user_id")
+func currentUserID(ctx *fiber.Ctx) uint {
+	if auth := auth_entity.ExtractAuthContext(ctx.Context()); auth != nil {
+		return auth.UserID
+	}
+
+	if value, ok := ctx.Locals("user_id").(string); ok {
+		userID, _ := strconv.ParseUint(value, 10, 32)
+		return uint(userID)
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type RoleModel struct {
+	ID          uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	Name        string    `gorm:"column:name;type:varchar(100);uniqueIndex;not null"`
+	Description string    `gorm:"column:description;type:varchar(255);default:''"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (RoleModel) TableName() string {
+	return "rbac_roles"
+}
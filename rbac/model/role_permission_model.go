@@ -0,0 +1,11 @@
+package model
+
+type RolePermissionModel struct {
+	ID           uint `gorm:"column:id;primaryKey;autoIncrement"`
+	RoleID       uint `gorm:"column:role_id;not null;uniqueIndex:idx_rbac_role_permission"`
+	PermissionID uint `gorm:"column:permission_id;not null;uniqueIndex:idx_rbac_role_permission"`
+}
+
+func (RolePermissionModel) TableName() string {
+	return "rbac_role_permissions"
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type UserRoleModel struct {
+	ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID    uint      `gorm:"column:user_id;not null;uniqueIndex:idx_rbac_user_role"`
+	RoleID    uint      `gorm:"column:role_id;not null;uniqueIndex:idx_rbac_user_role"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (UserRoleModel) TableName() string {
+	return "rbac_user_roles"
+}
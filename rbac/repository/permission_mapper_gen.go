@@ -0,0 +1,35 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/rbac/domain/entity"
+	model "github.com/budimanlai/go-core/rbac/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.Permission, model.PermissionModel](permissionMapper{})
+}
+
+// permissionMapper maps entity.Permission and model.PermissionModel without reflection
+type permissionMapper struct{}
+
+func (permissionMapper) ToModel(e *entity.Permission, m *model.PermissionModel) error {
+	m.ID = e.ID
+	m.Name = e.Name
+	m.Description = e.Description
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+	return nil
+}
+
+func (permissionMapper) ToEntity(m *model.PermissionModel, e *entity.Permission) error {
+	e.ID = m.ID
+	e.Name = m.Name
+	e.Description = m.Description
+	e.CreatedAt = m.CreatedAt
+	e.UpdatedAt = m.UpdatedAt
+	return nil
+}
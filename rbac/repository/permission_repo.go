@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-core/rbac/model"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/rbac/domain/entity.Permission -model github.com/budimanlai/go-core/rbac/model.PermissionModel
+
+type PermissionRepository interface {
+	base.BaseRepository[entity.Permission, model.PermissionModel]
+}
+
+type permissionRepositoryImpl struct {
+	base.BaseRepository[entity.Permission, model.PermissionModel]
+}
+
+func NewPermissionRepository(f *base.Factory) PermissionRepository {
+	return &permissionRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.Permission, model.PermissionModel](f),
+	}
+}
@@ -0,0 +1,35 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/rbac/domain/entity"
+	model "github.com/budimanlai/go-core/rbac/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.Role, model.RoleModel](roleMapper{})
+}
+
+// roleMapper maps entity.Role and model.RoleModel without reflection
+type roleMapper struct{}
+
+func (roleMapper) ToModel(e *entity.Role, m *model.RoleModel) error {
+	m.ID = e.ID
+	m.Name = e.Name
+	m.Description = e.Description
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+	return nil
+}
+
+func (roleMapper) ToEntity(m *model.RoleModel, e *entity.Role) error {
+	e.ID = m.ID
+	e.Name = m.Name
+	e.Description = m.Description
+	e.CreatedAt = m.CreatedAt
+	e.UpdatedAt = m.UpdatedAt
+	return nil
+}
@@ -0,0 +1,31 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/rbac/domain/entity"
+	model "github.com/budimanlai/go-core/rbac/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.RolePermission, model.RolePermissionModel](rolePermissionMapper{})
+}
+
+// rolePermissionMapper maps entity.RolePermission and model.RolePermissionModel without reflection
+type rolePermissionMapper struct{}
+
+func (rolePermissionMapper) ToModel(e *entity.RolePermission, m *model.RolePermissionModel) error {
+	m.ID = e.ID
+	m.RoleID = e.RoleID
+	m.PermissionID = e.PermissionID
+	return nil
+}
+
+func (rolePermissionMapper) ToEntity(m *model.RolePermissionModel, e *entity.RolePermission) error {
+	e.ID = m.ID
+	e.RoleID = m.RoleID
+	e.PermissionID = m.PermissionID
+	return nil
+}
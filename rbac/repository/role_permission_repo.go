@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-core/rbac/model"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/rbac/domain/entity.RolePermission -model github.com/budimanlai/go-core/rbac/model.RolePermissionModel
+
+type RolePermissionRepository interface {
+	base.BaseRepository[entity.RolePermission, model.RolePermissionModel]
+}
+
+type rolePermissionRepositoryImpl struct {
+	base.BaseRepository[entity.RolePermission, model.RolePermissionModel]
+}
+
+func NewRolePermissionRepository(f *base.Factory) RolePermissionRepository {
+	return &rolePermissionRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.RolePermission, model.RolePermissionModel](f),
+	}
+}
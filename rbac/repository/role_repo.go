@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-core/rbac/model"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/rbac/domain/entity.Role -model github.com/budimanlai/go-core/rbac/model.RoleModel
+
+type RoleRepository interface {
+	base.BaseRepository[entity.Role, model.RoleModel]
+}
+
+type roleRepositoryImpl struct {
+	base.BaseRepository[entity.Role, model.RoleModel]
+}
+
+func NewRoleRepository(f *base.Factory) RoleRepository {
+	return &roleRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.Role, model.RoleModel](f),
+	}
+}
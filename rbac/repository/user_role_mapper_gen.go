@@ -0,0 +1,33 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/rbac/domain/entity"
+	model "github.com/budimanlai/go-core/rbac/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.UserRole, model.UserRoleModel](userRoleMapper{})
+}
+
+// userRoleMapper maps entity.UserRole and model.UserRoleModel without reflection
+type userRoleMapper struct{}
+
+func (userRoleMapper) ToModel(e *entity.UserRole, m *model.UserRoleModel) error {
+	m.ID = e.ID
+	m.UserID = e.UserID
+	m.RoleID = e.RoleID
+	m.CreatedAt = e.CreatedAt
+	return nil
+}
+
+func (userRoleMapper) ToEntity(m *model.UserRoleModel, e *entity.UserRole) error {
+	e.ID = m.ID
+	e.UserID = m.UserID
+	e.RoleID = m.RoleID
+	e.CreatedAt = m.CreatedAt
+	return nil
+}
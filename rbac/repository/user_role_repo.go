@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-core/rbac/model"
+)
+
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/rbac/domain/entity.UserRole -model github.com/budimanlai/go-core/rbac/model.UserRoleModel
+
+type UserRoleRepository interface {
+	base.BaseRepository[entity.UserRole, model.UserRoleModel]
+}
+
+type userRoleRepositoryImpl struct {
+	base.BaseRepository[entity.UserRole, model.UserRoleModel]
+}
+
+func NewUserRoleRepository(f *base.Factory) UserRoleRepository {
+	return &userRoleRepositoryImpl{
+		BaseRepository: base.NewRepository[entity.UserRole, model.UserRoleModel](f),
+	}
+}
@@ -0,0 +1,37 @@
+package rbac
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetAdminRoutes sets the admin routes managing roles, permissions and assignments.
+// Pass the private middleware and a RequirePermission guard, e.g. container.RequirePermission("rbac.manage").
+func SetAdminRoutes(app fiber.Router, container *RBACContainer, middleware ...fiber.Handler) {
+	rbacGroup := app.Group("/rbac", middleware...)
+
+	// role CRUD
+	roleGroup := rbacGroup.Group("/roles")
+	roleGroup.Post("/", container.RoleHandler.Create)
+	roleGroup.Get("/", container.RoleHandler.Index)
+	roleGroup.Get("/:id", container.RoleHandler.View)
+	roleGroup.Put("/:id", container.RoleHandler.Update)
+	roleGroup.Delete("/:id", container.RoleHandler.Delete)
+
+	// role permissions
+	roleGroup.Post("/:id/permissions", container.AssignmentHandler.AssignPermission)
+	roleGroup.Delete("/:id/permissions/:permission_id", container.AssignmentHandler.RevokePermission)
+
+	// permission CRUD
+	permissionGroup := rbacGroup.Group("/permissions")
+	permissionGroup.Post("/", container.PermissionHandler.Create)
+	permissionGroup.Get("/", container.PermissionHandler.Index)
+	permissionGroup.Get("/:id", container.PermissionHandler.View)
+	permissionGroup.Put("/:id", container.PermissionHandler.Update)
+	permissionGroup.Delete("/:id", container.PermissionHandler.Delete)
+
+	// user roles
+	userGroup := rbacGroup.Group("/users/:user_id")
+	userGroup.Get("/permissions", container.AssignmentHandler.UserPermissions)
+	userGroup.Post("/roles", container.AssignmentHandler.AssignRole)
+	userGroup.Delete("/roles/:role_id", container.AssignmentHandler.RevokeRole)
+}
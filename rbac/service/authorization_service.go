@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-core/rbac/model"
+	"github.com/budimanlai/go-core/rbac/repository"
+	"gorm.io/gorm"
+)
+
+type AuthorizationService interface {
+	// AssignPermission grants the permission to the role
+	AssignPermission(ctx context.Context, roleID, permissionID uint) error
+
+	// RevokePermission removes the permission from the role
+	RevokePermission(ctx context.Context, roleID, permissionID uint) error
+
+	// AssignRole assigns the role to the user
+	AssignRole(ctx context.Context, userID, roleID uint) error
+
+	// RevokeRole removes the role from the user
+	RevokeRole(ctx context.Context, userID, roleID uint) error
+
+	// GetUserRoles returns the role names of the user
+	GetUserRoles(ctx context.Context, userID uint) ([]string, error)
+
+	// GetUserPermissions returns the permission names granted to the user through its roles
+	GetUserPermissions(ctx context.Context, userID uint) ([]string, error)
+
+	// HasPermission checks if the user is granted the permission, wildcard grants are honored
+	HasPermission(ctx context.Context, userID uint, permission string) (bool, error)
+}
+
+type authorizationServiceImpl struct {
+	roleRepo           repository.RoleRepository
+	permissionRepo     repository.PermissionRepository
+	rolePermissionRepo repository.RolePermissionRepository
+	userRoleRepo       repository.UserRoleRepository
+	cache              *PermissionCache
+}
+
+func NewAuthorizationService(roleRepo repository.RoleRepository, permissionRepo repository.PermissionRepository,
+	rolePermissionRepo repository.RolePermissionRepository, userRoleRepo repository.UserRoleRepository,
+	cache *PermissionCache) AuthorizationService {
+	return &authorizationServiceImpl{
+		roleRepo:           roleRepo,
+		permissionRepo:     permissionRepo,
+		rolePermissionRepo: rolePermissionRepo,
+		userRoleRepo:       userRoleRepo,
+		cache:              cache,
+	}
+}
+
+// AssignPermission grants the permission to the role, it is a no-op if already granted
+func (s *authorizationServiceImpl) AssignPermission(ctx context.Context, roleID, permissionID uint) error {
+	if err := s.ensureRole(ctx, roleID); err != nil {
+		return err
+	}
+	permission, err := s.permissionRepo.FindByID(ctx, permissionID)
+	if err != nil {
+		return err
+	}
+	if permission == nil {
+		return errors.New("permission not found")
+	}
+
+	existing, err := s.rolePermissionRepo.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
+		return d.Where("role_id = ? AND permission_id = ?", roleID, permissionID)
+	})
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		err = s.rolePermissionRepo.Create(ctx, &entity.RolePermission{
+			RoleID:       roleID,
+			PermissionID: permissionID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.cache.Flush()
+	return nil
+}
+
+// RevokePermission removes the permission from the role
+func (s *authorizationServiceImpl) RevokePermission(ctx context.Context, roleID, permissionID uint) error {
+	err := s.rolePermissionRepo.GetDB(ctx).
+		Where("role_id = ? AND permission_id = ?", roleID, permissionID).
+		Delete(&model.RolePermissionModel{}).Error
+	if err != nil {
+		return err
+	}
+
+	s.cache.Flush()
+	return nil
+}
+
+// AssignRole assigns the role to the user, it is a no-op if already assigned
+func (s *authorizationServiceImpl) AssignRole(ctx context.Context, userID, roleID uint) error {
+	if err := s.ensureRole(ctx, roleID); err != nil {
+		return err
+	}
+
+	existing, err := s.userRoleRepo.FindOne(ctx, func(d *gorm.DB) *gorm.DB {
+		return d.Where("user_id = ? AND role_id = ?", userID, roleID)
+	})
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		err = s.userRoleRepo.Create(ctx, &entity.UserRole{
+			UserID: userID,
+			RoleID: roleID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.cache.Invalidate(userID)
+	return nil
+}
+
+// RevokeRole removes the role from the user
+func (s *authorizationServiceImpl) RevokeRole(ctx context.Context, userID, roleID uint) error {
+	err := s.userRoleRepo.GetDB(ctx).
+		Where("user_id = ? AND role_id = ?", userID, roleID).
+		Delete(&model.UserRoleModel{}).Error
+	if err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(userID)
+	return nil
+}
+
+// GetUserRoles returns the role names of the user
+func (s *authorizationServiceImpl) GetUserRoles(ctx context.Context, userID uint) ([]string, error) {
+	roles, _, err := s.resolve(ctx, userID)
+	return roles, err
+}
+
+// GetUserPermissions returns the permission names granted to the user through its roles
+func (s *authorizationServiceImpl) GetUserPermissions(ctx context.Context, userID uint) ([]string, error) {
+	_, permissions, err := s.resolve(ctx, userID)
+	return permissions, err
+}
+
+// HasPermission checks if the user is granted the permission, wildcard grants are honored
+func (s *authorizationServiceImpl) HasPermission(ctx context.Context, userID uint, permission string) (bool, error) {
+	permissions, err := s.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, granted := range permissions {
+		if entity.MatchPermission(granted, permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolve loads the roles and permissions of the user, from cache when possible
+func (s *authorizationServiceImpl) resolve(ctx context.Context, userID uint) ([]string, []string, error) {
+	if roles, permissions, ok := s.cache.Get(userID); ok {
+		return roles, permissions, nil
+	}
+
+	var roles []string
+	err := s.roleRepo.GetDB(ctx).Model(&model.RoleModel{}).
+		Joins("JOIN rbac_user_roles ON rbac_user_roles.role_id = rbac_roles.id").
+		Where("rbac_user_roles.user_id = ?", userID).
+		Distinct().Pluck("rbac_roles.name", &roles).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var permissions []string
+	err = s.permissionRepo.GetDB(ctx).Model(&model.PermissionModel{}).
+		Joins("JOIN rbac_role_permissions ON rbac_role_permissions.permission_id = rbac_permissions.id").
+		Joins("JOIN rbac_user_roles ON rbac_user_roles.role_id = rbac_role_permissions.role_id").
+		Where("rbac_user_roles.user_id = ?", userID).
+		Distinct().Pluck("rbac_permissions.name", &permissions).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.cache.Set(userID, roles, permissions)
+	return roles, permissions, nil
+}
+
+func (s *authorizationServiceImpl) ensureRole(ctx context.Context, roleID uint) error {
+	role, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return errors.New("role not found")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+type cachedGrant struct {
+	roles       []string
+	permissions []string
+	expiresAt   time.Time
+}
+
+// PermissionCache keeps the resolved roles and permissions per user in process.
+// Entries expire after ttl and are invalidated when assignments change.
+type PermissionCache struct {
+	mu     sync.RWMutex
+	ttl    time.Duration
+	grants map[uint]cachedGrant
+}
+
+func NewPermissionCache(ttl time.Duration) *PermissionCache {
+	return &PermissionCache{
+		ttl:    ttl,
+		grants: make(map[uint]cachedGrant),
+	}
+}
+
+// Get returns the cached roles and permissions of the user, ok is false on cache miss
+func (c *PermissionCache) Get(userID uint) (roles []string, permissions []string, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	grant, found := c.grants[userID]
+	if !found || time.Now().After(grant.expiresAt) {
+		return nil, nil, false
+	}
+	return grant.roles, grant.permissions, true
+}
+
+// Set caches the roles and permissions of the user
+func (c *PermissionCache) Set(userID uint, roles []string, permissions []string) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.grants[userID] = cachedGrant{
+		roles:       roles,
+		permissions: permissions,
+		expiresAt:   time.Now().Add(c.ttl),
+	}
+}
+
+// Invalidate drops the cached grant of the user
+func (c *PermissionCache) Invalidate(userID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.grants, userID)
+}
+
+// Flush drops every cached grant, used when a role or permission changes
+func (c *PermissionCache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.grants = make(map[uint]cachedGrant)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-core/rbac/model"
+	"github.com/budimanlai/go-core/rbac/repository"
+	"gorm.io/gorm"
+)
+
+type PermissionService interface {
+	base.BaseUsecase[entity.Permission]
+}
+
+type permissionServiceImpl struct {
+	base.BaseUsecase[entity.Permission]
+	repo  repository.PermissionRepository
+	cache *PermissionCache
+}
+
+func NewPermissionService(repo repository.PermissionRepository, db *gorm.DB, cache *PermissionCache) PermissionService {
+	return &permissionServiceImpl{
+		BaseUsecase: base.NewBaseUsecase(repo, db),
+		repo:        repo,
+		cache:       cache,
+	}
+}
+
+// Update renames the permission and flushes the permission cache
+func (s *permissionServiceImpl) Update(ctx context.Context, permission *entity.Permission) error {
+	if err := s.BaseUsecase.Update(ctx, permission); err != nil {
+		return err
+	}
+	s.cache.Flush()
+	return nil
+}
+
+// Delete removes the permission with its role assignments
+func (s *permissionServiceImpl) Delete(ctx context.Context, id any) error {
+	err := s.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.repo.GetDB(ctx).Where("permission_id = ?", id).Delete(&model.RolePermissionModel{}).Error
+		if err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cache.Flush()
+	return nil
+}
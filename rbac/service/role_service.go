@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/budimanlai/go-core/base"
+	"github.com/budimanlai/go-core/rbac/domain/entity"
+	"github.com/budimanlai/go-core/rbac/model"
+	"github.com/budimanlai/go-core/rbac/repository"
+	"gorm.io/gorm"
+)
+
+type RoleService interface {
+	base.BaseUsecase[entity.Role]
+}
+
+type roleServiceImpl struct {
+	base.BaseUsecase[entity.Role]
+	repo  repository.RoleRepository
+	cache *PermissionCache
+}
+
+func NewRoleService(repo repository.RoleRepository, db *gorm.DB, cache *PermissionCache) RoleService {
+	return &roleServiceImpl{
+		BaseUsecase: base.NewBaseUsecase(repo, db),
+		repo:        repo,
+		cache:       cache,
+	}
+}
+
+// Update renames the role and flushes the permission cache
+func (s *roleServiceImpl) Update(ctx context.Context, role *entity.Role) error {
+	if err := s.BaseUsecase.Update(ctx, role); err != nil {
+		return err
+	}
+	s.cache.Flush()
+	return nil
+}
+
+// Delete removes the role with its permission and user assignments
+func (s *roleServiceImpl) Delete(ctx context.Context, id any) error {
+	err := s.WithTransaction(ctx, func(ctx context.Context) error {
+		db := s.repo.GetDB(ctx)
+		if err := db.Where("role_id = ?", id).Delete(&model.RolePermissionModel{}).Error; err != nil {
+			return err
+		}
+		if err := db.Where("role_id = ?", id).Delete(&model.UserRoleModel{}).Error; err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cache.Flush()
+	return nil
+}
@@ -0,0 +1,31 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/region/domain/entity"
+	model "github.com/budimanlai/go-core/region/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.City, model.CityModel](cityMapper{})
+}
+
+// cityMapper maps entity.City and model.CityModel without reflection
+type cityMapper struct{}
+
+func (cityMapper) ToModel(e *entity.City, m *model.CityModel) error {
+	m.CityId = e.CityId
+	m.CityName = e.CityName
+	m.ProvId = e.ProvId
+	return nil
+}
+
+func (cityMapper) ToEntity(m *model.CityModel, e *entity.City) error {
+	e.CityId = m.CityId
+	e.CityName = m.CityName
+	e.ProvId = m.ProvId
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/budimanlai/go-core/region/model"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/region/domain/entity.City -model github.com/budimanlai/go-core/region/model.CityModel
+
 type CityRepository interface {
 	base.BaseRepository[entity.City, model.CityModel]
 }
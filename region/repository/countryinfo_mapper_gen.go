@@ -0,0 +1,67 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/region/domain/entity"
+	model "github.com/budimanlai/go-core/region/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.Countryinfo, model.CountryinfoModel](countryinfoMapper{})
+}
+
+// countryinfoMapper maps entity.Countryinfo and model.CountryinfoModel without reflection
+type countryinfoMapper struct{}
+
+func (countryinfoMapper) ToModel(e *entity.Countryinfo, m *model.CountryinfoModel) error {
+	m.IsoAlpha2 = e.IsoAlpha2
+	m.IsoAlpha3 = e.IsoAlpha3
+	m.IsoNumeric = e.IsoNumeric
+	m.FipsCode = e.FipsCode
+	m.Name = e.Name
+	m.Capital = e.Capital
+	m.Areainsqkm = e.Areainsqkm
+	m.Population = e.Population
+	m.Continent = e.Continent
+	m.Tld = e.Tld
+	m.CurrencyCode = e.CurrencyCode
+	m.CurrencyName = e.CurrencyName
+	m.Phone = e.Phone
+	m.PostalCodeFormat = e.PostalCodeFormat
+	m.PostalCodeRegex = e.PostalCodeRegex
+	m.Languages = e.Languages
+	m.GeonameId = e.GeonameId
+	m.Neighbours = e.Neighbours
+	m.EquivalentFipsCode = e.EquivalentFipsCode
+	m.CurrencySymbol = e.CurrencySymbol
+	m.Status = e.Status
+	return nil
+}
+
+func (countryinfoMapper) ToEntity(m *model.CountryinfoModel, e *entity.Countryinfo) error {
+	e.IsoAlpha2 = m.IsoAlpha2
+	e.IsoAlpha3 = m.IsoAlpha3
+	e.IsoNumeric = m.IsoNumeric
+	e.FipsCode = m.FipsCode
+	e.Name = m.Name
+	e.Capital = m.Capital
+	e.Areainsqkm = m.Areainsqkm
+	e.Population = m.Population
+	e.Continent = m.Continent
+	e.Tld = m.Tld
+	e.CurrencyCode = m.CurrencyCode
+	e.CurrencyName = m.CurrencyName
+	e.Phone = m.Phone
+	e.PostalCodeFormat = m.PostalCodeFormat
+	e.PostalCodeRegex = m.PostalCodeRegex
+	e.Languages = m.Languages
+	e.GeonameId = m.GeonameId
+	e.Neighbours = m.Neighbours
+	e.EquivalentFipsCode = m.EquivalentFipsCode
+	e.CurrencySymbol = m.CurrencySymbol
+	e.Status = m.Status
+	return nil
+}
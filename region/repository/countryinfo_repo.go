@@ -6,6 +6,8 @@ import (
 	"github.com/budimanlai/go-core/region/model"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/region/domain/entity.Countryinfo -model github.com/budimanlai/go-core/region/model.CountryinfoModel
+
 type CountryinfoRepository interface {
 	base.BaseRepository[entity.Countryinfo, model.CountryinfoModel]
 }
@@ -0,0 +1,31 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/region/domain/entity"
+	model "github.com/budimanlai/go-core/region/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.District, model.DistrictModel](districtMapper{})
+}
+
+// districtMapper maps entity.District and model.DistrictModel without reflection
+type districtMapper struct{}
+
+func (districtMapper) ToModel(e *entity.District, m *model.DistrictModel) error {
+	m.DisId = e.DisId
+	m.DisName = e.DisName
+	m.CityId = e.CityId
+	return nil
+}
+
+func (districtMapper) ToEntity(m *model.DistrictModel, e *entity.District) error {
+	e.DisId = m.DisId
+	e.DisName = m.DisName
+	e.CityId = m.CityId
+	return nil
+}
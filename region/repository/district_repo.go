@@ -6,6 +6,8 @@ import (
 	"github.com/budimanlai/go-core/region/model"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/region/domain/entity.District -model github.com/budimanlai/go-core/region/model.DistrictModel
+
 type DistrictRepository interface {
 	base.BaseRepository[entity.District, model.DistrictModel]
 }
@@ -0,0 +1,33 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/region/domain/entity"
+	model "github.com/budimanlai/go-core/region/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.Province, model.ProvinceModel](provinceMapper{})
+}
+
+// provinceMapper maps entity.Province and model.ProvinceModel without reflection
+type provinceMapper struct{}
+
+func (provinceMapper) ToModel(e *entity.Province, m *model.ProvinceModel) error {
+	m.ProvId = e.ProvId
+	m.ProvName = e.ProvName
+	m.Locationid = e.Locationid
+	m.Status = e.Status
+	return nil
+}
+
+func (provinceMapper) ToEntity(m *model.ProvinceModel, e *entity.Province) error {
+	e.ProvId = m.ProvId
+	e.ProvName = m.ProvName
+	e.Locationid = m.Locationid
+	e.Status = m.Status
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/budimanlai/go-core/region/model"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/region/domain/entity.Province -model github.com/budimanlai/go-core/region/model.ProvinceModel
+
 type ProvinceRepository interface {
 	base.BaseRepository[entity.Province, model.ProvinceModel]
 }
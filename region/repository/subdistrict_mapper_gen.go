@@ -0,0 +1,31 @@
+// Code generated by mappergen. DO NOT EDIT.
+
+package repository
+
+import (
+	"github.com/budimanlai/go-core/base"
+
+	entity "github.com/budimanlai/go-core/region/domain/entity"
+	model "github.com/budimanlai/go-core/region/model"
+)
+
+func init() {
+	base.RegisterMapper[entity.Subdistrict, model.SubdistrictModel](subdistrictMapper{})
+}
+
+// subdistrictMapper maps entity.Subdistrict and model.SubdistrictModel without reflection
+type subdistrictMapper struct{}
+
+func (subdistrictMapper) ToModel(e *entity.Subdistrict, m *model.SubdistrictModel) error {
+	m.SubdisId = e.SubdisId
+	m.SubdisName = e.SubdisName
+	m.DisId = e.DisId
+	return nil
+}
+
+func (subdistrictMapper) ToEntity(m *model.SubdistrictModel, e *entity.Subdistrict) error {
+	e.SubdisId = m.SubdisId
+	e.SubdisName = m.SubdisName
+	e.DisId = m.DisId
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/budimanlai/go-core/region/model"
 )
 
+//go:generate go run github.com/budimanlai/go-core/cmd/mappergen -entity github.com/budimanlai/go-core/region/domain/entity.Subdistrict -model github.com/budimanlai/go-core/region/model.SubdistrictModel
+
 type SubdistrictRepository interface {
 	base.BaseRepository[entity.Subdistrict, model.SubdistrictModel]
 }
@@ -0,0 +1,137 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// maxArgon2Memory and maxArgon2Iterations bound the cost read from a stored hash, so a crafted hash
+// cannot make a single login allocate gigabytes or spin for minutes
+const (
+	maxArgon2Memory     = 1024 * 1024 // KiB
+	maxArgon2Iterations = 64
+)
+
+// Argon2Params are the Argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params returns 64 MiB memory, 3 iterations and 2 lanes
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Argon2Hasher implements PasswordHasher using Argon2id,
+// hashes are encoded as $argon2id$v=19$m=65536,t=3,p=2$salt$key
+type Argon2Hasher struct {
+	params Argon2Params
+}
+
+// NewArgon2Hasher creates a new Argon2Hasher instance
+func NewArgon2Hasher(params Argon2Params) PasswordHasher {
+	return &Argon2Hasher{params: params}
+}
+
+// HashPassword generates an Argon2id hash with a random salt
+func (h *Argon2Hasher) HashPassword(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// CheckPasswordHash compares a password with its hash, using the parameters encoded in the hash
+func (h *Argon2Hasher) CheckPasswordHash(password, hash string) bool {
+	params, salt, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return false
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+// Matches reports whether the hash is an Argon2id hash
+func (h *Argon2Hasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+// NeedsRehash reports whether the hash was made with other parameters than the configured ones
+func (h *Argon2Hasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.params.Memory ||
+		params.Iterations != h.params.Iterations ||
+		params.Parallelism != h.params.Parallelism ||
+		uint32(len(salt)) != h.params.SaltLength ||
+		uint32(len(key)) != h.params.KeyLength
+}
+
+func decodeArgon2Hash(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, err
+	}
+	if params.Iterations < 1 || params.Iterations > maxArgon2Iterations {
+		return params, nil, nil, fmt.Errorf("invalid argon2 iterations %d", params.Iterations)
+	}
+	if params.Parallelism < 1 {
+		return params, nil, nil, fmt.Errorf("invalid argon2 parallelism %d", params.Parallelism)
+	}
+	if params.Memory > maxArgon2Memory {
+		return params, nil, nil, fmt.Errorf("invalid argon2 memory %d", params.Memory)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
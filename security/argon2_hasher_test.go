@@ -0,0 +1,65 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/budimanlai/go-core/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgon2Hasher(t *testing.T) {
+	hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+
+	hash, err := hasher.HashPassword("secret")
+	require.NoError(t, err)
+
+	assert.True(t, hasher.Matches(hash))
+	assert.True(t, hasher.CheckPasswordHash("secret", hash))
+	assert.False(t, hasher.CheckPasswordHash("other", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	stronger := security.NewArgon2Hasher(security.Argon2Params{Memory: 2048, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	assert.True(t, stronger.NeedsRehash(hash))
+	assert.True(t, stronger.CheckPasswordHash("secret", hash))
+}
+
+func TestArgon2Hasher_RejectsInvalidParams(t *testing.T) {
+	hasher := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	hash, err := hasher.HashPassword("secret")
+	require.NoError(t, err)
+
+	// the stored hash decides the cost of the check, out of range parameters are refused before hashing
+	for name, params := range map[string]string{
+		"no iterations":       "m=1024,t=0,p=1",
+		"too many iterations": "m=1024,t=1000000,p=1",
+		"no parallelism":      "m=1024,t=1,p=0",
+		"too much memory":     "m=4294967295,t=1,p=1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			forged := strings.Replace(hash, "m=1024,t=1,p=1", params, 1)
+			assert.False(t, hasher.CheckPasswordHash("secret", forged))
+			assert.True(t, hasher.NeedsRehash(forged))
+		})
+	}
+}
+
+func TestMultiHasher(t *testing.T) {
+	primary := security.NewArgon2Hasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	hasher := security.NewMultiHasher(primary, security.NewBcryptHasher())
+
+	hash, err := hasher.HashPassword("secret")
+	require.NoError(t, err)
+	assert.True(t, hasher.CheckPasswordHash("secret", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	// legacy bcrypt hashes are recognized and need rehash
+	legacy := "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+	assert.True(t, hasher.Matches(legacy))
+	assert.True(t, hasher.NeedsRehash(legacy))
+
+	// unknown formats never match
+	assert.False(t, hasher.Matches("plain"))
+	assert.False(t, hasher.CheckPasswordHash("plain", "plain"))
+}
@@ -0,0 +1,70 @@
+package security
+
+import (
+	"errors"
+	"strings"
+
+	pkgsecurity "github.com/budimanlai/go-pkg/security"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher defines password hashing interface
+type PasswordHasher interface {
+	HashPassword(password string) (string, error)
+	CheckPasswordHash(password, hash string) bool
+
+	// Matches reports whether the hash was produced by this hasher's algorithm
+	Matches(hash string) bool
+
+	// NeedsRehash reports whether the hash should be replaced on the next successful login
+	NeedsRehash(hash string) bool
+}
+
+// BcryptHasher implements PasswordHasher using go-pkg/security
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a new BcryptHasher instance
+func NewBcryptHasher() PasswordHasher {
+	return &BcryptHasher{}
+}
+
+// NewBcryptHasherWithCost creates a BcryptHasher with the given cost, hashes with a lower cost need rehash
+func NewBcryptHasherWithCost(cost int) PasswordHasher {
+	return &BcryptHasher{cost: cost}
+}
+
+// HashPassword generates a bcrypt hash from the given password
+func (h *BcryptHasher) HashPassword(password string) (string, error) {
+	if h.cost > 0 {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+		return string(hash), err
+	}
+
+	hash := pkgsecurity.HashPassword(password)
+	if hash == "" {
+		return "", errors.New("bcrypt: failed to hash password")
+	}
+	return hash, nil
+}
+
+// CheckPasswordHash compares a password with its hash
+func (h *BcryptHasher) CheckPasswordHash(password, hash string) bool {
+	valid, _ := pkgsecurity.CheckPasswordHash(password, hash)
+	return valid
+}
+
+// Matches reports whether the hash is a bcrypt hash
+func (h *BcryptHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// NeedsRehash reports whether the hash cost is lower than the configured cost
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	if h.cost == 0 {
+		return false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < h.cost
+}
@@ -0,0 +1,55 @@
+package security
+
+// MultiHasher hashes new passwords with the primary hasher and checks existing hashes
+// with the hasher matching their format, so several algorithms coexist during a migration
+type MultiHasher struct {
+	primary PasswordHasher
+	legacy  []PasswordHasher
+}
+
+// NewMultiHasher creates a MultiHasher, e.g. NewMultiHasher(NewArgon2Hasher(DefaultArgon2Params()), NewBcryptHasher())
+func NewMultiHasher(primary PasswordHasher, legacy ...PasswordHasher) PasswordHasher {
+	return &MultiHasher{
+		primary: primary,
+		legacy:  legacy,
+	}
+}
+
+// HashPassword hashes the password with the primary hasher
+func (h *MultiHasher) HashPassword(password string) (string, error) {
+	return h.primary.HashPassword(password)
+}
+
+// CheckPasswordHash compares a password with its hash, using the hasher matching the hash format
+func (h *MultiHasher) CheckPasswordHash(password, hash string) bool {
+	hasher := h.hasherFor(hash)
+	if hasher == nil {
+		return false
+	}
+	return hasher.CheckPasswordHash(password, hash)
+}
+
+// Matches reports whether any of the hashers knows the hash format
+func (h *MultiHasher) Matches(hash string) bool {
+	return h.hasherFor(hash) != nil
+}
+
+// NeedsRehash reports whether the hash is not a current primary hash
+func (h *MultiHasher) NeedsRehash(hash string) bool {
+	if !h.primary.Matches(hash) {
+		return true
+	}
+	return h.primary.NeedsRehash(hash)
+}
+
+func (h *MultiHasher) hasherFor(hash string) PasswordHasher {
+	if h.primary.Matches(hash) {
+		return h.primary
+	}
+	for _, hasher := range h.legacy {
+		if hasher.Matches(hash) {
+			return hasher
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type LocalStorageServiceImpl struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorageServiceImpl stores objects under dir, baseURL is where dir is served from
+func NewLocalStorageServiceImpl(dir, baseURL string) StorageService {
+	return &LocalStorageServiceImpl{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Put writes the object to a temporary file first so readers never see a partial file.
+func (s *LocalStorageServiceImpl) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+// Delete removes the object file.
+func (s *LocalStorageServiceImpl) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps the key to a file inside dir, keys escaping dir are rejected
+func (s *LocalStorageServiceImpl) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", errors.New("invalid storage key")
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
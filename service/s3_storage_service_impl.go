@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type S3StorageServiceConfig struct {
+	// Endpoint is the S3-compatible server, e.g. https://s3.ap-southeast-1.amazonaws.com or http://127.0.0.1:9000
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+
+	// PathStyle addresses the bucket in the path instead of the host name, most S3-compatible servers need it
+	PathStyle bool
+
+	// PublicURL is where the objects are read from (a CDN or public bucket URL), defaults to the object URL
+	PublicURL string
+}
+
+// S3StorageServiceImpl stores objects with plain HTTP requests signed with AWS Signature Version 4
+type S3StorageServiceImpl struct {
+	config     S3StorageServiceConfig
+	httpClient *http.Client
+}
+
+func NewS3StorageServiceImpl(config S3StorageServiceConfig, httpClient *http.Client) StorageService {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+	return &S3StorageServiceImpl{config: config, httpClient: httpClient}
+}
+
+// Put uploads the object with a PutObject request.
+func (s *S3StorageServiceImpl) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if err := s.do(req, data); err != nil {
+		return "", err
+	}
+
+	if s.config.PublicURL != "" {
+		return s.config.PublicURL + "/" + escapePath(key), nil
+	}
+	return objectURL, nil
+}
+
+// Delete removes the object with a DeleteObject request, S3 answers 204 for missing objects too.
+func (s *S3StorageServiceImpl) Delete(ctx context.Context, key string) error {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, objectURL, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, nil)
+}
+
+func (s *S3StorageServiceImpl) objectURL(key string) (string, error) {
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return "", fmt.Errorf("invalid s3 endpoint %q", s.config.Endpoint)
+	}
+
+	if s.config.PathStyle {
+		return fmt.Sprintf("%s://%s/%s/%s", endpoint.Scheme, endpoint.Host, s.config.Bucket, escapePath(key)), nil
+	}
+	return fmt.Sprintf("%s://%s.%s/%s", endpoint.Scheme, s.config.Bucket, endpoint.Host, escapePath(key)), nil
+}
+
+func (s *S3StorageServiceImpl) do(req *http.Request, payload []byte) error {
+	s.sign(req, payload, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 Authorization header, signing the host, content type and x-amz-* headers
+func (s *S3StorageServiceImpl) sign(req *http.Request, payload []byte, now time.Time) {
+	payloadHash := sha256.Sum256(payload)
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := now.Format("20060102") + "/" + s.config.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath escapes everything but the unreserved characters and the slashes, as the signature expects
+func escapePath(key string) string {
+	var out strings.Builder
+	for _, c := range []byte(key) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			out.WriteByte(c)
+		default:
+			fmt.Fprintf(&out, "%%%02X", c)
+		}
+	}
+	return out.String()
+}
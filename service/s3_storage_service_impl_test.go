@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/budimanlai/go-core/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a local stand-in for an S3-compatible server keeping the objects in memory
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") ||
+		!strings.Contains(auth, "/us-east-1/s3/aws4_request") ||
+		!strings.Contains(auth, "host;x-amz-content-sha256;x-amz-date, Signature=") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	sum := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.EscapedPath()] = string(body)
+		f.types[r.URL.EscapedPath()] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3StorageServiceImpl(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	storage := service.NewS3StorageServiceImpl(service.S3StorageServiceConfig{
+		Endpoint:  server.URL,
+		Region:    "us-east-1",
+		Bucket:    "avatars",
+		AccessKey: "access",
+		SecretKey: "secret",
+		PathStyle: true,
+	}, server.Client())
+
+	t.Run("Put And Delete", func(t *testing.T) {
+		url, err := storage.Put(context.Background(), "users/1/256 px.jpg", "image/jpeg", []byte("jpeg"))
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/avatars/users/1/256%20px.jpg", url)
+		assert.Equal(t, "jpeg", fake.objects["/avatars/users/1/256%20px.jpg"])
+		assert.Equal(t, "image/jpeg", fake.types["/avatars/users/1/256%20px.jpg"])
+
+		assert.NoError(t, storage.Delete(context.Background(), "users/1/256 px.jpg"))
+		assert.Empty(t, fake.objects)
+	})
+
+	t.Run("Public URL", func(t *testing.T) {
+		public := service.NewS3StorageServiceImpl(service.S3StorageServiceConfig{
+			Endpoint:  server.URL,
+			Region:    "us-east-1",
+			Bucket:    "avatars",
+			AccessKey: "access",
+			SecretKey: "secret",
+			PathStyle: true,
+			PublicURL: "https://cdn.example.com/",
+		}, server.Client())
+
+		url, err := public.Put(context.Background(), "users/1/64.jpg", "image/jpeg", []byte("jpeg"))
+		assert.NoError(t, err)
+		assert.Equal(t, "https://cdn.example.com/users/1/64.jpg", url)
+	})
+
+	t.Run("Server Error", func(t *testing.T) {
+		denied := service.NewS3StorageServiceImpl(service.S3StorageServiceConfig{
+			Endpoint:  server.URL,
+			Region:    "eu-west-1",
+			Bucket:    "avatars",
+			AccessKey: "access",
+			SecretKey: "secret",
+			PathStyle: true,
+		}, server.Client())
+
+		_, err := denied.Put(context.Background(), "users/1/64.jpg", "image/jpeg", []byte("jpeg"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,11 @@
+package service
+
+import "context"
+
+type StorageService interface {
+	// Put stores the object under the given key, replacing any existing one, and returns its public URL.
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+
+	// Delete removes the object, deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}